@@ -5,10 +5,17 @@ import (
 	"fmt"
 	"log"
 	"los-kmb-api/docs"
+	blindIndexScheduler "los-kmb-api/domain/blind_index/delivery/scheduler"
+	blindIndexRepository "los-kmb-api/domain/blind_index/repository"
+	blindIndexUsecase "los-kmb-api/domain/blind_index/usecase"
 	cacheRepository "los-kmb-api/domain/cache/repository"
+	cmsEventHandler "los-kmb-api/domain/cms/delivery/event"
 	cmsDelivery "los-kmb-api/domain/cms/delivery/http"
+	cmsScheduler "los-kmb-api/domain/cms/delivery/scheduler"
 	cmsRepository "los-kmb-api/domain/cms/repository"
 	cmsUsecase "los-kmb-api/domain/cms/usecase"
+	dsrDelivery "los-kmb-api/domain/dsr/delivery/http"
+	dsrUsecase "los-kmb-api/domain/dsr/usecase"
 	elaborateLTVDelivery "los-kmb-api/domain/elaborate_ltv/delivery/http"
 	elaborateLTVRepository "los-kmb-api/domain/elaborate_ltv/repository"
 	elaborateLTVUsecase "los-kmb-api/domain/elaborate_ltv/usecase"
@@ -16,15 +23,42 @@ import (
 	newKmbFilteringDelivery "los-kmb-api/domain/filtering_new/delivery/http"
 	newKmbFilteringRepository "los-kmb-api/domain/filtering_new/repository"
 	newKmbFilteringUsecase "los-kmb-api/domain/filtering_new/usecase"
+	kmbCheckpoint "los-kmb-api/domain/kmb/checkpoint"
 	eventHandler "los-kmb-api/domain/kmb/delivery/event"
 	kmbDelivery "los-kmb-api/domain/kmb/delivery/http"
 	kmbRepository "los-kmb-api/domain/kmb/repository"
 	kmbUsecase "los-kmb-api/domain/kmb/usecase"
+	lockSystemDelivery "los-kmb-api/domain/lock_system/delivery/http"
+	lockSystemScheduler "los-kmb-api/domain/lock_system/delivery/scheduler"
+	lockSystemRepository "los-kmb-api/domain/lock_system/repository"
+	lockSystemUsecase "los-kmb-api/domain/lock_system/usecase"
+	mappingCheckerCli "los-kmb-api/domain/mapping_checker/delivery/cli"
+	mappingCheckerDelivery "los-kmb-api/domain/mapping_checker/delivery/http"
+	mappingCheckerRepository "los-kmb-api/domain/mapping_checker/repository"
+	mappingCheckerUsecase "los-kmb-api/domain/mapping_checker/usecase"
+	notificationChannel "los-kmb-api/domain/notification/channel"
+	notificationRepository "los-kmb-api/domain/notification/repository"
+	notificationUsecase "los-kmb-api/domain/notification/usecase"
+	prescreeningRuleScheduler "los-kmb-api/domain/prescreening_rule/delivery/scheduler"
+	prescreeningRuleRepository "los-kmb-api/domain/prescreening_rule/repository"
+	prescreeningRuleUsecase "los-kmb-api/domain/prescreening_rule/usecase"
 	eventPrincipleHandler "los-kmb-api/domain/principle/delivery/event"
 	principleDelivery "los-kmb-api/domain/principle/delivery/http"
 	principleRepository "los-kmb-api/domain/principle/repository"
 	principleUsecase "los-kmb-api/domain/principle/usecase"
+	quotaDeviasiScheduler "los-kmb-api/domain/quota_deviasi/delivery/scheduler"
+	quotaDeviasiRepository "los-kmb-api/domain/quota_deviasi/repository"
+	quotaDeviasiUsecase "los-kmb-api/domain/quota_deviasi/usecase"
+	slaScheduler "los-kmb-api/domain/sla/delivery/scheduler"
+	slaRepository "los-kmb-api/domain/sla/repository"
+	slaUsecase "los-kmb-api/domain/sla/usecase"
+	stagingJobDelivery "los-kmb-api/domain/staging_job/delivery/http"
+	stagingJobRepository "los-kmb-api/domain/staging_job/repository"
+	stagingJobUsecase "los-kmb-api/domain/staging_job/usecase"
 	toolsDelivery "los-kmb-api/domain/tools/delivery/http"
+	workerScheduler "los-kmb-api/domain/worker/delivery/scheduler"
+	workerRepository "los-kmb-api/domain/worker/repository"
+	workerUsecase "los-kmb-api/domain/worker/usecase"
 	"los-kmb-api/middlewares"
 	"los-kmb-api/shared/authorization"
 	authRepository "los-kmb-api/shared/authorization/repository"
@@ -122,17 +156,25 @@ func main() {
 	constant.TOPIC_INSERT_CUSTOMER = os.Getenv("TOPIC_INSERT_CUSTOMER")
 	constant.TOPIC_SUBMISSION_PRINCIPLE = os.Getenv("TOPIC_SUBMISSION_PRINCIPLE")
 	constant.TOPIC_SUBMISSION_2WILEN = os.Getenv("TOPIC_SUBMISSION_2WILEN")
+	constant.TOPIC_UNLOCK = os.Getenv("TOPIC_UNLOCK")
+	constant.TOPIC_SLA_ESCALATION = os.Getenv("TOPIC_SLA_ESCALATION")
+	constant.TOPIC_MASTER_DATA = os.Getenv("TOPIC_MASTER_DATA")
 
 	//Platform Event key
 	constant.KEY_PREFIX_FILTERING = os.Getenv("KEY_PREFIX_FILTERING")
 	constant.KEY_PREFIX_UPDATE_STATUS_FILTERING = os.Getenv("KEY_PREFIX_UPDATE_STATUS_FILTERING")
 	constant.KEY_PREFIX_SUBMIT_TO_LOS = os.Getenv("KEY_PREFIX_SUBMIT_TO_LOS")
 	constant.KEY_PREFIX_AFTER_PRESCREENING = os.Getenv("KEY_PREFIX_AFTER_PRESCREENING")
+	constant.KEY_PREFIX_RESUME_JOURNEY = os.Getenv("KEY_PREFIX_RESUME_JOURNEY")
 	constant.KEY_PREFIX_CALLBACK = os.Getenv("KEY_PREFIX_CALLBACK")
 	constant.KEY_PREFIX_CALLBACK_GOLIVE = os.Getenv("KEY_PREFIX_CALLBACK_GOLIVE")
 	constant.KEY_PREFIX_UPDATE_CUSTOMER = os.Getenv("KEY_PREFIX_UPDATE_CUSTOMER")
 	constant.KEY_PREFIX_UPDATE_TRANSACTION_PRINCIPLE = os.Getenv("KEY_PREFIX_UPDATE_TRANSACTION_PRINCIPLE")
 	constant.KEY_PREFIX_CANCEL_ORDER_2WILEN = os.Getenv("KEY_PREFIX_CANCEL_ORDER_2WILEN")
+	constant.KEY_PREFIX_UNLOCK = os.Getenv("KEY_PREFIX_UNLOCK")
+	constant.KEY_PREFIX_SLA_ESCALATION = os.Getenv("KEY_PREFIX_SLA_ESCALATION")
+	constant.KEY_PREFIX_RELEASE_QUOTA_DEVIASI = os.Getenv("KEY_PREFIX_RELEASE_QUOTA_DEVIASI")
+	constant.KEY_PREFIX_MASTER_DATA = os.Getenv("KEY_PREFIX_MASTER_DATA")
 
 	kpLos, err := database.OpenKpLos()
 	if err != nil {
@@ -171,6 +213,15 @@ func main() {
 
 	common.SetDB(newKMB)
 
+	// define mapping checker domain
+	mappingCheckerRepo := mappingCheckerRepository.NewRepository(kpLos, newKMB)
+	mappingCheckerCase := mappingCheckerUsecase.NewUsecase(mappingCheckerRepo)
+
+	// cli subcommand: los-kmb-api check-mapping
+	if len(os.Args) > 1 && os.Args[1] == constant.CLI_CHECK_MAPPING {
+		os.Exit(mappingCheckerCli.Run(context.Background(), mappingCheckerCase, os.Stdout))
+	}
+
 	var cache *bigcache.BigCache
 	isCacheActive, _ := strconv.ParseBool(config.Env("CACHE_ACTIVE"))
 	if isCacheActive {
@@ -235,7 +286,25 @@ func main() {
 		log.Fatalf("Failed Init Producer event %s with Error : %s", constant.TOPIC_SUBMISSION_2WILEN, err.Error())
 	}
 
-	producer := platformevent.NewPlatformEvent(producerSubmission, producerSubmissionLOS, producerInsertCustomer, producerSubmission2Wilen)
+	// init producer topic unlock
+	producerUnlock, err := config.ProducerEvent(constant.TOPIC_UNLOCK, 3)
+	if err != nil {
+		log.Fatalf("Failed Init Producer event %s with Error : %s", constant.TOPIC_UNLOCK, err.Error())
+	}
+
+	// init producer topic sla escalation
+	producerSlaEscalation, err := config.ProducerEvent(constant.TOPIC_SLA_ESCALATION, 3)
+	if err != nil {
+		log.Fatalf("Failed Init Producer event %s with Error : %s", constant.TOPIC_SLA_ESCALATION, err.Error())
+	}
+
+	// init producer topic master data
+	producerMasterData, err := config.ProducerEvent(constant.TOPIC_MASTER_DATA, 3)
+	if err != nil {
+		log.Fatalf("Failed Init Producer event %s with Error : %s", constant.TOPIC_MASTER_DATA, err.Error())
+	}
+
+	producer := platformevent.NewPlatformEvent(producerSubmission, producerSubmissionLOS, producerInsertCustomer, producerSubmission2Wilen, producerUnlock, producerSlaEscalation, producerMasterData)
 	platformCache := platformcache.NewPlatformCache()
 
 	libResponse := response.NewResponse(os.Getenv("APP_PREFIX_NAME"), response.WithDebug(true))
@@ -260,18 +329,122 @@ func main() {
 	newElaborateLTVUsecase := elaborateLTVUsecase.NewUsecase(newElaborateLTVRepo, httpClient)
 	elaborateLTVDelivery.ElaborateHandler(apiGroupv3, newElaborateLTVUsecase, newElaborateLTVRepo, authorization, jsonResponse, accessToken, authPlatform)
 
+	// define workflow notification, channels are listed comma separated on NOTIFICATION_CHANNELS
+	notificationRepo := notificationRepository.NewRepository(newKMB)
+	notificationCase := notificationUsecase.NewUsecase(notificationRepo, notificationChannel.NewChannels(os.Getenv("NOTIFICATION_CHANNELS"), httpClient))
+
+	// define the standalone dsr calculation on the kmb repository, the kmb journey uses the same repository below
+	kmbRepositories := kmbRepository.NewRepository(kpLos, kpLosLogs, core, staging, newKMB, scorePro, mCache)
+	dsrUsecases := dsrUsecase.NewUsecase(kmbRepositories)
+	dsrDelivery.DsrHandler(apiGroupv3, dsrUsecases, jsonResponse, accessToken)
+
 	// define new kmb cms
 	cmsRepositories := cmsRepository.NewRepository(core, confins, newKMB, kpLos, kpLosLogs)
-	cmsUsecases := cmsUsecase.NewUsecase(cmsRepositories, httpClient, cacheRepository)
+	cmsUsecases := cmsUsecase.NewUsecase(cmsRepositories, httpClient, cacheRepository, notificationCase, dsrUsecases, producer)
 	cmsDelivery.CMSHandler(apiGroupv3, cmsUsecases, cmsRepositories, jsonResponse, producer, libResponse, accessToken)
 
+	// define mapping checker
+	mappingCheckerDelivery.MappingCheckerHandler(apiGroupv3, mappingCheckerCase, jsonResponse, accessToken)
+
+	// define lock system and the unlock scheduler
+	lockSystemRepo := lockSystemRepository.NewRepository(newKMB)
+	lockSystemCase := lockSystemUsecase.NewUsecase(lockSystemRepo, producer)
+	lockSystemDelivery.LockSystemHandler(apiGroupv3, lockSystemCase, jsonResponse, accessToken)
+
+	// the schedulers run on the replicas with SCHEDULER_ENABLED only
+	schedulerEnabled := os.Getenv("SCHEDULER_ENABLED") == "true"
+
+	unlockInterval, _ := strconv.Atoi(os.Getenv("UNLOCK_SCHEDULER_INTERVAL"))
+	if unlockInterval <= 0 {
+		unlockInterval = constant.UNLOCK_SCHEDULER_INTERVAL
+	}
+	if schedulerEnabled {
+		go lockSystemScheduler.Run(ctx, lockSystemCase, time.Duration(unlockInterval)*time.Minute)
+	}
+
+	// define sla aging scheduler
+	slaRepo := slaRepository.NewRepository(kpLos, newKMB)
+	slaCase := slaUsecase.NewUsecase(slaRepo, producer, notificationCase)
+
+	slaInterval, _ := strconv.Atoi(os.Getenv("SLA_SCHEDULER_INTERVAL"))
+	if slaInterval <= 0 {
+		slaInterval = constant.SLA_SCHEDULER_INTERVAL
+	}
+	if schedulerEnabled {
+		go slaScheduler.Run(ctx, slaCase, time.Duration(slaInterval)*time.Minute)
+	}
+
+	// define prescreening auto decision scheduler. Polling trx_status instead of triggering from the insert picks up the
+	// orders whichever writer moved them to prescreening, and an order waiting for the next tick sits in the manual
+	// review queue meanwhile, so the only cost of the delay is at most one interval
+	prescreeningRuleRepo := prescreeningRuleRepository.NewRepository(kpLos, newKMB)
+	prescreeningRuleCase := prescreeningRuleUsecase.NewUsecase(prescreeningRuleRepo, producer)
+
+	prescreeningRuleInterval, _ := strconv.Atoi(os.Getenv("PRESCREENING_RULE_SCHEDULER_INTERVAL"))
+	if prescreeningRuleInterval <= 0 {
+		prescreeningRuleInterval = constant.PRESCREENING_RULE_SCHEDULER_INTERVAL
+	}
+	if schedulerEnabled {
+		go prescreeningRuleScheduler.Run(ctx, prescreeningRuleCase, time.Duration(prescreeningRuleInterval)*time.Minute)
+	}
+
+	// define quota deviasi period reset scheduler
+	quotaDeviasiRepo := quotaDeviasiRepository.NewRepository(kpLos, newKMB)
+	quotaDeviasiCase := quotaDeviasiUsecase.NewUsecase(quotaDeviasiRepo)
+
+	quotaResetInterval, _ := strconv.Atoi(os.Getenv("QUOTA_RESET_SCHEDULER_INTERVAL"))
+	if quotaResetInterval <= 0 {
+		quotaResetInterval = constant.QUOTA_RESET_SCHEDULER_INTERVAL
+	}
+	if schedulerEnabled {
+		go quotaDeviasiScheduler.Run(ctx, quotaDeviasiCase, time.Duration(quotaResetInterval)*time.Minute)
+	}
+
+	// define master data history sync, copies the history a save could not copy to los right away
+	masterDataHistoryInterval, _ := strconv.Atoi(os.Getenv("MASTER_DATA_HISTORY_SCHEDULER_INTERVAL"))
+	if masterDataHistoryInterval <= 0 {
+		masterDataHistoryInterval = constant.MASTER_DATA_HISTORY_SCHEDULER_INTERVAL
+	}
+	if schedulerEnabled {
+		go cmsScheduler.Run(ctx, cmsUsecases, time.Duration(masterDataHistoryInterval)*time.Minute)
+	}
+
+	// define trx_worker executor, disabled while the external worker still processes the table
+	if os.Getenv("WORKER_EXECUTOR_ENABLED") == "true" {
+		workerRepo := workerRepository.NewRepository(kpLos)
+		workerCase := workerUsecase.NewUsecase(workerRepo, httpClient)
+
+		workerInterval, _ := strconv.Atoi(os.Getenv("WORKER_SCHEDULER_INTERVAL"))
+		if workerInterval <= 0 {
+			workerInterval = constant.WORKER_SCHEDULER_INTERVAL
+		}
+		go workerScheduler.Run(ctx, workerCase, time.Duration(workerInterval)*time.Second)
+	}
+
+	// define blind index backfill of trx_customer_personal, the journey indexes new orders itself
+	if os.Getenv("BLIND_INDEX_KEY") != "" && schedulerEnabled {
+		blindIndexRepo := blindIndexRepository.NewRepository(newKMB)
+		blindIndexCase := blindIndexUsecase.NewUsecase(blindIndexRepo)
+
+		blindIndexInterval, _ := strconv.Atoi(os.Getenv("BLIND_INDEX_SCHEDULER_INTERVAL"))
+		if blindIndexInterval <= 0 {
+			blindIndexInterval = constant.BLIND_INDEX_SCHEDULER_INTERVAL
+		}
+		go blindIndexScheduler.Run(ctx, blindIndexCase, time.Duration(blindIndexInterval)*time.Minute)
+	}
+
 	// define new kmb journey
-	kmbRepositories := kmbRepository.NewRepository(kpLos, kpLosLogs, core, staging, newKMB, scorePro, mCache)
 	kmbUsecases := kmbUsecase.NewUsecase(kmbRepositories, httpClient)
 	kmbMultiUsecases := kmbUsecase.NewMultiUsecase(kmbRepositories, httpClient, kmbUsecases)
-	kmbMetrics := kmbUsecase.NewMetrics(kmbRepositories, httpClient, kmbUsecases, kmbMultiUsecases)
+	kmbCheckpointCase := kmbCheckpoint.NewUsecase(kmbRepositories, kmbUsecases)
+	kmbCheckpointMultiCase := kmbCheckpoint.NewMultiUsecase(kmbRepositories, kmbMultiUsecases)
+	kmbMetrics := kmbUsecase.NewMetrics(kmbRepositories, httpClient, kmbCheckpointCase, kmbCheckpointMultiCase)
 	kmbDelivery.KMBHandler(apiGroupv3, kmbMetrics, kmbUsecases, kmbRepositories, authPlatform, authorization, jsonResponse, accessToken, producer)
 
+	stagingJobRepo := stagingJobRepository.NewRepository(newKMB, staging)
+	stagingJobCase := stagingJobUsecase.NewUsecase(stagingJobRepo, kmbRepositories)
+	stagingJobDelivery.StagingJobHandler(apiGroupv3, stagingJobCase, jsonResponse, accessToken)
+
 	managers := manager.New(platformlog.GetPlatformEnv(), os.Getenv("PLATFORM_SECRET_KEY"), os.Getenv("PLATFORM_AUTH_BASE_URL")+"/v1/auth/login")
 
 	libLog := loslog.NewConfig(
@@ -344,6 +517,16 @@ func main() {
 		panic(err)
 	}
 
+	// every replica caches the industry types itself, so each one consumes the master data changes in a group of its own
+	hostname, _ := os.Hostname()
+	consumerMasterDataRouter := platformevent.NewConsumerRouter(constant.TOPIC_MASTER_DATA, os.Getenv("LOS_MASTER_DATA")+"-"+hostname, auth)
+
+	cmsEventHandler.NewServiceMasterData(consumerMasterDataRouter, cmsUsecases)
+
+	if err := consumerMasterDataRouter.StartConsume(); err != nil {
+		panic(err)
+	}
+
 	consumerPrincipleRouter := platformevent.NewConsumerRouter(constant.TOPIC_SUBMISSION_PRINCIPLE, os.Getenv("LOS_SUBMISSION_PRINCIPLE"), auth)
 
 	consumerPrincipleRouter.Use(func(next event.ConsumerProcessor) event.ConsumerProcessor {
@@ -359,7 +542,7 @@ func main() {
 		}
 	})
 
-	eventPrincipleHandler.NewServicePrinciple(consumerPrincipleRouter, principleRepo, principleCase, validator, producer, jsonResponse)
+	eventPrincipleHandler.NewServicePrinciple(consumerPrincipleRouter, principleRepo, principleCase, validator, producer, jsonResponse, cmsUsecases)
 
 	if err := consumerPrincipleRouter.StartConsumeWithoutTimestamp(); err != nil {
 		panic(err)
@@ -380,7 +563,7 @@ func main() {
 		}
 	})
 
-	eventPrincipleHandler.NewService2Wilen(consumer2WilenRouter, principleRepo, principleCase, validator, producer, jsonResponse)
+	eventPrincipleHandler.NewService2Wilen(consumer2WilenRouter, principleRepo, principleCase, validator, producer, jsonResponse, cmsUsecases)
 
 	if err := consumer2WilenRouter.StartConsumeProspectIDWithoutTimestamp(); err != nil {
 		panic(err)
@@ -0,0 +1,28 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/blind_index/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+	"time"
+)
+
+// Run backfills the blind indexes every interval until ctx is done, a full batch is followed by the next one right away
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "BLIND_INDEX_SCHEDULER",
+		MsgLogFile: "LOS - Blind Index Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+		SkipIdle:   true,
+		Rerun: func(result map[string]interface{}, err error) bool {
+			return err == nil && result["indexed"] == constant.BLIND_INDEX_BACKFILL_BATCH_SIZE
+		},
+	}, func(ctx context.Context, _ string) (map[string]interface{}, error) {
+		indexed, err := usecase.BackfillBlindIndex(ctx)
+		return map[string]interface{}{"indexed": indexed}, err
+	})
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/utils"
+)
+
+type Repository interface {
+	GetUnindexedCustomers(limit int) (data []entity.CustomerBlindIndexSource, err error)
+	SaveCustomerBlindIndex(prospectID string, index utils.CustomerBlindIndex) (err error)
+}
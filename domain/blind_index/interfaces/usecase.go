@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"context"
+)
+
+type Usecase interface {
+	BackfillBlindIndex(ctx context.Context) (indexed int, err error)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"los-kmb-api/domain/blind_index/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	newKmb *gorm.DB
+}
+
+func NewRepository(newKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		newKmb: newKmb,
+	}
+}
+
+// GetUnindexedCustomers returns the decrypted search fields of the orders without blind index yet, or indexed by an older version
+func (r repoHandler) GetUnindexedCustomers(limit int) (data []entity.CustomerBlindIndexSource, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.newKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT TOP (?) ProspectID, SCP.dbo.DEC_B64('SEC', IDNumber) AS IDNumber, SCP.dbo.DEC_B64('SEC', LegalName) AS LegalName,
+		SCP.dbo.DEC_B64('SEC', MobilePhone) AS MobilePhone
+		FROM trx_customer_personal WITH (nolock) WHERE bidx_at IS NULL OR ISNULL(bidx_version, 1) < ? ORDER BY ProspectID`, limit, constant.BLIND_INDEX_VERSION).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) SaveCustomerBlindIndex(prospectID string, index utils.CustomerBlindIndex) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.newKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = utils.SaveCustomerBlindIndex(db, prospectID, index)
+
+	return
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/domain/blind_index/interfaces"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+)
+
+type usecase struct {
+	repository interfaces.Repository
+}
+
+func NewUsecase(repository interfaces.Repository) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+	}
+}
+
+// BackfillBlindIndex indexes a batch of orders created before the blind index existed, an order that fails is retried on the next run
+func (u usecase) BackfillBlindIndex(ctx context.Context) (indexed int, err error) {
+
+	customers, err := u.repository.GetUnindexedCustomers(constant.BLIND_INDEX_BACKFILL_BATCH_SIZE)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Unindexed Customers error")
+		return
+	}
+
+	for _, customer := range customers {
+
+		index := utils.NewCustomerBlindIndex(customer.IDNumber, customer.LegalName, customer.MobilePhone)
+
+		if errSave := u.repository.SaveCustomerBlindIndex(customer.ProspectID, index); errSave != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Save Customer Blind Index error")
+			continue
+		}
+
+		indexed++
+	}
+
+	return
+}
@@ -0,0 +1,47 @@
+package eventhandlers
+
+import (
+	"context"
+	"los-kmb-api/domain/cms/interfaces"
+	"los-kmb-api/shared/common/platformevent"
+	"los-kmb-api/shared/constant"
+
+	"github.com/KB-FMF/platform-library/event"
+	jsoniter "github.com/json-iterator/go"
+)
+
+type handlers struct {
+	usecase interfaces.Usecase
+}
+
+// NewServiceMasterData consumes the master data changes, the router needs a consumer group of its own per replica so
+// every replica receives every change
+func NewServiceMasterData(app *platformevent.ConsumerRouter, usecase interfaces.Usecase) {
+	handler := handlers{
+		usecase: usecase,
+	}
+	app.Handle(constant.KEY_PREFIX_MASTER_DATA, handler.MasterDataChanged)
+}
+
+// event master data changed, refreshes the cached industry type descriptions of this replica
+func (h handlers) MasterDataChanged(ctx context.Context, event event.Event) (err error) {
+
+	var changed struct {
+		Type string            `json:"type"`
+		Data map[string]string `json:"data"`
+	}
+
+	if err = jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(event.GetBody(), &changed); err != nil {
+		return nil
+	}
+
+	if changed.Type != constant.MASTER_DATA_INDUSTRY_TYPE {
+		return nil
+	}
+
+	for id, description := range changed.Data {
+		h.usecase.RefreshIndustryType(id, description)
+	}
+
+	return nil
+}
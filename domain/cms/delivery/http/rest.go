@@ -50,36 +50,74 @@ func CMSHandler(cmsroute *echo.Group, usecase interfaces.Usecase, repository int
 	cmsroute.GET("/cms/ca/inquiry/:prospect_id", handler.CaDetailOrder, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/ca/save-as-draft", handler.SaveAsDraft, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/ca/submit-decision", handler.SubmitDecision, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ca/bulk-submit-decision", handler.BulkSubmitDecision, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ca/claim", handler.ClaimOrder, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ca/release", handler.ReleaseOrder, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ca/force-release", handler.ForceReleaseOrder, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/ca/claim/:prospect_id", handler.GetOrderClaim, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/akkk/view/:prospect_id", handler.GetAkkk, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/ca/cancel", handler.CancelOrder, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/ca/cancel-reason", handler.CancelReason, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/ca/return", handler.ReturnOrder, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/ca/recalculate", handler.RecalculateOrder, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/ca/recalculate/history/:prospect_id", handler.RecalculateHistory, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/search", handler.SearchInquiry, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/approval/inquiry", handler.ApprovalInquiry, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/approval/inquiry/:prospect_id/:alias", handler.ApprovalDetailOrder, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/approval/reason", handler.ApprovalReason, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/approval/submit-approval", handler.SubmitApproval, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/approval/bulk-submit-approval", handler.BulkSubmitApproval, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/get-list-branch", handler.GetListBranch, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/form-akkk", handler.GenerateFormAKKK, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/ne/submit", handler.SubmitNE, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/ne/inquiry", handler.NEInquiry, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/ne/inquiry/:prospect_id", handler.NEInquiryDetail, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/ne/check_license_plate", handler.CheckLicensePlate, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ne/draft/save", handler.SaveNEDraft, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ne/draft/update", handler.UpdateNEDraft, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/ne/draft/inquiry", handler.NEDraftInquiry, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/ne/draft/:draft_id", handler.ResumeNEDraft, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ne/draft/discard", handler.DiscardNEDraft, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/ne/precheck", handler.PrecheckNE, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/mapping-cluster/inquiry", handler.MappingClusterInquiry, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/mapping-cluster/download", handler.DownloadMappingCluster, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/mapping-cluster/upload", handler.UploadMappingCluster, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/mapping-cluster/upload/preview", handler.UploadMappingClusterPreview, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/mapping-cluster/upload/confirm", handler.UploadMappingClusterConfirm, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/mapping-cluster/branch", handler.MappingClusterBranch, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/mapping-cluster/change-log", handler.MappingClusterChangeLog, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/mapping-cluster/versions", handler.MappingClusterVersions, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/mapping-cluster/versions/diff", handler.MappingClusterVersionDiff, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/mapping-cluster/rollback", handler.MappingClusterRollback, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/worker/inquiry", handler.WorkerInquiry, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/worker/requeue", handler.WorkerRequeue, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/notification/preference", handler.NotificationPreference, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/notification/preference", handler.SaveNotificationPreference, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/dashboard/stats", handler.DashboardStats, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/master-data/inquiry", handler.MasterDataInquiry, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/master-data/create", handler.CreateMasterData, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/master-data/update", handler.UpdateMasterData, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/master-data/delete", handler.DeleteMasterData, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/master-data/reorder", handler.ReorderMasterData, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/master-data/history", handler.MasterDataHistory, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/quota-deviasi/inquiry", handler.QuotaDeviasiInquiry, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/quota-deviasi/consumption", handler.QuotaDeviasiConsumption, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/quota-deviasi/branch", handler.QuotaDeviasiBranch, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/quota-deviasi/update", handler.QuotaDeviasiUpdate, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/quota-deviasi/download", handler.QuotaDeviasiDownload, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/quota-deviasi/upload", handler.QuotaDeviasiUpload, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/quota-deviasi/upload/preview", handler.QuotaDeviasiUploadPreview, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/quota-deviasi/upload/confirm", handler.QuotaDeviasiUploadConfirm, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/quota-deviasi/reset-all", handler.QuotaDeviasiResetAll, middlewares.AccessMiddleware())
 	cmsroute.POST("/cms/quota-deviasi/reset", handler.QuotaDeviasiResetBranch, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/list-order/inquiry", handler.ListOrderInquiry, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/list-order/inquiry/:prospect_id", handler.ListOrderDetail, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/lock-system/inquiry", handler.LockSystemInquiry, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/lock-system/update", handler.LockSystemUpdate, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/lock-system/history/:lock_type/:prospect_id", handler.LockSystemHistory, middlewares.AccessMiddleware())
+	cmsroute.POST("/cms/export", handler.CreateExportJob, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/export/:id", handler.GetExportJob, middlewares.AccessMiddleware())
+	cmsroute.GET("/cms/export/:id/download", handler.DownloadExportJob, middlewares.AccessMiddleware())
 	cmsroute.GET("/cms/get-token", handler.GetToken, middlewares.AccessMiddleware())
 }
 
@@ -395,11 +433,12 @@ func (c *handlerCMS) CaInquiry(ctx echo.Context) (err error) {
 		BranchID:     ctx.QueryParam("branch_id"),
 		MultiBranch:  ctx.QueryParam("multi_branch"),
 		UserID:       ctx.QueryParam("user_id"),
+		AgingFilter:  ctx.QueryParam("aging_filter"),
 	}
 
 	token := ctx.Request().Header.Get(constant.HEADER_AUTHORIZATION)
 
-	err = platformauth.PlatformVerify(token)
+	session, err := platformauth.PlatformSession(token)
 	if err != nil {
 		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Inquiry", req, err)
 	}
@@ -414,6 +453,8 @@ func (c *handlerCMS) CaInquiry(ctx echo.Context) (err error) {
 		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Inquiry", err)
 	}
 
+	req.Session = request.PIISession{UserID: session.UserID, Role: session.Role}
+
 	if err := ctx.Validate(&req); err != nil {
 		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Inquiry", req, err)
 	}
@@ -684,6 +725,174 @@ func (c *handlerCMS) SubmitDecision(ctx echo.Context) (err error) {
 	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Submit Decision", req, data)
 }
 
+// CMS NEW KMB Tools godoc
+// @Description Api CA, submit one decision to many orders, a failed order does not cancel the others
+// @Tags CA
+// @Produce json
+// @Param body body request.ReqBulkSubmitDecision true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.BulkSubmitResponse}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ca/bulk-submit-decision [post]
+func (c *handlerCMS) BulkSubmitDecision(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqBulkSubmitDecision
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Bulk Submit Decision", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Bulk Submit Decision", req, err)
+	}
+
+	data, err := c.usecase.BulkSubmitDecision(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Bulk Submit Decision", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Bulk Submit Decision", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api CA, claim an order for exclusive review, the claim expires after inactivity
+// @Tags CA
+// @Produce json
+// @Param body body request.ReqClaimOrder true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.OrderClaim}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ca/claim [post]
+func (c *handlerCMS) ClaimOrder(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqClaimOrder
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Claim Order", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Claim Order", req, err)
+	}
+
+	data, err := c.usecase.ClaimOrder(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Claim Order", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Claim Order", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api CA, release an order claimed by the user
+// @Tags CA
+// @Produce json
+// @Param body body request.ReqReleaseOrder true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ca/release [post]
+func (c *handlerCMS) ReleaseOrder(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqReleaseOrder
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Release Order", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Release Order", req, err)
+	}
+
+	err = c.usecase.ReleaseOrder(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Release Order", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Release Order", req, nil)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api CA, supervisor releases the claim of another user
+// @Tags CA
+// @Produce json
+// @Param body body request.ReqForceReleaseOrder true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ca/force-release [post]
+func (c *handlerCMS) ForceReleaseOrder(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqForceReleaseOrder
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Force Release Order", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Force Release Order", req, err)
+	}
+
+	session, err := platformauth.PlatformSession(ctx.Request().Header.Get(constant.HEADER_AUTHORIZATION))
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Force Release Order", req, err)
+	}
+
+	if session.UserID != req.ReleasedBy {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - released_by tidak sesuai dengan user login")
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Force Release Order", req, err)
+	}
+
+	req.RoleAlias = session.Role
+
+	err = c.usecase.ForceReleaseOrder(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Force Release Order", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Force Release Order", req, nil)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api CA, current holder of an order claim
+// @Tags CA
+// @Produce json
+// @Param prospect_id path string true "Prospect ID"
+// @Success 200 {object} response.ApiResponse{data=response.OrderClaim}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ca/claim/{prospect_id} [get]
+func (c *handlerCMS) GetOrderClaim(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	prospectID := ctx.Param("prospect_id")
+
+	data, err := c.usecase.GetOrderClaim(prospectID)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Get Order Claim", prospectID, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Get Order Claim", prospectID, data)
+}
+
 // CMS NEW KMB Tools godoc
 // @Description Api Search Inquiry
 // @Tags Search Inquiry
@@ -708,6 +917,11 @@ func (c *handlerCMS) SearchInquiry(ctx echo.Context) (err error) {
 		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Search Inquiry", err)
 	}
 
+	// a caller without a valid cms session gets the personal data masked
+	if session, errSession := platformauth.PlatformSession(ctx.Request().Header.Get(constant.HEADER_AUTHORIZATION)); errSession == nil {
+		req.Session = request.PIISession{UserID: session.UserID, Role: session.Role}
+	}
+
 	if err := ctx.Validate(&req); err != nil {
 		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Search Inquiry", req, err)
 	}
@@ -758,14 +972,28 @@ func (c *handlerCMS) GetAkkk(ctx echo.Context) (err error) {
 		return ctxJson
 	}
 
-	data, err := c.usecase.GetAkkk(prospectID)
+	req := request.ReqViewAkkk{
+		ProspectID: prospectID,
+	}
+
+	// a caller without a valid cms session gets the personal data masked
+	if session, errSession := platformauth.PlatformSession(ctx.Request().Header.Get(constant.HEADER_AUTHORIZATION)); errSession == nil {
+		req.Session = request.PIISession{UserID: session.UserID, Role: session.Role}
+	}
+
+	if err = ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB AKKK", req, err)
+		return ctxJson
+	}
+
+	data, err := c.usecase.GetAkkkView(req)
 
 	if err != nil {
-		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB AKKK", prospectID, err)
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB AKKK", req, err)
 		return ctxJson
 	}
 
-	ctxJson, _ = c.Json.SuccessV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB AKKK", prospectID, data)
+	ctxJson, _ = c.Json.SuccessV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB AKKK", req, data)
 	return ctxJson
 }
 
@@ -774,6 +1002,7 @@ func (c *handlerCMS) GetAkkk(ctx echo.Context) (err error) {
 // @Tags Submit NE
 // @Produce json
 // @Param body body request.MetricsNE true "Body payload"
+// @Param draft_id query string false "draft_id"
 // @Success 200 {object} response.ApiResponse{data=response.ApiResponse}
 // @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
 // @Failure 500 {object} response.ApiResponse{}
@@ -821,6 +1050,11 @@ func (c *handlerCMS) SubmitNE(ctx echo.Context) (err error) {
 	//produce filtering for NE
 	c.producer.PublishEvent(ctx.Request().Context(), middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION, constant.KEY_PREFIX_FILTERING, req.Transaction.ProspectID, utils.StructToMap(payloadFiltering), 0)
 
+	// the order was submitted from a draft, the draft is no longer resumable
+	if draftID := ctx.QueryParam("draft_id"); draftID != "" {
+		c.usecase.CompleteNEDraft(ctx.Request().Context(), request.ReqNEDraft{ID: draftID, CreatedBy: req.CreatedBy.CreatedByID})
+	}
+
 	ctxJson, resp = c.Json.SuccessV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Submit NE Success", req, nil)
 
 	return ctxJson
@@ -1099,6 +1333,30 @@ func (c *handlerCMS) RecalculateOrder(ctx echo.Context) (err error) {
 	return ctxJson
 }
 
+// CMS NEW KMB Tools godoc
+// @Description Api CA, recalculation history compared to the original submission
+// @Tags CA
+// @Produce json
+// @Param prospect_id path string true "Prospect ID"
+// @Success 200 {object} response.ApiResponse{data=response.RecalculateHistory}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ca/recalculate/history/{prospect_id} [get]
+func (c *handlerCMS) RecalculateHistory(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	prospectID := ctx.Param("prospect_id")
+
+	data, err := c.usecase.GetRecalculateHistory(ctx.Request().Context(), prospectID)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Recalculate History", prospectID, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - CA Recalculate History", prospectID, data)
+}
+
 // CMS NEW KMB Tools godoc
 // @Description Api Credit Approval
 // @Tags Credit Approval
@@ -1121,11 +1379,12 @@ func (c *handlerCMS) ApprovalInquiry(ctx echo.Context) (err error) {
 		MultiBranch:  ctx.QueryParam("multi_branch"),
 		UserID:       ctx.QueryParam("user_id"),
 		Alias:        ctx.QueryParam("alias"),
+		AgingFilter:  ctx.QueryParam("aging_filter"),
 	}
 
 	token := ctx.Request().Header.Get(constant.HEADER_AUTHORIZATION)
 
-	err = platformauth.PlatformVerify(token)
+	session, err := platformauth.PlatformSession(token)
 	if err != nil {
 		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Inquiry", req, err)
 	}
@@ -1134,6 +1393,8 @@ func (c *handlerCMS) ApprovalInquiry(ctx echo.Context) (err error) {
 		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Inquiry", err)
 	}
 
+	req.Session = request.PIISession{UserID: session.UserID, Role: session.Role}
+
 	if err := ctx.Validate(&req); err != nil {
 		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Inquiry", req, err)
 	}
@@ -1346,6 +1607,80 @@ func (c *handlerCMS) SubmitApproval(ctx echo.Context) (err error) {
 	return ctxJson
 }
 
+// CMS NEW KMB Tools godoc
+// @Description Api Credit Approval, submit one approval to many orders, a failed order does not cancel the others
+// @Tags Credit Approval
+// @Produce json
+// @Param body body request.ReqBulkSubmitApproval true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.BulkSubmitResponse}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/approval/bulk-submit-approval [post]
+func (c *handlerCMS) BulkSubmitApproval(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqBulkSubmitApproval
+		data        response.BulkSubmitResponse
+		ctxJson     error
+	)
+
+	// Save Log Orchestrator per order
+	defer func() {
+		headers := map[string]string{constant.HeaderXRequestID: ctx.Get(constant.HeaderXRequestID).(string)}
+		for _, item := range data.Items {
+			c.repository.SaveLogOrchestrator(headers, req, item, "/api/v3/kmb/cms/approval/bulk-submit-approval", constant.METHOD_POST, item.ProspectID, ctx.Get(constant.HeaderXRequestID).(string))
+		}
+	}()
+
+	if err := ctx.Bind(&req); err != nil {
+		ctxJson, _ = c.Json.InternalServerErrorCustomV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Bulk Submit Decision", err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Bulk Submit Decision", req, err)
+		return ctxJson
+	}
+
+	data, err = c.usecase.BulkSubmitApproval(ctx.Request().Context(), req)
+
+	if err != nil {
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Bulk Submit Decision", req, err)
+		return ctxJson
+	}
+
+	ctxJson, _ = c.Json.SuccessV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Approval Bulk Submit Decision", req, data)
+
+	for _, item := range data.Items {
+		approval, ok := item.Data.(response.ApprovalResponse)
+		if !ok || !approval.IsFinal || approval.NeedEscalation || approval.Decision == constant.DECISION_RETURN {
+			continue
+		}
+
+		metrics := response.Metrics{
+			ProspectID:     approval.ProspectID,
+			Code:           approval.Code,
+			Decision:       approval.Decision,
+			DecisionReason: approval.Reason,
+		}
+
+		responseEvent := c.Json.EventSuccess(ctx.Request().Context(), accessToken, constant.NEW_KMB_LOG, "LOS - Approval Bulk Submit Decision", req, metrics)
+
+		// generate form akkk
+		reqGenAkkk := request.RequestGenerateFormAKKK{
+			ProspectID: approval.ProspectID,
+			LOB:        strings.ToLower(constant.LOB_NEW_KMB),
+			Source:     constant.SYSTEM,
+		}
+		c.usecase.GenerateFormAKKK(ctx.Request().Context(), reqGenAkkk, accessToken)
+
+		c.producer.PublishEvent(ctx.Request().Context(), accessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_CALLBACK, approval.ProspectID, utils.StructToMap(responseEvent), 0)
+	}
+
+	return ctxJson
+}
+
 // CMS NEW KMB Tools godoc
 // @Description Api Generate Form AKKK
 // @Tags Generate Form AKKK
@@ -1444,6 +1779,47 @@ func (c *handlerCMS) QuotaDeviasiInquiry(ctx echo.Context) (err error) {
 	})
 }
 
+// CMS NEW KMB Tools godoc
+// @Description Api Setting Quota Deviasi, booking and release of the ledger per branch per period
+// @Tags Setting Quota Deviasi
+// @Produce json
+// @Param branch_id query string false "branch_id"
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/quota-deviasi/consumption [get]
+func (c *handlerCMS) QuotaDeviasiConsumption(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqListQuotaDeviasiConsumption{
+		BranchID: ctx.QueryParam("branch_id"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	data, rowTotal, err := c.usecase.GetQuotaDeviasiConsumption(req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Kuota Deviasi Consumption", req, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Kuota Deviasi Consumption", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Kuota Deviasi Consumption", req, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
 // CMS NEW KMB Tools godoc
 // @Description Api Setting Quota Deviasi
 // @Tags Setting Quota Deviasi
@@ -1560,46 +1936,129 @@ func (c *handlerCMS) QuotaDeviasiUpload(ctx echo.Context) (err error) {
 }
 
 // CMS NEW KMB Tools godoc
-// @Description Api Setting Quota Deviasi
+// @Description Api Preview Upload Quota Deviasi
 // @Tags Setting Quota Deviasi
 // @Produce json
-// @Param body body request.ReqUpdateQuotaDeviasi true "Body payload"
-// @Success 200 {object} response.ApiResponse{data=response.ApiResponse}
+// @Param excel_file formData file true "upload file"
+// @Param updated_by_name formData string true "updated by name"
+// @Success 200 {object} response.ApiResponse{data=response.UploadPreview}
 // @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
 // @Failure 500 {object} response.ApiResponse{}
-// @Router /api/v3/kmb/cms/quota-deviasi/update [post]
-func (c *handlerCMS) QuotaDeviasiUpdate(ctx echo.Context) (err error) {
+// @Router /api/v3/kmb/cms/quota-deviasi/upload/preview [post]
+func (c *handlerCMS) QuotaDeviasiUploadPreview(ctx echo.Context) (err error) {
 
 	var (
 		accessToken = middlewares.UserInfoData.AccessToken
-		req         request.ReqUpdateQuotaDeviasi
-		ctxJson     error
+		req         request.ReqUploadSettingQuotaDeviasi
 	)
 
 	if err := ctx.Bind(&req); err != nil {
-		ctxJson, _ = c.Json.InternalServerErrorCustomV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi", err)
-		return ctxJson
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi", err)
 	}
 
 	if err := ctx.Validate(&req); err != nil {
-		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi - Input Tidak Valid", req, err)
-		return ctxJson
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi", req, err)
 	}
 
-	data, err := c.usecase.UpdateQuotaDeviasiBranch(ctx.Request().Context(), req)
+	file, err := ctx.FormFile("excel_file")
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi", nil, errors.New(constant.ERROR_BAD_REQUEST+" - Silakan unggah file excel yang valid"))
+	}
 
+	src, err := file.Open()
 	if err != nil {
-		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi", req, err)
-		return ctxJson
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi", nil, errors.New(constant.ERROR_BAD_REQUEST+" - Silakan unggah file excel yang valid"))
 	}
+	defer src.Close()
 
-	ctxJson, _ = c.Json.SuccessV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi - Success", req, data)
-	return ctxJson
-}
+	mime := file.Header.Get("Content-Type")
+	if mime != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi", nil, errors.New(constant.ERROR_BAD_REQUEST+" - Silakan unggah file berformat .xlsx"))
+	}
 
-// CMS NEW KMB Tools godoc
-// @Description Api Setting Quota Deviasi
-// @Tags Setting Quota Deviasi
+	data, err := c.usecase.PreviewUploadQuotaDeviasi(req, src)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi", nil, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Upload Setting Kuota Deviasi Success", nil, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Confirm Upload Quota Deviasi
+// @Tags Setting Quota Deviasi
+// @Produce json
+// @Param body body request.ReqConfirmUploadQuotaDeviasi true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.UploadQuotaDeviasiBranchResponse}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/quota-deviasi/upload/confirm [post]
+func (c *handlerCMS) QuotaDeviasiUploadConfirm(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqConfirmUploadQuotaDeviasi
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Confirm Upload Setting Kuota Deviasi", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Confirm Upload Setting Kuota Deviasi", req, err)
+	}
+
+	data, err := c.usecase.ConfirmUploadQuotaDeviasi(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Confirm Upload Setting Kuota Deviasi", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Upload Setting Kuota Deviasi Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Setting Quota Deviasi
+// @Tags Setting Quota Deviasi
+// @Produce json
+// @Param body body request.ReqUpdateQuotaDeviasi true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.ApiResponse}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/quota-deviasi/update [post]
+func (c *handlerCMS) QuotaDeviasiUpdate(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqUpdateQuotaDeviasi
+		ctxJson     error
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		ctxJson, _ = c.Json.InternalServerErrorCustomV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi", err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi - Input Tidak Valid", req, err)
+		return ctxJson
+	}
+
+	data, err := c.usecase.UpdateQuotaDeviasiBranch(ctx.Request().Context(), req)
+
+	if err != nil {
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi", req, err)
+		return ctxJson
+	}
+
+	ctxJson, _ = c.Json.SuccessV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Kuota Deviasi - Success", req, data)
+	return ctxJson
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Setting Quota Deviasi
+// @Tags Setting Quota Deviasi
 // @Produce json
 // @Param body body request.ReqResetQuotaDeviasiBranch true "Body payload"
 // @Success 200 {object} response.ApiResponse{data=response.ApiResponse}
@@ -1935,6 +2394,89 @@ func (c *handlerCMS) UploadMappingCluster(ctx echo.Context) (err error) {
 	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Upload Success", nil, nil)
 }
 
+// CMS NEW KMB Tools godoc
+// @Description Api Preview Upload Mapping Cluster
+// @Tags Mapping Cluster
+// @Produce json
+// @Param excel_file formData file true "upload file"
+// @Param user_id formData string true "user id"
+// @Success 200 {object} response.ApiResponse{data=response.UploadPreview}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/mapping-cluster/upload/preview [post]
+func (c *handlerCMS) UploadMappingClusterPreview(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqUploadMappingCluster
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster", req, err)
+	}
+
+	file, err := ctx.FormFile("excel_file")
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster", nil, errors.New(constant.ERROR_BAD_REQUEST+" - Silakan unggah file excel yang valid"))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster", nil, errors.New(constant.ERROR_BAD_REQUEST+" - Silakan unggah file excel yang valid"))
+	}
+	defer src.Close()
+
+	mime := file.Header.Get("Content-Type")
+	if mime != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster", nil, errors.New(constant.ERROR_BAD_REQUEST+" - Silakan unggah file berformat .xlsx"))
+	}
+
+	data, err := c.usecase.PreviewMappingCluster(req, src)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster", nil, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Preview Mapping Cluster Success", nil, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Confirm Upload Mapping Cluster
+// @Tags Mapping Cluster
+// @Produce json
+// @Param body body request.ReqConfirmUploadMappingCluster true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/mapping-cluster/upload/confirm [post]
+func (c *handlerCMS) UploadMappingClusterConfirm(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqConfirmUploadMappingCluster
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Confirm Mapping Cluster", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Confirm Mapping Cluster", req, err)
+	}
+
+	err = c.usecase.ConfirmMappingCluster(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Confirm Mapping Cluster", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Upload Success", req, nil)
+}
+
 // CMS NEW KMB Tools godoc
 // @Description Api Mapping Cluster
 // @Tags Mapping Cluster
@@ -2008,38 +2550,992 @@ func (c *handlerCMS) MappingClusterChangeLog(ctx echo.Context) (err error) {
 }
 
 // CMS NEW KMB Tools godoc
-// @Description Api Get Chassis Number By License Plate
-// @Tags Agreement By License Plate
+// @Description Api Mapping Cluster Versions
+// @Tags Mapping Cluster
 // @Produce json
-// @Param license_plate path string true "License Plate"
-// @Success 200 {object} response.ApiResponse{data=response.ChassisNumberOfLicensePlateResponse}
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
 // @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
 // @Failure 500 {object} response.ApiResponse{}
-// @Router /api/v3/kmb/cms/ne/check_license_plate/{license_plate} [get]
-func (c *handlerCMS) CheckLicensePlate(ctx echo.Context) (err error) {
+// @Router /api/v3/kmb/cms/mapping-cluster/versions [get]
+func (c *handlerCMS) MappingClusterVersions(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	data, rowTotal, err := c.usecase.GetMappingClusterVersions(pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Versions", nil, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Versions", nil, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Versions", nil, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Mapping Cluster Version Diff
+// @Tags Mapping Cluster
+// @Produce json
+// @Param from query int true "from version"
+// @Param to query int true "to version"
+// @Success 200 {object} response.ApiResponse{data=response.MappingClusterVersionDiff}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/mapping-cluster/versions/diff [get]
+func (c *handlerCMS) MappingClusterVersionDiff(ctx echo.Context) (err error) {
 
 	var (
 		accessToken = middlewares.UserInfoData.AccessToken
-		ctxJson     error
+		req         request.ReqDiffMappingCluster
 	)
 
-	licensePlate := ctx.QueryParam("license_plate")
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Version Diff", err)
+	}
 
-	if licensePlate == "" {
-		err = errors.New(constant.ERROR_BAD_REQUEST + " - param request `license_plate` does not exist")
-		ctxJson, _ = c.Json.BadRequestErrorBindV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Check License Plate - param request `license_plate` does not exist", licensePlate, err)
-		return ctxJson
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Version Diff", req, err)
 	}
 
-	data, err := c.usecase.GetAgreementByLicensePlate(ctx.Request().Context(), licensePlate, accessToken)
+	data, err := c.usecase.DiffMappingClusterVersion(ctx.Request().Context(), req)
 
 	if err != nil {
-		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Check License Plate", licensePlate, err)
-		return ctxJson
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Version Diff", req, err)
 	}
 
-	ctxJson, _ = c.Json.SuccessV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Check License Plate", licensePlate, data)
-	return ctxJson
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Version Diff", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Mapping Cluster Rollback
+// @Tags Mapping Cluster
+// @Produce json
+// @Param body body request.ReqRollbackMappingCluster true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.MappingClusterVersionDiff}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/mapping-cluster/rollback [post]
+func (c *handlerCMS) MappingClusterRollback(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqRollbackMappingCluster
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Rollback", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Rollback", req, err)
+	}
+
+	data, err := c.usecase.RollbackMappingCluster(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Rollback", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Cluster Rollback Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Worker
+// @Tags Worker
+// @Produce json
+// @Param prospect_id query string false "prospect_id"
+// @Param category query string false "category"
+// @Param activity query string false "activity"
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/worker/inquiry [get]
+func (c *handlerCMS) WorkerInquiry(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqListWorker{
+		ProspectID: ctx.QueryParam("prospect_id"),
+		Category:   ctx.QueryParam("category"),
+		Activity:   ctx.QueryParam("activity"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	data, rowTotal, err := c.usecase.GetWorkers(req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Worker Inquiry", req, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Worker Inquiry", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Worker Inquiry", req, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Worker
+// @Tags Worker
+// @Produce json
+// @Param body body request.ReqRequeueWorker true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/worker/requeue [post]
+func (c *handlerCMS) WorkerRequeue(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqRequeueWorker
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Requeue Worker", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Requeue Worker", req, err)
+	}
+
+	err = c.usecase.RequeueWorker(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Requeue Worker", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Requeue Worker Success", req, nil)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Notification Preference
+// @Tags Notification
+// @Produce json
+// @Param user_id query string true "user_id"
+// @Success 200 {object} response.ApiResponse{data=[]entity.MappingNotificationPreference}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/notification/preference [get]
+func (c *handlerCMS) NotificationPreference(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqListNotificationPreference
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Notification Preference", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Notification Preference", req, err)
+	}
+
+	data, err := c.usecase.GetNotificationPreferences(req.UserID)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Notification Preference", req, data)
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Notification Preference", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Notification Preference", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Notification Preference
+// @Tags Notification
+// @Produce json
+// @Param body body request.ReqNotificationPreference true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=entity.MappingNotificationPreference}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/notification/preference [post]
+func (c *handlerCMS) SaveNotificationPreference(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqNotificationPreference
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save Notification Preference", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save Notification Preference", req, err)
+	}
+
+	data, err := c.usecase.SaveNotificationPreference(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save Notification Preference", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save Notification Preference Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Dashboard Stats
+// @Tags Dashboard
+// @Produce json
+// @Param user_id query string true "user_id"
+// @Param branch_id query string true "branch_id"
+// @Param multi_branch query string false "multi_branch"
+// @Param branch_filter query string false "branch_filter"
+// @Param region query string false "region"
+// @Param start_date query string true "start_date (YYYY-MM-DD)"
+// @Param end_date query string true "end_date (YYYY-MM-DD)"
+// @Success 200 {object} response.ApiResponse{data=response.DashboardStats}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/dashboard/stats [get]
+func (c *handlerCMS) DashboardStats(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqDashboardStats
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Dashboard Stats", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Dashboard Stats", req, err)
+	}
+
+	data, err := c.usecase.GetDashboardStats(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Dashboard Stats", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Dashboard Stats", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Save NE Draft
+// @Tags Submit NE
+// @Produce json
+// @Param body body request.ReqSaveNEDraft true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.NEDraft}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/draft/save [post]
+func (c *handlerCMS) SaveNEDraft(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqSaveNEDraft
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save NE Draft", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save NE Draft", req, err)
+	}
+
+	data, err := c.usecase.SaveNEDraft(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save NE Draft", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Save NE Draft Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Update NE Draft
+// @Tags Submit NE
+// @Produce json
+// @Param body body request.ReqUpdateNEDraft true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.NEDraft}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/draft/update [post]
+func (c *handlerCMS) UpdateNEDraft(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqUpdateNEDraft
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update NE Draft", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update NE Draft", req, err)
+	}
+
+	data, err := c.usecase.UpdateNEDraft(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update NE Draft", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update NE Draft Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api NE Draft
+// @Tags Submit NE
+// @Produce json
+// @Param created_by query string true "created_by"
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/draft/inquiry [get]
+func (c *handlerCMS) NEDraftInquiry(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqListNEDraft{
+		CreatedBy: ctx.QueryParam("created_by"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - NE Draft Inquiry", req, err)
+	}
+
+	data, rowTotal, err := c.usecase.GetNEDrafts(req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - NE Draft Inquiry", req, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - NE Draft Inquiry", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - NE Draft Inquiry", req, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Resume NE Draft
+// @Tags Submit NE
+// @Produce json
+// @Param draft_id path string true "draft_id"
+// @Param created_by query string true "created_by"
+// @Success 200 {object} response.ApiResponse{data=response.NEDraft}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/draft/{draft_id} [get]
+func (c *handlerCMS) ResumeNEDraft(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqNEDraft
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Resume NE Draft", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Resume NE Draft", req, err)
+	}
+
+	data, err := c.usecase.ResumeNEDraft(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Resume NE Draft", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Resume NE Draft", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Discard NE Draft
+// @Tags Submit NE
+// @Produce json
+// @Param body body request.ReqNEDraft true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/draft/discard [post]
+func (c *handlerCMS) DiscardNEDraft(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqNEDraft
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Discard NE Draft", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Discard NE Draft", req, err)
+	}
+
+	err = c.usecase.DiscardNEDraft(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Discard NE Draft", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Discard NE Draft Success", req, nil)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Precheck NE
+// @Tags Submit NE
+// @Produce json
+// @Param body body request.MetricsNE true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.NEPrecheck}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/precheck [post]
+func (c *handlerCMS) PrecheckNE(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.MetricsNE
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Precheck NE", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Precheck NE", req, err)
+	}
+
+	data, err := c.usecase.PrecheckNE(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Precheck NE", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Precheck NE", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Master Data
+// @Tags Master Data
+// @Produce json
+// @Param type query string true "cancel_reason / approval_reason / prescreening_reason / industry_type"
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/master-data/inquiry [get]
+func (c *handlerCMS) MasterDataInquiry(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqListMasterData{
+		Type: ctx.QueryParam("type"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data Inquiry", req, err)
+	}
+
+	data, rowTotal, err := c.usecase.GetMasterData(req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data Inquiry", req, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data Inquiry", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data Inquiry", req, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Master Data
+// @Tags Master Data
+// @Produce json
+// @Param body body request.ReqMasterData true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=entity.MasterData}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/master-data/create [post]
+func (c *handlerCMS) CreateMasterData(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqMasterData
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Master Data", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Master Data", req, err)
+	}
+
+	data, err := c.usecase.CreateMasterData(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Master Data", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Master Data Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Master Data
+// @Tags Master Data
+// @Produce json
+// @Param body body request.ReqMasterData true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=entity.MasterData}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/master-data/update [post]
+func (c *handlerCMS) UpdateMasterData(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqMasterData
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Master Data", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Master Data", req, err)
+	}
+
+	data, err := c.usecase.UpdateMasterData(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Master Data", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Master Data Success", req, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Master Data
+// @Tags Master Data
+// @Produce json
+// @Param body body request.ReqDeleteMasterData true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/master-data/delete [post]
+func (c *handlerCMS) DeleteMasterData(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqDeleteMasterData
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Delete Master Data", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Delete Master Data", req, err)
+	}
+
+	err = c.usecase.DeleteMasterData(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Delete Master Data", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Delete Master Data Success", req, nil)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Master Data
+// @Tags Master Data
+// @Produce json
+// @Param body body request.ReqReorderMasterData true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/master-data/reorder [post]
+func (c *handlerCMS) ReorderMasterData(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqReorderMasterData
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reorder Master Data", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reorder Master Data", req, err)
+	}
+
+	err = c.usecase.ReorderMasterData(ctx.Request().Context(), req)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reorder Master Data", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reorder Master Data Success", req, nil)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Master Data
+// @Tags Master Data
+// @Produce json
+// @Param type query string true "cancel_reason / approval_reason / prescreening_reason / industry_type"
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/master-data/history [get]
+func (c *handlerCMS) MasterDataHistory(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqListMasterData{
+		Type: ctx.QueryParam("type"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data History", req, err)
+	}
+
+	data, rowTotal, err := c.usecase.GetMasterDataHistory(req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data History", req, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data History", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Master Data History", req, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Get Chassis Number By License Plate
+// @Tags Agreement By License Plate
+// @Produce json
+// @Param license_plate path string true "License Plate"
+// @Success 200 {object} response.ApiResponse{data=response.ChassisNumberOfLicensePlateResponse}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/ne/check_license_plate/{license_plate} [get]
+func (c *handlerCMS) CheckLicensePlate(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		ctxJson     error
+	)
+
+	licensePlate := ctx.QueryParam("license_plate")
+
+	if licensePlate == "" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - param request `license_plate` does not exist")
+		ctxJson, _ = c.Json.BadRequestErrorBindV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Check License Plate - param request `license_plate` does not exist", licensePlate, err)
+		return ctxJson
+	}
+
+	data, err := c.usecase.GetAgreementByLicensePlate(ctx.Request().Context(), licensePlate, accessToken)
+
+	if err != nil {
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Check License Plate", licensePlate, err)
+		return ctxJson
+	}
+
+	ctxJson, _ = c.Json.SuccessV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Check License Plate", licensePlate, data)
+	return ctxJson
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Lock System
+// @Tags Lock System
+// @Produce json
+// @Param id_number query string false "id_number"
+// @Param chassis_number query string false "chassis_number"
+// @Param engine_number query string false "engine_number"
+// @Param is_active query string false "is_active"
+// @Param page query string false "page"
+// @Success 200 {object} response.ApiResponse{data=response.InquiryRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/lock-system/inquiry [get]
+func (c *handlerCMS) LockSystemInquiry(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqListLockSystem{
+		IDNumber:      ctx.QueryParam("id_number"),
+		ChassisNumber: ctx.QueryParam("chassis_number"),
+		EngineNumber:  ctx.QueryParam("engine_number"),
+		IsActive:      ctx.QueryParam("is_active"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System Inquiry", req, err)
+	}
+
+	data, rowTotal, err := c.usecase.GetInquiryLockSystem(req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System Inquiry", req, response.InquiryRow{Inquiry: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System Inquiry", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System Inquiry", req, response.InquiryRow{
+		Inquiry:        data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Lock System, lift, extend or shorten a lock with a mandatory reason
+// @Tags Lock System
+// @Produce json
+// @Param body body request.ReqUpdateLockSystem true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.UpdateLockSystemResponse}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/lock-system/update [post]
+func (c *handlerCMS) LockSystemUpdate(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqUpdateLockSystem
+		ctxJson     error
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		ctxJson, _ = c.Json.InternalServerErrorCustomV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Lock System", err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Lock System - Input Tidak Valid", req, err)
+		return ctxJson
+	}
+
+	data, err := c.usecase.UpdateLockSystem(ctx.Request().Context(), req)
+
+	if err != nil {
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Lock System", req, err)
+		return ctxJson
+	}
+
+	ctxJson, _ = c.Json.SuccessV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Update Lock System - Success", req, data)
+	return ctxJson
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Lock System, audit trail of manual overrides
+// @Tags Lock System
+// @Produce json
+// @Param lock_type path string true "LOCK_SYSTEM / BANNED_PMK_DSR / BANNED_CHASSIS"
+// @Param prospect_id path string true "Prospect ID"
+// @Success 200 {object} response.ApiResponse{data=[]entity.TrxLockOverride}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/lock-system/history/{lock_type}/{prospect_id} [get]
+func (c *handlerCMS) LockSystemHistory(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	lockType := ctx.Param("lock_type")
+	prospectID := ctx.Param("prospect_id")
+
+	data, err := c.usecase.GetLockSystemHistory(lockType, prospectID)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System History", prospectID, data)
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System History", prospectID, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Lock System History", prospectID, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Export Inquiry, the filter follows the query of the chosen inquiry and the file is generated in background
+// @Tags Export Inquiry
+// @Produce json
+// @Param body body request.ReqCreateExportJob true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.ExportJob}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/export [post]
+func (c *handlerCMS) CreateExportJob(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqCreateExportJob
+		filter      interface{}
+		ctxJson     error
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		ctxJson, _ = c.Json.InternalServerErrorCustomV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry", err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry - Input Tidak Valid", req, err)
+		return ctxJson
+	}
+
+	switch req.InquiryType {
+	case constant.EXPORT_TYPE_PRESCREENING:
+		filter = &request.ReqInquiryPrescreening{}
+	case constant.EXPORT_TYPE_CA:
+		filter = &request.ReqInquiryCa{}
+	case constant.EXPORT_TYPE_APPROVAL:
+		filter = &request.ReqInquiryApproval{}
+	case constant.EXPORT_TYPE_LIST_ORDER:
+		filter = &request.ReqInquiryListOrder{}
+	case constant.EXPORT_TYPE_SEARCH:
+		filter = &request.ReqSearchInquiry{}
+	}
+
+	// the filter is checked with the rules of the inquiry it belongs to
+	body, _ := json.Marshal(req.Filter)
+	if err := json.Unmarshal(body, filter); err != nil {
+		ctxJson, _ = c.Json.InternalServerErrorCustomV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry", err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(filter); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry - Input Tidak Valid", req, err)
+		return ctxJson
+	}
+
+	data, err := c.usecase.CreateExportJob(ctx.Request().Context(), req)
+
+	if err != nil {
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry", req, err)
+		return ctxJson
+	}
+
+	ctxJson, _ = c.Json.SuccessV3(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry - Success", req, data)
+	return ctxJson
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Export Inquiry, status of an export job
+// @Tags Export Inquiry
+// @Produce json
+// @Param id path string true "Export Job ID"
+// @Success 200 {object} response.ApiResponse{data=response.ExportJob}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/export/{id} [get]
+func (c *handlerCMS) GetExportJob(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	id := ctx.Param("id")
+
+	data, err := c.usecase.GetExportJob(id)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry Status", id, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry Status", id, data)
+}
+
+// CMS NEW KMB Tools godoc
+// @Description Api Export Inquiry, download the file of a finished export job
+// @Tags Export Inquiry
+// @Produce octet-stream
+// @Param id path string true "Export Job ID"
+// @Success 302 {string} string "redirect to the file in media"
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/cms/export/{id}/download [get]
+func (c *handlerCMS) DownloadExportJob(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	id := ctx.Param("id")
+
+	fileURL, err := c.usecase.GetExportFile(id)
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Export Inquiry Download", id, err)
+	}
+
+	return ctx.Redirect(http.StatusFound, fileURL)
 }
 
 func (c *handlerCMS) GetToken(ctx echo.Context) (err error) {
@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/cms/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"time"
+)
+
+// Run copies the master data history left in the outbox to los every interval until ctx is done
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "MASTER_DATA_HISTORY_SCHEDULER",
+		MsgLogFile: "LOS - Master Data History Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+		SkipIdle:   true,
+	}, func(ctx context.Context, _ string) (map[string]interface{}, error) {
+		synced, err := usecase.SyncMasterDataHistory(ctx)
+		return map[string]interface{}{"synced": synced}, err
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"los-kmb-api/models/entity"
 	"los-kmb-api/models/request"
 	"los-kmb-api/models/response"
+	"time"
 )
 
 type Repository interface {
@@ -19,6 +20,9 @@ type Repository interface {
 	GetInquiryPrescreening(req request.ReqInquiryPrescreening, pagination interface{}) (data []entity.InquiryPrescreening, rowTotal int, err error)
 	GetTrxStatus(prospectID string) (status entity.TrxStatus, err error)
 	GetTrxEDD(prospectID string) (trxEDD entity.TrxEDD, err error)
+	GetNTFAkumulasi(prospectID string) (ntfAkumulasi float64, err error)
+	GetApprovalState(prospectID string) (data entity.ApprovalState, err error)
+	GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error)
 	SavePrescreening(prescreening entity.TrxPrescreening, detail entity.TrxDetail, status entity.TrxStatus) (err error)
 	SaveLogOrchestrator(header, request, response interface{}, path, method, prospectID string, requestID string) (err error)
 	GetDatatableCa(req request.ReqInquiryCa, pagination interface{}) (data []entity.ListDatatableCa, rowTotal int, err error)
@@ -44,6 +48,8 @@ type Repository interface {
 	GetAFMobilePhone(prospectID string) (data entity.AFMobilePhone, err error)
 	GetRegionBranch(userId string) (data []entity.RegionBranch, err error)
 	GetInquiryQuotaDeviasi(req request.ReqListQuotaDeviasi, pagination interface{}) (data []entity.InquirySettingQuotaDeviasi, rowTotal int, err error)
+	GetQuotaDeviasiConsumption(req request.ReqListQuotaDeviasiConsumption, pagination interface{}) (data []entity.InquiryQuotaDeviasiConsumption, rowTotal int, err error)
+	ReleaseQuotaDeviasi(prospectID, reason, releasedBy string) (released bool, err error)
 	GetQuotaDeviasiBranch(req request.ReqListQuotaDeviasiBranch) (data []entity.ConfinsBranch, err error)
 	ProcessUpdateQuotaDeviasiBranch(branchID string, mBranchDeviasi entity.MappingBranchDeviasi) (dataBefore entity.DataQuotaDeviasiBranch, dataAfter entity.DataQuotaDeviasiBranch, err error)
 	BatchUpdateQuotaDeviasi(data []entity.MappingBranchDeviasi) (dataBeforeList []entity.MappingBranchDeviasi, dataAfterList []entity.MappingBranchDeviasi, err error)
@@ -54,9 +60,48 @@ type Repository interface {
 	GetMappingCluster() (data []entity.MasterMappingCluster, err error)
 	GetInquiryMappingCluster(req request.ReqListMappingCluster, pagination interface{}) (data []entity.InquiryMappingCluster, rowTotal int, err error)
 	BatchUpdateMappingCluster(data []entity.MasterMappingCluster, history entity.HistoryConfigChanges) (err error)
+	RollbackMappingCluster(data []entity.MasterMappingCluster, history entity.HistoryConfigChanges, version int) (before []entity.MasterMappingCluster, err error)
+	GetMappingClusterVersion(version int) (data entity.MappingClusterVersion, err error)
+	GetMappingClusterVersions(pagination interface{}) (data []entity.InquiryMappingClusterVersion, rowTotal int, err error)
+	GetWorkers(req request.ReqListWorker, pagination interface{}) (data []entity.InquiryWorker, rowTotal int, err error)
+	RequeueWorker(req request.ReqRequeueWorker) (err error)
+	GetNotificationPreferences(userID string) (data []entity.MappingNotificationPreference, err error)
+	SaveNotificationPreference(data entity.MappingNotificationPreference) (err error)
+	GetRegionBranches(region string) (branchIDs []string, err error)
+	GetDashboardStats(branchIDs []string, startDate, endDate time.Time) (data entity.DashboardStats, err error)
+	SaveUploadPreview(preview entity.TrxUploadPreview) (err error)
+	GetUploadPreview(id string) (data entity.TrxUploadPreview, err error)
+	UpdateUploadPreviewStatus(id, fromStatus, toStatus string, appliedBy *string) (err error)
+	GetQuotaDeviasiBranches(branchIDs []string) (data []entity.MappingBranchDeviasi, err error)
 	GetMappingClusterBranch(req request.ReqListMappingClusterBranch) (data []entity.ConfinsBranch, err error)
 	GetMappingClusterChangeLog(pagination interface{}) (data []entity.MappingClusterChangeLog, rowTotal int, err error)
 	GetListBranch(req request.ReqListBranch) (regions []string, branches []response.BranchInfo, err error)
 	SaveWorker(trxworker entity.TrxWorker) (err error)
 	SaveUrlFormAKKK(prospectID, urlFormAKKK string) (err error)
+	GetInquiryLockSystem(req request.ReqListLockSystem, pagination interface{}) (data []entity.InquiryLockSystem, rowTotal int, err error)
+	GetLockSystemEntry(lockType, prospectID string) (data entity.TrxLockOverride, err error)
+	SaveLockOverride(override entity.TrxLockOverride) (err error)
+	GetLockOverrideHistory(lockType, prospectID string) (data []entity.TrxLockOverride, err error)
+	SaveExportJob(job entity.TrxExportJob) (err error)
+	UpdateExportJob(id string, fields map[string]interface{}) (err error)
+	GetExportJob(id string) (data entity.TrxExportJob, err error)
+	GetOrderClaim(prospectID string) (data entity.TrxOrderClaim, err error)
+	ClaimOrder(claim entity.TrxOrderClaim, history entity.TrxOrderClaimHistory) (err error)
+	TouchOrderClaim(prospectID, userID string) (err error)
+	ReleaseOrderClaim(prospectID, userID string, history entity.TrxOrderClaimHistory) (err error)
+	SaveNEDraft(draft entity.TrxNEDraft) (err error)
+	UpdateNEDraft(id, createdBy string, fields map[string]interface{}) (err error)
+	GetNEDraft(id, createdBy string) (data entity.TrxNEDraft, err error)
+	GetNEDrafts(req request.ReqListNEDraft, pagination interface{}) (data []entity.InquiryNEDraft, rowTotal int, err error)
+	GetMappingIncomePMK(branchID string) (data []entity.MappingIncomePMK, err error)
+	GetBranchClusters(branchID string, bpkbNameType int) (data []entity.MasterMappingCluster, err error)
+	GetRecalculateHistory(prospectID string) (data []entity.TrxRecalculateHistory, err error)
+	GetRecalculateEvents(prospectID string) (data []entity.TrxHistoryApprovalScheme, err error)
+	GetRecalculateCurrent(prospectID string) (data entity.TrxRecalculate, err error)
+	GetRecalculateLimit(prospectID string) (data entity.RecalculateLimit, err error)
+	GetMasterData(dataType string) (data []entity.MasterData, err error)
+	SaveMasterData(dataType string, data []entity.MasterData, isNew bool, history entity.HistoryConfigChanges) (err error)
+	SyncMasterDataHistory(limit int) (synced int, err error)
+	GetMasterDataHistory(configID string, pagination interface{}) (data []entity.MasterDataChangeLog, rowTotal int, err error)
+	SavePIIAccessLog(logs []entity.TrxPIIAccessLog) (err error)
 }
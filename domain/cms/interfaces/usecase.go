@@ -20,6 +20,7 @@ type Usecase interface {
 	SubmitDecision(ctx context.Context, req request.ReqSubmitDecision) (data response.CAResponse, err error)
 	GetSearchInquiry(ctx context.Context, req request.ReqSearchInquiry, pagination interface{}) (data []entity.InquiryDataSearch, rowTotal int, err error)
 	GetAkkk(prospectID string) (data entity.Akkk, err error)
+	GetAkkkView(req request.ReqViewAkkk) (data entity.Akkk, err error)
 	GetListBranch(ctx context.Context, req request.ReqListBranch) (data response.ListBranchResponse, err error)
 	SubmitNE(ctx context.Context, req request.MetricsNE) (data interface{}, err error)
 	GetInquiryNE(ctx context.Context, req request.ReqInquiryNE, pagination interface{}) (data []entity.InquiryDataNE, rowTotal int, err error)
@@ -33,10 +34,14 @@ type Usecase interface {
 	GetApprovalReason(ctx context.Context, req request.ReqApprovalReason, pagination interface{}) (data []entity.ApprovalReason, rowTotal int, err error)
 	SubmitApproval(ctx context.Context, req request.ReqSubmitApproval) (data response.ApprovalResponse, err error)
 	GetInquiryQuotaDeviasi(req request.ReqListQuotaDeviasi, pagination interface{}) (data []entity.InquirySettingQuotaDeviasi, rowTotal int, err error)
+	GetQuotaDeviasiConsumption(req request.ReqListQuotaDeviasiConsumption, pagination interface{}) (data []entity.InquiryQuotaDeviasiConsumption, rowTotal int, err error)
+	ReleaseQuotaDeviasi(ctx context.Context, prospectID, reason, releasedBy string) (released bool, err error)
 	GetQuotaDeviasiBranch(req request.ReqListQuotaDeviasiBranch) (data []entity.ConfinsBranch, err error)
 	UpdateQuotaDeviasiBranch(ctx context.Context, req request.ReqUpdateQuotaDeviasi) (data response.UpdateQuotaDeviasiBranchResponse, err error)
 	GenerateExcelQuotaDeviasi() (genName, fileName string, err error)
 	UploadQuotaDeviasi(req request.ReqUploadSettingQuotaDeviasi, file multipart.File) (data response.UploadQuotaDeviasiBranchResponse, err error)
+	PreviewUploadQuotaDeviasi(req request.ReqUploadSettingQuotaDeviasi, file multipart.File) (data response.UploadPreview, err error)
+	ConfirmUploadQuotaDeviasi(ctx context.Context, req request.ReqConfirmUploadQuotaDeviasi) (data response.UploadQuotaDeviasiBranchResponse, err error)
 	ResetQuotaDeviasiBranch(ctx context.Context, req request.ReqResetQuotaDeviasiBranch) (data response.UpdateQuotaDeviasiBranchResponse, err error)
 	ResetAllQuotaDeviasi(ctx context.Context, req request.ReqResetAllQuotaDeviasi) (data response.UploadQuotaDeviasiBranchResponse, err error)
 	GetInquiryListOrder(ctx context.Context, req request.ReqInquiryListOrder, pagination interface{}) (data []entity.InquiryDataListOrder, rowTotal int, err error)
@@ -44,8 +49,46 @@ type Usecase interface {
 	GetInquiryMappingCluster(req request.ReqListMappingCluster, pagination interface{}) (data []entity.InquiryMappingCluster, rowTotal int, err error)
 	GenerateExcelMappingCluster() (genName, fileName string, err error)
 	UpdateMappingCluster(req request.ReqUploadMappingCluster, file multipart.File) (err error)
+	PreviewMappingCluster(req request.ReqUploadMappingCluster, file multipart.File) (data response.UploadPreview, err error)
+	ConfirmMappingCluster(ctx context.Context, req request.ReqConfirmUploadMappingCluster) (err error)
 	GetMappingClusterBranch(req request.ReqListMappingClusterBranch) (data []entity.ConfinsBranch, err error)
 	GetMappingClusterChangeLog(pagination interface{}) (data []entity.MappingClusterChangeLog, rowTotal int, err error)
+	GetMappingClusterVersions(pagination interface{}) (data []entity.InquiryMappingClusterVersion, rowTotal int, err error)
+	DiffMappingClusterVersion(ctx context.Context, req request.ReqDiffMappingCluster) (data response.MappingClusterVersionDiff, err error)
+	RollbackMappingCluster(ctx context.Context, req request.ReqRollbackMappingCluster) (data response.MappingClusterVersionDiff, err error)
+	GetWorkers(req request.ReqListWorker, pagination interface{}) (data []entity.InquiryWorker, rowTotal int, err error)
+	RequeueWorker(ctx context.Context, req request.ReqRequeueWorker) (err error)
+	GetNotificationPreferences(userID string) (data []entity.MappingNotificationPreference, err error)
+	SaveNotificationPreference(ctx context.Context, req request.ReqNotificationPreference) (data entity.MappingNotificationPreference, err error)
+	GetDashboardStats(ctx context.Context, req request.ReqDashboardStats) (data response.DashboardStats, err error)
 	GenerateFormAKKK(ctx context.Context, req request.RequestGenerateFormAKKK, accessToken string) (data interface{}, err error)
 	GetAgreementByLicensePlate(ctx context.Context, LicensePlate string, accessToken string) (data response.ChassisNumberOfLicensePlateResponse, err error)
+	GetInquiryLockSystem(req request.ReqListLockSystem, pagination interface{}) (data []entity.InquiryLockSystem, rowTotal int, err error)
+	UpdateLockSystem(ctx context.Context, req request.ReqUpdateLockSystem) (data response.UpdateLockSystemResponse, err error)
+	GetLockSystemHistory(lockType, prospectID string) (data []entity.TrxLockOverride, err error)
+	CreateExportJob(ctx context.Context, req request.ReqCreateExportJob) (data response.ExportJob, err error)
+	GetExportJob(id string) (data response.ExportJob, err error)
+	GetExportFile(id string) (fileURL string, err error)
+	BulkSubmitDecision(ctx context.Context, req request.ReqBulkSubmitDecision) (data response.BulkSubmitResponse, err error)
+	BulkSubmitApproval(ctx context.Context, req request.ReqBulkSubmitApproval) (data response.BulkSubmitResponse, err error)
+	ClaimOrder(ctx context.Context, req request.ReqClaimOrder) (data response.OrderClaim, err error)
+	ReleaseOrder(ctx context.Context, req request.ReqReleaseOrder) (err error)
+	ForceReleaseOrder(ctx context.Context, req request.ReqForceReleaseOrder) (err error)
+	GetOrderClaim(prospectID string) (data response.OrderClaim, err error)
+	SaveNEDraft(ctx context.Context, req request.ReqSaveNEDraft) (data response.NEDraft, err error)
+	UpdateNEDraft(ctx context.Context, req request.ReqUpdateNEDraft) (data response.NEDraft, err error)
+	GetNEDrafts(req request.ReqListNEDraft, pagination interface{}) (data []entity.InquiryNEDraft, rowTotal int, err error)
+	ResumeNEDraft(ctx context.Context, req request.ReqNEDraft) (data response.NEDraft, err error)
+	DiscardNEDraft(ctx context.Context, req request.ReqNEDraft) (err error)
+	CompleteNEDraft(ctx context.Context, req request.ReqNEDraft) (err error)
+	PrecheckNE(ctx context.Context, req request.MetricsNE) (data response.NEPrecheck, err error)
+	GetRecalculateHistory(ctx context.Context, prospectID string) (data response.RecalculateHistory, err error)
+	GetMasterData(req request.ReqListMasterData, pagination interface{}) (data []entity.MasterData, rowTotal int, err error)
+	CreateMasterData(ctx context.Context, req request.ReqMasterData) (data entity.MasterData, err error)
+	UpdateMasterData(ctx context.Context, req request.ReqMasterData) (data entity.MasterData, err error)
+	DeleteMasterData(ctx context.Context, req request.ReqDeleteMasterData) (err error)
+	ReorderMasterData(ctx context.Context, req request.ReqReorderMasterData) (err error)
+	GetMasterDataHistory(req request.ReqListMasterData, pagination interface{}) (data []entity.MasterDataChangeLog, rowTotal int, err error)
+	SyncMasterDataHistory(ctx context.Context) (synced int, err error)
+	RefreshIndustryType(id, description string)
 }
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"fmt"
+	"los-kmb-api/shared/utils"
+	"strings"
+)
+
+// customerSearchCondition builds the trx_customer_personal condition of a search by id number, legal name or mobile phone,
+// through the blind indexes once they are enabled and through the encrypted value before that
+func (r repoHandler) customerSearchCondition(searchBy, searchValue string) (condition string, err error) {
+
+	if !utils.BlindIndexEnabled() {
+		column := map[string]string{
+			"id_number":    "tcp.IDNumber",
+			"legal_name":   "tcp.LegalName",
+			"mobile_phone": "tcp.MobilePhone",
+		}[searchBy]
+
+		if column == "" {
+			return
+		}
+
+		encrypted, errEncrypt := r.EncryptString(searchValue)
+		if errEncrypt != nil {
+			err = errEncrypt
+			return
+		}
+
+		condition = fmt.Sprintf("%s = '%s'", column, encrypted.Encrypt)
+		return
+	}
+
+	switch searchBy {
+	case "id_number":
+		condition = fmt.Sprintf("(tcp.id_number_bidx = '%s' OR %s)", utils.BlindIndexIDNumber(searchValue), numberTokenCondition(utils.BlindIndexIDNumberSearchTokens(searchValue)))
+	case "mobile_phone":
+		condition = fmt.Sprintf("(tcp.mobile_phone_bidx = '%s' OR %s)", utils.BlindIndexMobilePhone(searchValue), numberTokenCondition(utils.BlindIndexPhoneSearchTokens(searchValue)))
+	case "legal_name":
+		condition = nameTokenCondition(utils.BlindIndexSearchTokens(searchValue))
+	}
+
+	return
+}
+
+// nameTokenCondition matches the orders whose name has every token, tokens are hex hashes so they are safe to inline
+func nameTokenCondition(tokens []string) string {
+
+	if len(tokens) == 0 {
+		return "1 = 0"
+	}
+
+	return fmt.Sprintf(`tcp.ProspectID IN (SELECT ProspectID FROM trx_customer_name_bidx WITH (nolock) WHERE bidx IN ('%s') GROUP BY ProspectID HAVING COUNT(DISTINCT bidx) = %d)`,
+		strings.Join(tokens, "','"), len(tokens))
+}
+
+// numberTokenCondition matches the orders whose id number or mobile phone starts or ends with the search
+func numberTokenCondition(tokens []string) string {
+
+	if len(tokens) == 0 {
+		return "1 = 0"
+	}
+
+	return fmt.Sprintf(`tcp.ProspectID IN (SELECT ProspectID FROM trx_customer_number_bidx WITH (nolock) WHERE bidx IN ('%s'))`,
+		strings.Join(tokens, "','"))
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// GetRegionBranches returns the branch members of a new kmb region
+func (r repoHandler) GetRegionBranches(region string) (branchIDs []string, err error) {
+
+	var (
+		x    sql.TxOptions
+		data []entity.BranchData
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT DISTINCT LTRIM(RTRIM(s.value)) AS BranchID
+		FROM region_branch a WITH (nolock)
+		INNER JOIN region b WITH (nolock) ON a.region = b.region_id
+		CROSS APPLY STRING_SPLIT(REPLACE(REPLACE(REPLACE(a.branch_member, '[', ''), ']', ''), '"', ''), ',') AS s
+		WHERE b.region_name = ? AND b.lob_id = ?`, region, constant.LOB_ID_NEW_KMB).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	for _, branch := range data {
+		branchIDs = append(branchIDs, branch.BranchID)
+	}
+
+	return
+}
+
+// GetDashboardStats aggregates the orders of the branches created between startDate and endDate (exclusive)
+func (r repoHandler) GetDashboardStats(branchIDs []string, startDate, endDate time.Time) (data entity.DashboardStats, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT ts.source_decision, ts.status_process, ts.decision, COUNT(*) AS total
+		FROM trx_status ts WITH (nolock)
+		INNER JOIN trx_master tm WITH (nolock) ON ts.ProspectID = tm.ProspectID
+		WHERE tm.BranchID IN (?) AND tm.created_at >= ? AND tm.created_at < ?
+		GROUP BY ts.source_decision, ts.status_process, ts.decision
+		ORDER BY ts.source_decision, ts.status_process, ts.decision`, branchIDs, startDate, endDate).Scan(&data.Orders).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	if err = db.Raw(`SELECT thas.source_decision, thas.decision, COUNT(*) AS total
+		FROM trx_history_approval_scheme thas WITH (nolock)
+		INNER JOIN trx_master tm WITH (nolock) ON thas.ProspectID = tm.ProspectID
+		WHERE tm.BranchID IN (?) AND tm.created_at >= ? AND tm.created_at < ?
+		GROUP BY thas.source_decision, thas.decision
+		ORDER BY thas.source_decision, thas.decision`, branchIDs, startDate, endDate).Scan(&data.AliasDecisions).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	if err = db.Raw(`SELECT TOP (?) ISNULL(ts.rule_code, '') AS rule_code, ISNULL(ts.reason, '') AS reason, COUNT(*) AS total
+		FROM trx_status ts WITH (nolock)
+		INNER JOIN trx_master tm WITH (nolock) ON ts.ProspectID = tm.ProspectID
+		WHERE tm.BranchID IN (?) AND tm.created_at >= ? AND tm.created_at < ? AND ts.status_process = ? AND ts.decision = ?
+		GROUP BY ts.rule_code, ts.reason
+		ORDER BY total DESC`, constant.DASHBOARD_TOP_REJECTION, branchIDs, startDate, endDate, constant.STATUS_FINAL, constant.DB_DECISION_REJECT).Scan(&data.Rejections).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	if err = db.Raw(`SELECT ISNULL(SUM(quota_amount), 0) AS quota_amount, ISNULL(SUM(quota_account), 0) AS quota_account,
+		ISNULL(SUM(booking_amount), 0) AS booking_amount, ISNULL(SUM(booking_account), 0) AS booking_account
+		FROM m_branch_deviasi WITH (nolock)
+		WHERE BranchID IN (?) AND is_active = 1`, branchIDs).Scan(&data.QuotaDeviasi).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	// trx_status.created_at is moved on every transition, on a final order it is the time of the decision
+	if err = db.Raw(`SELECT COUNT(*) AS total_decided, ISNULL(AVG(CAST(DATEDIFF(MINUTE, tm.created_at, ts.created_at) AS FLOAT)), 0) AS average_minutes
+		FROM trx_status ts WITH (nolock)
+		INNER JOIN trx_master tm WITH (nolock) ON ts.ProspectID = tm.ProspectID
+		WHERE tm.BranchID IN (?) AND tm.created_at >= ? AND tm.created_at < ? AND ts.status_process = ?`, branchIDs, startDate, endDate, constant.STATUS_FINAL).Scan(&data.TimeToDecision).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	err = nil
+
+	return
+}
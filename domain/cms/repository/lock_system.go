@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// lockOverrideApply picks the latest manual override of a lock row
+const lockOverrideApply = `OUTER APPLY (SELECT TOP 1 tlo.action, tlo.reason, tlo.created_by_name, tlo.unban_date
+			FROM trx_lock_override tlo WITH (nolock)
+			WHERE tlo.lock_type = '%s' AND tlo.ProspectID = %s.ProspectID
+			ORDER BY tlo.created_at DESC) o`
+
+func (r repoHandler) GetInquiryLockSystem(req request.ReqListLockSystem, pagination interface{}) (data []entity.InquiryLockSystem, rowTotal int, err error) {
+
+	var (
+		unions         []string
+		args           []interface{}
+		filterPaginate string
+		filter         string
+		x              sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	// trx_lock_system can be searched by nik, chassis and engine number
+	var lockConditions []string
+	if req.IDNumber != "" {
+		lockConditions = append(lockConditions, "tls.IDNumber = SCP.dbo.ENC_B64('SEC', ?)")
+		args = append(args, req.IDNumber)
+	}
+	if req.ChassisNumber != "" {
+		lockConditions = append(lockConditions, "tls.chassis_number = ?")
+		args = append(args, req.ChassisNumber)
+	}
+	if req.EngineNumber != "" {
+		lockConditions = append(lockConditions, "tls.engine_number = ?")
+		args = append(args, req.EngineNumber)
+	}
+
+	unions = append(unions, fmt.Sprintf(`SELECT '%s' AS lock_type,
+			CASE WHEN tls.reason LIKE 'Asset %%' THEN '%s' ELSE '%s' END AS banned_type,
+			tls.ProspectID, SCP.dbo.DEC_B64('SEC', tls.IDNumber) AS IDNumber,
+			ISNULL(tls.chassis_number, '') AS chassis_number, ISNULL(tls.engine_number, '') AS engine_number,
+			tls.reason, tls.created_at, CAST(tls.unban_date AS DATE) AS unban_date,
+			o.action AS override_action, o.reason AS override_reason, o.created_by_name AS override_by
+		FROM trx_lock_system tls WITH (nolock)
+		%s
+		WHERE (%s)`, constant.LOCK_TYPE_SYSTEM, constant.BANNED_TYPE_ASSET, constant.BANNED_TYPE_NIK,
+		fmt.Sprintf(lockOverrideApply, constant.LOCK_TYPE_SYSTEM, "tls"), strings.Join(lockConditions, " OR ")))
+
+	// banned pmk dsr is keyed by nik, its unban date is the end of the banned window unless overridden
+	if req.IDNumber != "" {
+		unions = append(unions, fmt.Sprintf(`SELECT '%s' AS lock_type, '%s' AS banned_type,
+				tbp.ProspectID, SCP.dbo.DEC_B64('SEC', tbp.IDNumber) AS IDNumber,
+				'' AS chassis_number, '' AS engine_number,
+				'%s' AS reason, tbp.created_at, ISNULL(CAST(o.unban_date AS DATE), DATEADD(DAY, %d, CAST(tbp.created_at AS DATE))) AS unban_date,
+				o.action AS override_action, o.reason AS override_reason, o.created_by_name AS override_by
+			FROM trx_banned_pmk_dsr tbp WITH (nolock)
+			%s
+			WHERE tbp.IDNumber = SCP.dbo.ENC_B64('SEC', ?)`, constant.LOCK_TYPE_BANNED_PMK_DSR, constant.BANNED_TYPE_NIK,
+			constant.LOCK_TYPE_BANNED_PMK_DSR, constant.LOCK_BANNED_DAYS+1,
+			fmt.Sprintf(lockOverrideApply, constant.LOCK_TYPE_BANNED_PMK_DSR, "tbp")))
+		args = append(args, req.IDNumber)
+	}
+
+	// banned chassis number is keyed by chassis number only
+	if req.ChassisNumber != "" {
+		unions = append(unions, fmt.Sprintf(`SELECT '%s' AS lock_type, '%s' AS banned_type,
+				tbc.ProspectID, '' AS IDNumber,
+				tbc.chassis_number, '' AS engine_number,
+				'%s' AS reason, tbc.created_at, ISNULL(CAST(o.unban_date AS DATE), DATEADD(DAY, %d, CAST(tbc.created_at AS DATE))) AS unban_date,
+				o.action AS override_action, o.reason AS override_reason, o.created_by_name AS override_by
+			FROM trx_banned_chassis_number tbc WITH (nolock)
+			%s
+			WHERE tbc.chassis_number = ?`, constant.LOCK_TYPE_BANNED_CHASSIS, constant.BANNED_TYPE_ASSET,
+			constant.LOCK_TYPE_BANNED_CHASSIS, constant.LOCK_BANNED_DAYS+1,
+			fmt.Sprintf(lockOverrideApply, constant.LOCK_TYPE_BANNED_CHASSIS, "tbc")))
+		args = append(args, req.ChassisNumber)
+	}
+
+	if req.IsActive != "" {
+		filter = fmt.Sprintf("WHERE y.is_active = %s", req.IsActive)
+	}
+
+	query := fmt.Sprintf(`SELECT * FROM (
+			SELECT u.*, CASE WHEN u.unban_date > CAST(GETDATE() AS DATE) THEN 1 ELSE 0 END AS is_active
+			FROM (%s) AS u
+		) AS y %s`, strings.Join(unions, " UNION ALL "), filter)
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw(fmt.Sprintf(`SELECT COUNT(*) AS totalRow FROM (%s) AS z`, query), args...).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`%s ORDER BY y.is_active DESC, y.created_at DESC %s`, query, filterPaginate), args...).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, 0, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+	return
+}
+
+// GetLockSystemEntry returns the current state of one lock row shaped as an override, IDNumber is kept as stored
+func (r repoHandler) GetLockSystemEntry(lockType, prospectID string) (data entity.TrxLockOverride, err error) {
+
+	var (
+		query string
+		x     sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	switch lockType {
+	case constant.LOCK_TYPE_SYSTEM:
+		query = fmt.Sprintf(`SELECT tls.ProspectID, tls.IDNumber, tls.chassis_number, tls.engine_number,
+				CASE WHEN tls.reason LIKE 'Asset %%' THEN '%s' ELSE '%s' END AS banned_type,
+				CAST(tls.unban_date AS DATE) AS unban_date
+			FROM trx_lock_system tls WITH (nolock)
+			WHERE tls.ProspectID = ?`, constant.BANNED_TYPE_ASSET, constant.BANNED_TYPE_NIK)
+	case constant.LOCK_TYPE_BANNED_PMK_DSR:
+		query = fmt.Sprintf(`SELECT tbp.ProspectID, tbp.IDNumber, '%s' AS banned_type,
+				ISNULL(CAST(o.unban_date AS DATE), DATEADD(DAY, %d, CAST(tbp.created_at AS DATE))) AS unban_date
+			FROM trx_banned_pmk_dsr tbp WITH (nolock)
+			%s
+			WHERE tbp.ProspectID = ?`, constant.BANNED_TYPE_NIK, constant.LOCK_BANNED_DAYS+1,
+			fmt.Sprintf(lockOverrideApply, constant.LOCK_TYPE_BANNED_PMK_DSR, "tbp"))
+	case constant.LOCK_TYPE_BANNED_CHASSIS:
+		query = fmt.Sprintf(`SELECT tbc.ProspectID, tbc.chassis_number, '%s' AS banned_type,
+				ISNULL(CAST(o.unban_date AS DATE), DATEADD(DAY, %d, CAST(tbc.created_at AS DATE))) AS unban_date
+			FROM trx_banned_chassis_number tbc WITH (nolock)
+			%s
+			WHERE tbc.ProspectID = ?`, constant.BANNED_TYPE_ASSET, constant.LOCK_BANNED_DAYS+1,
+			fmt.Sprintf(lockOverrideApply, constant.LOCK_TYPE_BANNED_CHASSIS, "tbc"))
+	default:
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Lock type tidak valid")
+		return
+	}
+
+	if err = db.Raw(query, prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	data.LockType = lockType
+
+	return
+}
+
+// SaveLockOverride moves the unban date of trx_lock_system and records the override, banned tables are only overridden through the record
+func (r repoHandler) SaveLockOverride(override entity.TrxLockOverride) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	if override.LockType == constant.LOCK_TYPE_SYSTEM {
+		result := db.Model(&entity.TrxLockSystem{}).
+			Where("ProspectID = ?", override.ProspectID).
+			Update("unban_date", override.UnbanDate)
+
+		if err = result.Error; err != nil {
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			err = errors.New(constant.ERROR_ROWS_AFFECTED)
+			return
+		}
+	}
+
+	if err = db.Create(&override).Error; err != nil {
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetLockOverrideHistory(lockType, prospectID string) (data []entity.TrxLockOverride, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT * FROM trx_lock_override WITH (nolock) WHERE lock_type = ? AND ProspectID = ? ORDER BY created_at DESC`, lockType, prospectID).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		err = errors.New(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
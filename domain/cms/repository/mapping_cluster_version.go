@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// replaceMappingCluster swaps the whole mapping cluster with data and stores it as a new version next to its change log
+func replaceMappingCluster(tx *gorm.DB, data []entity.MasterMappingCluster, history entity.HistoryConfigChanges, source string, rollbackFrom *int) (err error) {
+
+	var cluster entity.MasterMappingCluster
+	if err = tx.Delete(&cluster).Error; err != nil {
+		return
+	}
+
+	for _, val := range data {
+		if err = tx.Create(&val).Error; err != nil {
+			return
+		}
+	}
+
+	if err = tx.Create(&history).Error; err != nil {
+		return
+	}
+
+	err = insertMappingClusterVersion(tx, history, source, len(data), rollbackFrom)
+
+	return
+}
+
+// insertMappingClusterVersion stores data_after of the change as the next version,
+// the mapping before the first versioned change is kept as the initial version so it can be rolled back to
+func insertMappingClusterVersion(tx *gorm.DB, history entity.HistoryConfigChanges, source string, totalRows int, rollbackFrom *int) (err error) {
+
+	var latest struct {
+		Version int `gorm:"column:version"`
+	}
+
+	if err = tx.Raw(`SELECT ISNULL(MAX(version), 0) AS version FROM kmb_mapping_cluster_version WITH (updlock, holdlock)`).Scan(&latest).Error; err != nil {
+		return
+	}
+
+	if latest.Version == 0 {
+		var initial []entity.MasterMappingCluster
+		if history.DataBefore != "" {
+			if err = json.Unmarshal([]byte(history.DataBefore), &initial); err != nil {
+				return
+			}
+		}
+
+		if len(initial) > 0 {
+			latest.Version++
+			if err = tx.Create(&entity.MappingClusterVersion{
+				ID:        uuid.New().String(),
+				Version:   latest.Version,
+				Source:    constant.MAPPING_CLUSTER_INITIAL,
+				TotalRows: len(initial),
+				Data:      history.DataBefore,
+				CreatedBy: history.CreatedBy,
+				CreatedAt: history.CreatedAt,
+			}).Error; err != nil {
+				return
+			}
+		}
+	}
+
+	err = tx.Create(&entity.MappingClusterVersion{
+		ID:           uuid.New().String(),
+		Version:      latest.Version + 1,
+		Source:       source,
+		RollbackFrom: rollbackFrom,
+		TotalRows:    totalRows,
+		Data:         history.DataAfter,
+		HistoryID:    history.ID,
+		CreatedBy:    history.CreatedBy,
+		CreatedAt:    history.CreatedAt,
+	}).Error
+
+	return
+}
+
+// RollbackMappingCluster restores the snapshot of a version, the restore is recorded as a new version.
+// The current mapping is read under lock in the same transaction and returned as before, it fails with
+// ERROR_ROWS_AFFECTED when the mapping already equals the snapshot
+func (r repoHandler) RollbackMappingCluster(data []entity.MasterMappingCluster, history entity.HistoryConfigChanges, version int) (before []entity.MasterMappingCluster, err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.losDB.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	if err = db.Raw("SELECT * FROM kmb_mapping_cluster_branch WITH (updlock, holdlock) ORDER BY branch_id ASC").Scan(&before).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	if sameMappingCluster(before, data) {
+		err = errors.New(constant.ERROR_ROWS_AFFECTED)
+		return
+	}
+
+	dataBefore, err := json.Marshal(before)
+	if err != nil {
+		return
+	}
+	history.DataBefore = string(dataBefore)
+
+	err = replaceMappingCluster(db, data, history, constant.MAPPING_CLUSTER_ROLLBACK, &version)
+
+	return
+}
+
+func sameMappingCluster(before, after []entity.MasterMappingCluster) bool {
+
+	if len(before) != len(after) {
+		return false
+	}
+
+	clusters := make(map[string]string, len(before))
+	for _, mapping := range before {
+		clusters[fmt.Sprintf("%s-%s-%d", mapping.BranchID, mapping.CustomerStatus, mapping.BpkbNameType)] = mapping.Cluster
+	}
+
+	for _, mapping := range after {
+		cluster, exists := clusters[fmt.Sprintf("%s-%s-%d", mapping.BranchID, mapping.CustomerStatus, mapping.BpkbNameType)]
+		if !exists || cluster != mapping.Cluster {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r repoHandler) GetMappingClusterVersion(version int) (data entity.MappingClusterVersion, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT * FROM kmb_mapping_cluster_version WITH (nolock) WHERE version = ?`, version).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetMappingClusterVersions(pagination interface{}) (data []entity.InquiryMappingClusterVersion, rowTotal int, err error) {
+	var (
+		filterPaginate string
+		x              sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw(`SELECT COUNT(*) AS totalRow FROM kmb_mapping_cluster_version WITH (nolock)`).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT
+			kmcv.version, kmcv.source, kmcv.rollback_from, kmcv.total_rows,
+			FORMAT(kmcv.created_at, 'yyyy-MM-dd HH:mm:ss') AS created_at, ud.name AS user_name
+		FROM kmb_mapping_cluster_version kmcv WITH (nolock)
+		LEFT JOIN user_details ud ON ud.user_id = kmcv.created_by
+		ORDER BY kmcv.version DESC %s`, filterPaginate)).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, 0, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+	return
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// GetMasterData returns every row of a reference list including the inactive ones, ordered by sort_order.
+// Approval reasons and industry types are owned by confins, the rows edited from cms are kept in m_approval_reason and m_industry_type and replace the confins row with the same id.
+func (r repoHandler) GetMasterData(dataType string) (data []entity.MasterData, err error) {
+
+	var (
+		x       sql.TxOptions
+		overlay []entity.MasterData
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	switch dataType {
+	case constant.MASTER_DATA_CANCEL_REASON:
+		err = db.Raw(`SELECT CAST(id_cancel_reason AS varchar(20)) AS id, reason AS description, ISNULL(sort_order, 0) AS sort_order,
+			CASE WHEN show = '1' THEN 1 ELSE 0 END AS is_active, ISNULL(updated_by, '') AS updated_by, updated_at
+			FROM m_cancel_reason WITH (nolock) ORDER BY id_cancel_reason ASC`).Scan(&data).Error
+
+	case constant.MASTER_DATA_PRESCREENING_REASON:
+		err = db.Raw(`SELECT ReasonID AS id, Code AS code, ReasonMessage AS description, ISNULL(sort_order, 0) AS sort_order,
+			ISNULL(is_active, 1) AS is_active, ISNULL(updated_by, '') AS updated_by, updated_at
+			FROM m_reason_message WITH (nolock) ORDER BY ReasonID ASC`).Scan(&data).Error
+
+	case constant.MASTER_DATA_APPROVAL_REASON:
+		if err = r.confins.Raw(`SELECT CAST(ReasonID AS varchar(20)) AS id, [Type] AS group_id, Description AS description,
+			CASE WHEN IsActive = 'True' THEN 1 ELSE 0 END AS is_active
+			FROM tblApprovalReason ORDER BY ReasonID ASC`).Scan(&data).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return
+		}
+
+		err = db.Raw(`SELECT ReasonID AS id, [Type] AS group_id, Description AS description, sort_order, is_active, updated_by, updated_at
+			FROM m_approval_reason WITH (nolock)`).Scan(&overlay).Error
+
+	case constant.MASTER_DATA_INDUSTRY_TYPE:
+		var industry []entity.SpIndustryTypeMaster
+
+		if err = r.core.Raw("exec[spIndustryTypeMaster] '01/01/2007'").Scan(&industry).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return
+		}
+
+		for _, row := range industry {
+			data = append(data, entity.MasterData{
+				ID:          strings.TrimSpace(row.IndustryTypeID),
+				Description: row.Description,
+				IsActive:    row.IsActive,
+			})
+		}
+
+		err = db.Raw(`SELECT IndustryTypeID AS id, Description AS description, sort_order, is_active, updated_by, updated_at
+			FROM m_industry_type WITH (nolock)`).Scan(&overlay).Error
+	}
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	err = nil
+
+	data = mergeMasterData(data, overlay)
+
+	if len(data) == 0 {
+		err = fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
+
+func mergeMasterData(base, overlay []entity.MasterData) []entity.MasterData {
+
+	key := func(row entity.MasterData) string {
+		return strings.TrimSpace(row.ID) + "|" + strings.TrimSpace(row.Group)
+	}
+
+	index := map[string]int{}
+	for i, row := range base {
+		index[key(row)] = i
+	}
+
+	for _, row := range overlay {
+		if i, ok := index[key(row)]; ok {
+			base[i] = row
+			continue
+		}
+		base = append(base, row)
+	}
+
+	sort.SliceStable(base, func(i, j int) bool {
+		return base[i].SortOrder < base[j].SortOrder
+	})
+
+	return base
+}
+
+// SaveMasterData writes the rows and the history of the change in one new_kmb transaction, the history is copied to los
+// by SyncMasterDataHistory
+func (r repoHandler) SaveMasterData(dataType string, data []entity.MasterData, isNew bool, history entity.HistoryConfigChanges) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	for _, row := range data {
+
+		var result *gorm.DB
+
+		switch dataType {
+		case constant.MASTER_DATA_CANCEL_REASON:
+			show := "0"
+			if row.IsActive {
+				show = "1"
+			}
+
+			if isNew {
+				result = db.Exec(`INSERT INTO m_cancel_reason (id_cancel_reason, reason, show, sort_order, updated_by, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+					row.ID, row.Description, show, row.SortOrder, row.UpdatedBy, row.UpdatedAt)
+			} else {
+				result = db.Exec(`UPDATE m_cancel_reason SET reason = ?, show = ?, sort_order = ?, updated_by = ?, updated_at = ? WHERE id_cancel_reason = ?`,
+					row.Description, show, row.SortOrder, row.UpdatedBy, row.UpdatedAt, row.ID)
+			}
+
+		case constant.MASTER_DATA_PRESCREENING_REASON:
+			if isNew {
+				result = db.Exec(`INSERT INTO m_reason_message (ReasonID, Code, ReasonMessage, is_active, sort_order, updated_by, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+					row.ID, row.Code, row.Description, row.IsActive, row.SortOrder, row.UpdatedBy, row.UpdatedAt)
+			} else {
+				result = db.Exec(`UPDATE m_reason_message SET Code = ?, ReasonMessage = ?, is_active = ?, sort_order = ?, updated_by = ?, updated_at = ? WHERE ReasonID = ?`,
+					row.Code, row.Description, row.IsActive, row.SortOrder, row.UpdatedBy, row.UpdatedAt, row.ID)
+			}
+
+		case constant.MASTER_DATA_APPROVAL_REASON:
+			result = db.Exec(`UPDATE m_approval_reason SET Description = ?, is_active = ?, sort_order = ?, updated_by = ?, updated_at = ? WHERE ReasonID = ? AND [Type] = ?`,
+				row.Description, row.IsActive, row.SortOrder, row.UpdatedBy, row.UpdatedAt, row.ID, row.Group)
+
+			if result.Error == nil && result.RowsAffected == 0 {
+				result = db.Exec(`INSERT INTO m_approval_reason (ReasonID, [Type], Description, is_active, sort_order, updated_by, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+					row.ID, row.Group, row.Description, row.IsActive, row.SortOrder, row.UpdatedBy, row.UpdatedAt)
+			}
+
+		case constant.MASTER_DATA_INDUSTRY_TYPE:
+			result = db.Exec(`UPDATE m_industry_type SET Description = ?, is_active = ?, sort_order = ?, updated_by = ?, updated_at = ? WHERE IndustryTypeID = ?`,
+				row.Description, row.IsActive, row.SortOrder, row.UpdatedBy, row.UpdatedAt, row.ID)
+
+			if result.Error == nil && result.RowsAffected == 0 {
+				result = db.Exec(`INSERT INTO m_industry_type (IndustryTypeID, Description, is_active, sort_order, updated_by, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+					row.ID, row.Description, row.IsActive, row.SortOrder, row.UpdatedBy, row.UpdatedAt)
+			}
+
+		default:
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - type tidak dikenal")
+			return
+		}
+
+		if err = result.Error; err != nil {
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			err = errors.New(constant.ERROR_ROWS_AFFECTED)
+			return
+		}
+	}
+
+	err = db.Create(&entity.TrxHistoryConfigOutbox{
+		ID:         history.ID,
+		ConfigID:   history.ConfigID,
+		ObjectName: history.ObjectName,
+		Action:     history.Action,
+		DataBefore: history.DataBefore,
+		DataAfter:  history.DataAfter,
+		CreatedBy:  history.CreatedBy,
+		CreatedAt:  history.CreatedAt,
+	}).Error
+
+	return
+}
+
+// SyncMasterDataHistory copies the pending outbox rows to history_config_changes, rows claimed by another replica are
+// skipped and a row already copied before a failure is not copied twice
+func (r repoHandler) SyncMasterDataHistory(limit int) (synced int, err error) {
+
+	var outbox []entity.TrxHistoryConfigOutbox
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	if err = db.Raw(fmt.Sprintf(`SELECT TOP (%d) * FROM trx_history_config_outbox WITH (updlock, readpast, rowlock)
+		WHERE synced_at IS NULL ORDER BY created_at ASC`, limit)).Scan(&outbox).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	for _, row := range outbox {
+		if err = r.losDB.Exec(`INSERT INTO history_config_changes (id, config_id, object_name, action, data_before, data_after, created_by, created_at)
+			SELECT ?, ?, ?, ?, ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM history_config_changes WHERE id = ?)`,
+			row.ID, row.ConfigID, row.ObjectName, row.Action, row.DataBefore, row.DataAfter, row.CreatedBy, row.CreatedAt, row.ID).Error; err != nil {
+			return
+		}
+
+		if err = db.Exec("UPDATE trx_history_config_outbox SET synced_at = GETDATE() WHERE id = ?", row.ID).Error; err != nil {
+			return
+		}
+
+		synced++
+	}
+
+	return
+}
+
+func (r repoHandler) GetMasterDataHistory(configID string, pagination interface{}) (data []entity.MasterDataChangeLog, rowTotal int, err error) {
+
+	var (
+		filterPaginate string
+		x              sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw("SELECT COUNT(*) AS totalRow FROM history_config_changes WITH (nolock) WHERE config_id = ?", configID).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT
+			hcc.id, hcc.action, hcc.data_before, hcc.data_after, hcc.created_at, ud.name AS user_name
+		FROM history_config_changes hcc WITH (nolock)
+		LEFT JOIN user_details ud ON ud.user_id = hcc.created_by
+		WHERE hcc.config_id = ?
+		ORDER BY hcc.created_at DESC %s`, filterPaginate), configID).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, 0, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
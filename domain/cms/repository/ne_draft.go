@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	jsoniter "github.com/json-iterator/go"
+)
+
+func (r repoHandler) SaveNEDraft(draft entity.TrxNEDraft) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Create(&draft).Error
+
+	return
+}
+
+// UpdateNEDraft changes an open draft of the creator, ERROR_ROWS_AFFECTED is returned when there is no such draft
+func (r repoHandler) UpdateNEDraft(id, createdBy string, fields map[string]interface{}) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	result := db.Model(&entity.TrxNEDraft{}).Where("id = ? AND created_by = ? AND status = ?", id, createdBy, constant.NE_DRAFT_STATUS_DRAFT).Updates(fields)
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = errors.New(constant.ERROR_ROWS_AFFECTED)
+	}
+
+	return
+}
+
+func (r repoHandler) GetNEDraft(id, createdBy string) (data entity.TrxNEDraft, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT * FROM trx_ne_draft WITH (nolock) WHERE id = ? AND created_by = ? AND status = ?", id, createdBy, constant.NE_DRAFT_STATUS_DRAFT).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetNEDrafts(req request.ReqListNEDraft, pagination interface{}) (data []entity.InquiryNEDraft, rowTotal int, err error) {
+
+	var (
+		filterPaginate string
+		x              sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw("SELECT COUNT(*) AS totalRow FROM trx_ne_draft WITH (nolock) WHERE created_by = ? AND status = ?", req.CreatedBy, constant.NE_DRAFT_STATUS_DRAFT).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT id, BranchID, ProspectID, status, created_at, updated_at FROM trx_ne_draft WITH (nolock)
+		WHERE created_by = ? AND status = ? ORDER BY updated_at DESC %s`, filterPaginate), req.CreatedBy, constant.NE_DRAFT_STATUS_DRAFT).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, 0, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
+
+// GetMappingIncomePMK returns the minimum income of every customer status of the branch, the default branch is used when the branch has none
+func (r repoHandler) GetMappingIncomePMK(branchID string) (data []entity.MappingIncomePMK, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	for _, branch := range []string{branchID, constant.DEFAULT_BRANCH_ID} {
+		if err = db.Raw("SELECT * FROM mapping_income_pmk WITH (nolock) WHERE lob = 'los_kmb_off' AND branch_id = ?", branch).Scan(&data).Error; err != nil && err != gorm.ErrRecordNotFound {
+			return
+		}
+
+		err = nil
+
+		if len(data) > 0 {
+			return
+		}
+	}
+
+	return
+}
+
+func (r repoHandler) GetBranchClusters(branchID string, bpkbNameType int) (data []entity.MasterMappingCluster, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT * FROM kmb_mapping_cluster_branch WITH (nolock) WHERE branch_id = ? AND bpkb_name_type = ?", branchID, bpkbNameType).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
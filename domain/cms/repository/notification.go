@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+)
+
+func (r repoHandler) GetNotificationPreferences(userID string) (data []entity.MappingNotificationPreference, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT user_id, event_type, channel, is_muted, updated_by, updated_at FROM m_notification_preference WITH (nolock) WHERE user_id = ? ORDER BY event_type, channel", userID).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
+
+func (r repoHandler) SaveNotificationPreference(data entity.MappingNotificationPreference) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	result := db.Model(&entity.MappingNotificationPreference{}).Where("user_id = ? AND event_type = ? AND channel = ?", data.UserID, data.EventType, data.Channel).Updates(map[string]interface{}{
+		"is_muted":   data.IsMuted,
+		"updated_by": data.UpdatedBy,
+		"updated_at": data.UpdatedAt,
+	})
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = db.Create(&data).Error
+	}
+
+	return
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// GetOrderClaim returns the claim of an order, a claim without activity past the timeout is treated as released
+func (r repoHandler) GetOrderClaim(prospectID string) (data entity.TrxOrderClaim, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	query := fmt.Sprintf(`SELECT * FROM trx_order_claim WITH (nolock) WHERE ProspectID = ? AND last_activity_at > DATEADD(MINUTE, -%d, GETDATE())`, constant.ORDER_CLAIM_TIMEOUT)
+
+	if err = db.Raw(query, prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+// ClaimOrder takes the claim when the order is free, expired or already held by the same user,
+// ERROR_ROWS_AFFECTED is returned when another user holds the claim
+func (r repoHandler) ClaimOrder(claim entity.TrxOrderClaim, history entity.TrxOrderClaimHistory) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	var existing []entity.TrxOrderClaim
+
+	if err = db.Raw(`SELECT ProspectID FROM trx_order_claim WITH (updlock, rowlock) WHERE ProspectID = ?`, claim.ProspectID).Scan(&existing).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+	err = nil
+
+	if len(existing) == 0 {
+		if err = db.Exec(`INSERT INTO trx_order_claim (ProspectID, claimed_by, claimed_by_name, claimed_at, last_activity_at) VALUES (?, ?, ?, GETDATE(), GETDATE())`,
+			claim.ProspectID, claim.ClaimedBy, claim.ClaimedByName).Error; err != nil {
+			return
+		}
+	} else {
+		query := fmt.Sprintf(`UPDATE trx_order_claim SET claimed_at = CASE WHEN claimed_by = ? THEN claimed_at ELSE GETDATE() END,
+		claimed_by = ?, claimed_by_name = ?, last_activity_at = GETDATE()
+		WHERE ProspectID = ? AND (claimed_by = ? OR last_activity_at <= DATEADD(MINUTE, -%d, GETDATE()))`, constant.ORDER_CLAIM_TIMEOUT)
+
+		result := db.Exec(query, claim.ClaimedBy, claim.ClaimedBy, claim.ClaimedByName, claim.ProspectID, claim.ClaimedBy)
+		if err = result.Error; err != nil {
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			err = errors.New(constant.ERROR_ROWS_AFFECTED)
+			return
+		}
+	}
+
+	err = db.Create(&history).Error
+
+	return
+}
+
+// TouchOrderClaim extends the claim of the holder, nothing is updated for other users
+func (r repoHandler) TouchOrderClaim(prospectID, userID string) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec(`UPDATE trx_order_claim SET last_activity_at = GETDATE() WHERE ProspectID = ? AND claimed_by = ?`, prospectID, userID).Error
+
+	return
+}
+
+// ReleaseOrderClaim removes the claim of an order, an empty userID releases the claim of any holder
+func (r repoHandler) ReleaseOrderClaim(prospectID, userID string, history entity.TrxOrderClaimHistory) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	var claim entity.TrxOrderClaim
+
+	if err = db.Raw(`SELECT * FROM trx_order_claim WITH (updlock, rowlock) WHERE ProspectID = ?`, prospectID).Scan(&claim).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	if userID != "" && claim.ClaimedBy != userID {
+		err = errors.New(constant.RECORD_NOT_FOUND)
+		return
+	}
+
+	if err = db.Exec(`DELETE FROM trx_order_claim WHERE ProspectID = ?`, prospectID).Error; err != nil {
+		return
+	}
+
+	history.HolderBy = claim.ClaimedBy
+
+	err = db.Create(&history).Error
+
+	return
+}
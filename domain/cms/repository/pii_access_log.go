@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"los-kmb-api/models/entity"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const piiAccessLogBatchSize = 300
+
+func (r repoHandler) SavePIIAccessLog(logs []entity.TrxPIIAccessLog) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	// one insert per batch, a batch stays under the 2100 parameters of sql server
+	for start := 0; start < len(logs); start += piiAccessLogBatchSize {
+
+		end := start + piiAccessLogBatchSize
+		if end > len(logs) {
+			end = len(logs)
+		}
+
+		var (
+			values []string
+			args   []interface{}
+		)
+
+		for _, accessLog := range logs[start:end] {
+			values = append(values, "(?, ?, ?, ?, ?, ?)")
+			args = append(args, accessLog.ID, accessLog.ProspectID, accessLog.UserID, accessLog.Role, accessLog.Purpose, accessLog.CreatedAt)
+		}
+
+		if err = db.Exec("INSERT INTO trx_pii_access_log (id, ProspectID, user_id, role, purpose, created_at) VALUES "+strings.Join(values, ", "), args...).Error; err != nil {
+			return
+		}
+	}
+
+	return
+}
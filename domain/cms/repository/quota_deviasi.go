@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jinzhu/gorm"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// insertQuotaDeviasiLedger writes a booking or release of an order into the ledger of the current period of its branch
+func insertQuotaDeviasiLedger(tx *gorm.DB, prospectID, branchID, action, reason string, amount float64, createdBy string) error {
+
+	return tx.Exec(`INSERT INTO trx_quota_deviasi_ledger (id, ProspectID, BranchID, period_start, action, amount, account, reason, created_by, created_at)
+		SELECT ?, ?, BranchID, period_start, ?, ?, 1, ?, ?, GETDATE() FROM m_branch_deviasi WHERE BranchID = ?`,
+		uuid.New().String(), prospectID, action, amount, reason, createdBy, branchID).Error
+}
+
+// releaseQuotaDeviasi gives back the quota booked by an order, an order has at most one release per booking.
+// The balance is only restored while the booking is still in the current period, a reset has already cleared older bookings
+func releaseQuotaDeviasi(tx *gorm.DB, prospectID, reason, createdBy string) (released bool, err error) {
+
+	var booking entity.QuotaDeviasiBooking
+
+	if err = tx.Raw(`SELECT c.booking_count, c.release_count, b.BranchID, b.amount,
+			CASE WHEN b.period_start = mbd.period_start OR (b.period_start IS NULL AND mbd.period_start IS NULL) THEN 1 ELSE 0 END AS same_period
+		FROM (
+			SELECT ISNULL(SUM(CASE WHEN action = ? THEN 1 ELSE 0 END), 0) AS booking_count,
+				ISNULL(SUM(CASE WHEN action = ? THEN 1 ELSE 0 END), 0) AS release_count
+			FROM trx_quota_deviasi_ledger WITH (updlock, holdlock) WHERE ProspectID = ?
+		) AS c
+		OUTER APPLY (
+			SELECT TOP 1 BranchID, amount, period_start FROM trx_quota_deviasi_ledger WITH (nolock)
+			WHERE ProspectID = ? AND action = ? ORDER BY created_at DESC
+		) AS b
+		LEFT JOIN m_branch_deviasi mbd WITH (nolock) ON b.BranchID = mbd.BranchID`,
+		constant.QUOTA_LEDGER_BOOKING, constant.QUOTA_LEDGER_RELEASE, prospectID, prospectID, constant.QUOTA_LEDGER_BOOKING).Scan(&booking).Error; err != nil {
+		return
+	}
+
+	if booking.BookingCount == 0 && booking.ReleaseCount == 0 {
+		return releaseLegacyQuotaDeviasi(tx, prospectID, reason, createdBy)
+	}
+
+	if booking.BookingCount <= booking.ReleaseCount {
+		return
+	}
+
+	if booking.SamePeriod {
+		if err = tx.Exec(`UPDATE m_branch_deviasi
+			SET booking_amount = booking_amount - ?, booking_account = booking_account - 1,
+				balance_amount = balance_amount + ?, balance_account = balance_account + 1
+			WHERE BranchID = ?`, booking.Amount, booking.Amount, booking.BranchID).Error; err != nil {
+			return
+		}
+	}
+
+	// the release belongs to the period of its booking so the consumption of that period stays right
+	if err = tx.Exec(`INSERT INTO trx_quota_deviasi_ledger (id, ProspectID, BranchID, period_start, action, amount, account, reason, created_by, created_at)
+		SELECT TOP 1 ?, ProspectID, BranchID, period_start, ?, amount, 1, ?, ?, GETDATE()
+		FROM trx_quota_deviasi_ledger WHERE ProspectID = ? AND action = ? ORDER BY created_at DESC`,
+		uuid.New().String(), constant.QUOTA_LEDGER_RELEASE, reason, createdBy, prospectID, constant.QUOTA_LEDGER_BOOKING).Error; err != nil {
+		return
+	}
+
+	return true, nil
+}
+
+// releaseLegacyQuotaDeviasi releases an approved deviasi of a new customer booked before the ledger existed
+func releaseLegacyQuotaDeviasi(tx *gorm.DB, prospectID, reason, createdBy string) (released bool, err error) {
+
+	var resultCheckDeviation entity.ResultCheckDeviation
+
+	selectQuery := `
+		SELECT mbd.BranchID, ta.NTF, tf.customer_status, tfa.decision
+		FROM trx_deviasi AS td WITH (nolock)
+		LEFT JOIN trx_apk AS ta ON (td.ProspectID = ta.ProspectID)
+		LEFT JOIN trx_master AS tm ON (td.ProspectID = tm.ProspectID)
+		LEFT JOIN m_branch_deviasi AS mbd ON (tm.BranchID = mbd.BranchID)
+		LEFT JOIN trx_filtering AS tf ON (td.ProspectID = tf.prospect_id)
+		LEFT JOIN trx_final_approval AS tfa ON (td.ProspectID = tfa.ProspectID)
+		WHERE ta.ProspectID = ? AND mbd.is_active = 1
+	`
+	if err = tx.Raw(selectQuery, prospectID).Scan(&resultCheckDeviation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	if resultCheckDeviation.BranchID == "" || resultCheckDeviation.CustomerStatus != constant.STATUS_KONSUMEN_NEW {
+		return
+	}
+
+	if decisionStr, ok := resultCheckDeviation.Decision.(string); !ok || decisionStr != constant.DB_DECISION_APR {
+		return
+	}
+
+	updateQuery := `
+		UPDATE m_branch_deviasi
+		SET booking_amount = booking_amount - ?,
+			booking_account = booking_account - 1,
+			balance_amount = (quota_amount - booking_amount) + ?,
+			balance_account = (quota_account - booking_account) + 1
+		WHERE BranchID = ? AND is_active = 1
+	`
+	if err = tx.Exec(updateQuery, resultCheckDeviation.NTF, resultCheckDeviation.NTF, resultCheckDeviation.BranchID).Error; err != nil {
+		return
+	}
+
+	if err = insertQuotaDeviasiLedger(tx, prospectID, resultCheckDeviation.BranchID, constant.QUOTA_LEDGER_RELEASE, reason, resultCheckDeviation.NTF, createdBy); err != nil {
+		return
+	}
+
+	return true, nil
+}
+
+// ReleaseQuotaDeviasi releases the quota of an order closed outside the cms transactions
+func (r repoHandler) ReleaseQuotaDeviasi(prospectID, reason, releasedBy string) (released bool, err error) {
+
+	err = r.NewKmb.Transaction(func(tx *gorm.DB) error {
+		var errRelease error
+		released, errRelease = releaseQuotaDeviasi(tx, prospectID, reason, releasedBy)
+		return errRelease
+	})
+
+	return
+}
+
+// archiveQuotaDeviasiPeriod closes the current period of the active branches, an empty branchID archives every branch
+func archiveQuotaDeviasiPeriod(tx *gorm.DB, branchID, resetType, createdBy string) error {
+
+	query := `INSERT INTO trx_quota_deviasi_period (id, BranchID, period_start, period_end, quota_amount, quota_account, booking_amount, booking_account, balance_amount, balance_account, reset_type, created_by, created_at)
+		SELECT NEWID(), BranchID, period_start, GETDATE(), quota_amount, quota_account, booking_amount, booking_account, balance_amount, balance_account, ?, ?, GETDATE()
+		FROM m_branch_deviasi WHERE is_active = 1`
+
+	if branchID == "" {
+		return tx.Exec(query, resetType, createdBy).Error
+	}
+
+	return tx.Exec(query+" AND BranchID = ?", resetType, createdBy, branchID).Error
+}
+
+// GetQuotaDeviasiConsumption returns the bookings and releases of the ledger per branch per period, the open period has no period_end
+func (r repoHandler) GetQuotaDeviasiConsumption(req request.ReqListQuotaDeviasiConsumption, pagination interface{}) (data []entity.InquiryQuotaDeviasiConsumption, rowTotal int, err error) {
+
+	var (
+		filter         string
+		filterPaginate string
+		x              sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if req.BranchID != "" {
+		numbers := strings.Split(req.BranchID, ",")
+		for i, number := range numbers {
+			numbers[i] = "'" + number + "'"
+		}
+		filter = fmt.Sprintf("WHERE tql.BranchID IN (%s)", strings.Join(numbers, ","))
+	}
+
+	consumption := fmt.Sprintf(`SELECT tql.BranchID, tql.period_start,
+			SUM(CASE WHEN tql.action = '%[1]s' THEN tql.amount ELSE 0 END) AS booking_amount,
+			SUM(CASE WHEN tql.action = '%[1]s' THEN tql.account ELSE 0 END) AS booking_account,
+			SUM(CASE WHEN tql.action = '%[2]s' THEN tql.amount ELSE 0 END) AS release_amount,
+			SUM(CASE WHEN tql.action = '%[2]s' THEN tql.account ELSE 0 END) AS release_account
+		FROM trx_quota_deviasi_ledger tql WITH (nolock) %[3]s
+		GROUP BY tql.BranchID, tql.period_start`, constant.QUOTA_LEDGER_BOOKING, constant.QUOTA_LEDGER_RELEASE, filter)
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw(fmt.Sprintf(`SELECT COUNT(*) AS totalRow FROM (%s) AS y`, consumption)).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT c.BranchID, cb.BranchName AS branch_name,
+			ISNULL(FORMAT(c.period_start, 'yyyy-MM-dd HH:mm:ss'), '') AS period_start,
+			ISNULL(FORMAT(tqp.period_end, 'yyyy-MM-dd HH:mm:ss'), '') AS period_end,
+			ISNULL(tqp.quota_amount, mbd.quota_amount) AS quota_amount,
+			ISNULL(tqp.quota_account, mbd.quota_account) AS quota_account,
+			c.booking_amount, c.booking_account, c.release_amount, c.release_account,
+			c.booking_amount - c.release_amount AS used_amount,
+			c.booking_account - c.release_account AS used_account,
+			ISNULL(tqp.reset_type, '') AS reset_type
+		FROM (%s) AS c
+		JOIN confins_branch AS cb ON (c.BranchID = cb.BranchID)
+		LEFT JOIN m_branch_deviasi AS mbd WITH (nolock) ON (c.BranchID = mbd.BranchID)
+		OUTER APPLY (
+			SELECT TOP 1 p.* FROM trx_quota_deviasi_period AS p WITH (nolock)
+			WHERE p.BranchID = c.BranchID AND (p.period_start = c.period_start OR (p.period_start IS NULL AND c.period_start IS NULL))
+			ORDER BY p.period_end DESC
+		) AS tqp
+		ORDER BY c.period_start DESC, c.BranchID ASC %s`, consumption, filterPaginate)).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, 0, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+func (r repoHandler) GetRecalculateHistory(prospectID string) (data []entity.TrxRecalculateHistory, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT * FROM trx_recalculate_history WITH (nolock) WHERE ProspectID = ? ORDER BY created_at ASC", prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// GetRecalculateEvents returns the returns and recalculation requests of the order, oldest first
+func (r repoHandler) GetRecalculateEvents(prospectID string) (data []entity.TrxHistoryApprovalScheme, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT * FROM trx_history_approval_scheme WITH (nolock) WHERE ProspectID = ? AND decision IN (?, ?) ORDER BY created_at ASC",
+		prospectID, constant.DB_DECISION_SDP, constant.DB_DECISION_RTN).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// GetRecalculateCurrent returns the figures the order has now
+func (r repoHandler) GetRecalculateCurrent(prospectID string) (data entity.TrxRecalculate, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT ta.ProspectID, ta.Tenor, ta.DPAmount, ta.percent_dp, ta.loan_amount, ta.NTF, ta.NTFAkumulasi, ta.AF, ta.InstallmentAmount,
+		ISNULL(tak.DSRFMF, 0) AS DSRFMF, ISNULL(tak.TotalDSR, 0) AS TotalDSR
+		FROM trx_apk ta WITH (nolock)
+		LEFT JOIN trx_akkk tak WITH (nolock) ON ta.ProspectID = tak.ProspectID
+		WHERE ta.ProspectID = ?`, prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+// GetRecalculateLimit returns what the ltv and dsr limits of the order are evaluated with
+func (r repoHandler) GetRecalculateLimit(prospectID string) (data entity.RecalculateLimit, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT ta.OTR,
+		ISNULL(CASE WHEN mmel.ltv IS NULL THEN mmelovd.ltv ELSE mmel.ltv END, 0) AS max_ltv,
+		ISNULL(tf.customer_status, '') AS customer_status, ISNULL(tf.customer_segment, '') AS customer_segment, ISNULL(tf.cluster, '') AS cluster,
+		ISNULL(tce.MonthlyFixedIncome, 0) AS MonthlyFixedIncome, ISNULL(tce.MonthlyVariableIncome, 0) AS MonthlyVariableIncome, ISNULL(tce.SpouseIncome, 0) AS SpouseIncome,
+		ISNULL(tak.InstallmentAmountFMF, 0) AS InstallmentAmountFMF, ISNULL(tak.InstallmentAmountSpouseFMF, 0) AS InstallmentAmountSpouseFMF,
+		ISNULL(tak.InstallmentAmountOther, 0) AS InstallmentAmountOther, ISNULL(tak.InstallmentAmountOtherSpouse, 0) AS InstallmentAmountOtherSpouse,
+		ISNULL(tak.InstallmentTopup, 0) AS InstallmentTopup, ISNULL(tak.DSRPBK, 0) AS DSRPBK
+		FROM trx_apk ta WITH (nolock)
+		LEFT JOIN trx_filtering tf WITH (nolock) ON ta.ProspectID = tf.prospect_id
+		LEFT JOIN trx_customer_employment tce WITH (nolock) ON ta.ProspectID = tce.ProspectID
+		LEFT JOIN trx_akkk tak WITH (nolock) ON ta.ProspectID = tak.ProspectID
+		LEFT JOIN trx_elaborate_ltv tel WITH (nolock) ON ta.ProspectID = tel.prospect_id
+		LEFT JOIN m_mapping_elaborate_ltv mmel WITH (nolock) ON tel.m_mapping_elaborate_ltv_id = mmel.id
+		LEFT JOIN m_mapping_elaborate_ltv_ovd mmelovd WITH (nolock) ON tel.m_mapping_elaborate_ltv_id = mmelovd.id
+		WHERE ta.ProspectID = ?`, prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
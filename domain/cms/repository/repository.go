@@ -51,6 +51,81 @@ func (r repoHandler) GetAFMobilePhone(prospectID string) (data entity.AFMobilePh
 	return
 }
 
+func (r repoHandler) GetNTFAkumulasi(prospectID string) (ntfAkumulasi float64, err error) {
+
+	var (
+		data entity.TrxApk
+		x    sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT NTFAkumulasi FROM trx_apk WITH (nolock) WHERE ProspectID = ?`, prospectID).Scan(&data).Error; err != nil {
+		return
+	}
+
+	ntfAkumulasi = data.NTFAkumulasi
+
+	return
+}
+
+// GetApprovalState returns the stage an order is waiting at and the final approval level set by the ca decision
+func (r repoHandler) GetApprovalState(prospectID string) (data entity.ApprovalState, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT tst.status_process, tst.decision, tst.source_decision, tcd.final_approval
+		FROM trx_status tst WITH (nolock)
+		LEFT JOIN trx_ca_decision tcd WITH (nolock) ON tst.ProspectID = tcd.ProspectID
+		WHERE tst.ProspectID = ?`, prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error) {
+
+	if err = r.losDB.Raw("SELECT [value] FROM app_config WITH (nolock) WHERE group_name = ? AND lob = ? AND [key] = ? AND is_active = 1", groupName, lob, key).Scan(&appConfig).Error; err != nil {
+		return
+	}
+
+	return
+}
+
+// slaFilter keeps the open orders whose current stage did or did not breach its sla, the flag is refreshed by the sla scheduler
+func slaFilter(agingFilter string) string {
+
+	breached := `EXISTS (SELECT 1 FROM trx_sla_aging tsa WITH (nolock)
+		WHERE tsa.ProspectID = tm.ProspectID AND tsa.stage = tst.source_decision AND tsa.stage_entered_at = tst.created_at AND tsa.is_breached = 1)`
+
+	switch agingFilter {
+	case constant.SLA_FILTER_BREACHED:
+		return fmt.Sprintf(" AND tst.status_process = '%s' AND %s", constant.STATUS_ONPROCESS, breached)
+	case constant.SLA_FILTER_ON_TRACK:
+		return fmt.Sprintf(" AND tst.status_process = '%s' AND NOT %s", constant.STATUS_ONPROCESS, breached)
+	}
+
+	return ""
+}
+
 func (r repoHandler) GetRegionBranch(userId string) (data []entity.RegionBranch, err error) {
 
 	if err = r.losDB.Raw(fmt.Sprintf(`SELECT region_name, branch_member FROM region_branch a WITH (nolock)
@@ -73,13 +148,18 @@ func (r repoHandler) GetRegionBranch(userId string) (data []entity.RegionBranch,
 
 func (r repoHandler) GetSpIndustryTypeMaster() (data []entity.SpIndustryTypeMaster, err error) {
 
-	if err = r.core.Raw("exec[spIndustryTypeMaster] '01/01/2007'").Scan(&data).Error; err != nil {
+	// industry types edited from cms replace the ones of spIndustryTypeMaster
+	rows, err := r.GetMasterData(constant.MASTER_DATA_INDUSTRY_TYPE)
+	if err != nil {
 		return
 	}
 
-	if len(data) == 0 {
-		err = fmt.Errorf(constant.RECORD_NOT_FOUND)
-		return
+	for _, row := range rows {
+		data = append(data, entity.SpIndustryTypeMaster{
+			IndustryTypeID: row.ID,
+			Description:    row.Description,
+			IsActive:       row.IsActive,
+		})
 	}
 
 	return
@@ -124,7 +204,7 @@ func (r repoHandler) GetReasonPrescreening(req request.ReqReasonPrescreening, pa
 		SELECT
 		COUNT(tt.ReasonID) AS totalRow
 		FROM
-		(SELECT ReasonID FROM m_reason_message WITH (nolock)) AS tt %s`, filter)).Scan(&row).Error; err != nil {
+		(SELECT ReasonID FROM m_reason_message WITH (nolock) WHERE ISNULL(is_active, 1) = 1) AS tt %s`, filter)).Scan(&row).Error; err != nil {
 			return
 		}
 
@@ -139,7 +219,7 @@ func (r repoHandler) GetReasonPrescreening(req request.ReqReasonPrescreening, pa
 	db := r.NewKmb.BeginTx(ctx, &x)
 	defer db.Commit()
 
-	if err = r.NewKmb.Raw(fmt.Sprintf(`SELECT tt.* FROM (SELECT Code, ReasonID, ReasonMessage FROM m_reason_message WITH (nolock)) AS tt %s ORDER BY tt.ReasonID asc %s`, filter, filterPaginate)).Scan(&reason).Error; err != nil {
+	if err = r.NewKmb.Raw(fmt.Sprintf(`SELECT tt.* FROM (SELECT Code, ReasonID, ReasonMessage, ISNULL(sort_order, 0) AS sort_order FROM m_reason_message WITH (nolock) WHERE ISNULL(is_active, 1) = 1) AS tt %s ORDER BY tt.sort_order ASC, tt.ReasonID asc %s`, filter, filterPaginate)).Scan(&reason).Error; err != nil {
 		return
 	}
 
@@ -189,7 +269,7 @@ func (r repoHandler) GetCancelReason(pagination interface{}) (reason []entity.Ca
 	db := r.NewKmb.BeginTx(ctx, &x)
 	defer db.Commit()
 
-	if err = r.NewKmb.Raw(fmt.Sprintf(`SELECT * FROM m_cancel_reason with (nolock) WHERE show = '1' ORDER BY id_cancel_reason ASC %s`, filterPaginate)).Scan(&reason).Error; err != nil {
+	if err = r.NewKmb.Raw(fmt.Sprintf(`SELECT * FROM m_cancel_reason with (nolock) WHERE show = '1' ORDER BY ISNULL(sort_order, 0) ASC, id_cancel_reason ASC %s`, filterPaginate)).Scan(&reason).Error; err != nil {
 		return
 	}
 
@@ -200,19 +280,56 @@ func (r repoHandler) GetCancelReason(pagination interface{}) (reason []entity.Ca
 }
 
 func (r repoHandler) GetApprovalReason(req request.ReqApprovalReason, pagination interface{}) (reason []entity.ApprovalReason, rowTotal int, err error) {
-	var x sql.TxOptions
-
-	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
-
 	var (
-		filterPaginate string
+		x              sql.TxOptions
+		overlay        []entity.MasterData
+		overlayRows    []string
+		args           []interface{}
 		filter         string
+		filterPaginate string
 	)
 
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	// the approval reasons edited from cms are kept in new_kmb, they are joined into the confins list as a derived
+	// table so the list is still filtered and paginated by sql
+	if err = r.NewKmb.Raw(`SELECT ReasonID AS id, [Type] AS group_id, Description AS description, ISNULL(sort_order, 0) AS sort_order, is_active
+		FROM m_approval_reason WITH (nolock)`).Scan(&overlay).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	for _, row := range overlay {
+		overlayRows = append(overlayRows, "SELECT ? AS id, ? AS [Type], ? AS Description, ? AS sort_order, ? AS is_active")
+		args = append(args, strings.TrimSpace(row.ID), strings.TrimSpace(row.Group), row.Description, row.SortOrder, row.IsActive)
+	}
+
+	overlayQuery := "SELECT CAST(NULL AS varchar(20)) AS id, CAST(NULL AS varchar(50)) AS [Type], CAST(NULL AS varchar(255)) AS Description, 0 AS sort_order, CAST(0 AS bit) AS is_active WHERE 1 = 0"
+	if len(overlayRows) > 0 {
+		overlayQuery = strings.Join(overlayRows, " UNION ALL ")
+	}
+
+	// an edited row replaces the confins row with the same id and type, an edited row out of confins keeps its place by id
+	reasonQuery := fmt.Sprintf(`WITH overlay AS (%s),
+		reason AS (
+			SELECT CAST(tar.ReasonID AS varchar(20)) AS id, tar.[Type], tar.Description, 0 AS sort_order, tar.ReasonID AS position
+			FROM tblApprovalReason tar WHERE tar.IsActive = 'True'
+			AND NOT EXISTS (SELECT 1 FROM overlay o WHERE o.id = CAST(tar.ReasonID AS varchar(20)) AND o.[Type] = tar.[Type])
+			UNION ALL
+			SELECT o.id, o.[Type], o.Description, o.sort_order, ISNULL(TRY_CAST(o.id AS int), 2147483647) AS position
+			FROM overlay o WHERE o.is_active = 1
+		)`, overlayQuery)
+
 	if req.Type != "" {
-		filter = fmt.Sprintf(" AND [Type] = '%s'", req.Type)
+		filter = "WHERE [Type] = ?"
+		args = append(args, req.Type)
 	}
 
+	db := r.confins.BeginTx(ctx, &x)
+	defer db.Commit()
+
 	if pagination != nil {
 		page, _ := json.Marshal(pagination)
 		var paginationFilter request.RequestPagination
@@ -225,11 +342,7 @@ func (r repoHandler) GetApprovalReason(req request.ReqApprovalReason, pagination
 
 		var row entity.TotalRow
 
-		if err = r.confins.Raw(fmt.Sprintf(`
-		SELECT
-		COUNT(tt.id) AS totalRow
-		FROM
-		(SELECT CONCAT(ReasonID, '|', Type, '|', Description) AS 'id', Description AS 'value', [Type] FROM tblApprovalReason WHERE IsActive = 'True' %s) AS tt`, filter)).Scan(&row).Error; err != nil {
+		if err = db.Raw(fmt.Sprintf(`%s SELECT COUNT(1) AS totalRow FROM reason %s`, reasonQuery, filter), args...).Scan(&row).Error; err != nil {
 			return
 		}
 
@@ -238,13 +351,8 @@ func (r repoHandler) GetApprovalReason(req request.ReqApprovalReason, pagination
 		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-	defer cancel()
-
-	db := r.confins.BeginTx(ctx, &x)
-	defer db.Commit()
-
-	if err = r.confins.Raw(fmt.Sprintf(`SELECT CONCAT(ReasonID, '|', Type, '|', Description) AS 'id', Description AS 'value', [Type] FROM tblApprovalReason WHERE IsActive = 'True' %s ORDER BY ReasonID ASC %s`, filter, filterPaginate)).Scan(&reason).Error; err != nil {
+	if err = db.Raw(fmt.Sprintf(`%s SELECT CONCAT(id, '|', [Type], '|', Description) AS 'id', Description AS 'value', [Type] FROM reason %s
+		ORDER BY sort_order ASC, position ASC, id ASC %s`, reasonQuery, filter, filterPaginate), args...).Scan(&reason).Error; err != nil {
 		return
 	}
 
@@ -474,7 +582,6 @@ func (r repoHandler) GetDatatablePrescreening(req request.ReqInquiryPrescreening
 		filter         string
 		filterBranch   string
 		filterPaginate string
-		encrypted      entity.EncryptString
 	)
 
 	rangeDays := os.Getenv("DEFAULT_RANGE_DAYS")
@@ -515,15 +622,11 @@ func (r repoHandler) GetDatatablePrescreening(req request.ReqInquiryPrescreening
 		switch req.SearchBy {
 		case "order_id":
 			whereConditions = append(whereConditions, fmt.Sprintf("tm.ProspectID = '%s'", req.SearchValue))
-		case "id_number":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.IDNumber = '%s'", encrypted.Encrypt))
-			}
-		case "legal_name":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.LegalName = '%s'", encrypted.Encrypt))
+		case "id_number", "legal_name", "mobile_phone":
+			var condition string
+			condition, err = r.customerSearchCondition(req.SearchBy, req.SearchValue)
+			if err == nil && condition != "" {
+				whereConditions = append(whereConditions, condition)
 			}
 		}
 	} else {
@@ -632,7 +735,6 @@ func (r repoHandler) GetInquiryPrescreening(req request.ReqInquiryPrescreening,
 		filter         string
 		filterBranch   string
 		filterPaginate string
-		encrypted      entity.EncryptString
 	)
 
 	rangeDays := os.Getenv("DEFAULT_RANGE_DAYS")
@@ -673,15 +775,11 @@ func (r repoHandler) GetInquiryPrescreening(req request.ReqInquiryPrescreening,
 		switch req.SearchBy {
 		case "order_id":
 			whereConditions = append(whereConditions, fmt.Sprintf("tm.ProspectID = '%s'", req.SearchValue))
-		case "id_number":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.IDNumber = '%s'", encrypted.Encrypt))
-			}
-		case "legal_name":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.LegalName = '%s'", encrypted.Encrypt))
+		case "id_number", "legal_name", "mobile_phone":
+			var condition string
+			condition, err = r.customerSearchCondition(req.SearchBy, req.SearchValue)
+			if err == nil && condition != "" {
+				whereConditions = append(whereConditions, condition)
 			}
 		}
 	} else {
@@ -922,6 +1020,8 @@ func (r repoHandler) GetInquiryPrescreening(req request.ReqInquiryPrescreening,
 	tps.created_by AS DecisionBy,
 	tps.decision_by AS DecisionName,
 	tps.created_at AS DecisionAt,
+	tpad.result AS auto_decision,
+	tpad.rule_code AS auto_rule_code,
 	CASE
 	  WHEN tm.incoming_source = 'SLY' THEN 'SALLY'
 	  ELSE 'NE'
@@ -1106,6 +1206,7 @@ func (r repoHandler) GetInquiryPrescreening(req request.ReqInquiryPrescreening,
 	INNER JOIN trx_customer_emcon em WITH (nolock) ON tm.ProspectID = em.ProspectID
 	LEFT JOIN trx_customer_spouse tcs WITH (nolock) ON tm.ProspectID = tcs.ProspectID
 	LEFT JOIN trx_prescreening tps WITH (nolock) ON tm.ProspectID = tps.ProspectID
+	LEFT JOIN trx_prescreening_auto_decision tpad WITH (nolock) ON tm.ProspectID = tpad.ProspectID
 	LEFT JOIN (
 	  SELECT
 		[key],
@@ -1772,7 +1873,6 @@ func (r repoHandler) GetDatatableCa(req request.ReqInquiryCa, pagination interfa
 		filterBranch   string
 		filterPaginate string
 		query          string
-		encrypted      entity.EncryptString
 	)
 
 	rangeDays := os.Getenv("DEFAULT_RANGE_DAYS")
@@ -1813,15 +1913,11 @@ func (r repoHandler) GetDatatableCa(req request.ReqInquiryCa, pagination interfa
 		switch req.SearchBy {
 		case "order_id":
 			whereConditions = append(whereConditions, fmt.Sprintf("tm.ProspectID = '%s'", req.SearchValue))
-		case "id_number":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.IDNumber = '%s'", encrypted.Encrypt))
-			}
-		case "legal_name":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.LegalName = '%s'", encrypted.Encrypt))
+		case "id_number", "legal_name", "mobile_phone":
+			var condition string
+			condition, err = r.customerSearchCondition(req.SearchBy, req.SearchValue)
+			if err == nil && condition != "" {
+				whereConditions = append(whereConditions, condition)
 			}
 		}
 	} else {
@@ -1875,7 +1971,7 @@ func (r repoHandler) GetDatatableCa(req request.ReqInquiryCa, pagination interfa
 		filter = filterBranch
 	}
 
-	filter = filter + query
+	filter = filter + query + slaFilter(req.AgingFilter)
 
 	if pagination != nil {
 		page, _ := json.Marshal(pagination)
@@ -2053,6 +2149,12 @@ func (r repoHandler) GetDatatableCa(req request.ReqInquiryCa, pagination interfa
 				END AS ca_decision,
 				tst.decision,
 				tst.reason,
+				tst.source_decision AS stage,
+				tst.status_process AS stage_status,
+				tst.created_at AS stage_entered_at,
+				toc.claimed_by,
+				toc.claimed_by_name,
+				toc.last_activity_at AS claim_activity_at,
 				tm.created_at,
 				tm.order_at,
 				tm.ProspectID,
@@ -2079,9 +2181,10 @@ func (r repoHandler) GetDatatableCa(req request.ReqInquiryCa, pagination interfa
 				LEFT JOIN cte_trx_history_approval_scheme_sdp sdp ON sdp.ProspectID = tm.ProspectID
 				LEFT JOIN cte_trx_ca_decision tcd ON tm.ProspectID = tcd.ProspectID
 				LEFT JOIN cte_trx_draft_ca_decision tdd ON tm.ProspectID = tdd.ProspectID 
+				LEFT JOIN trx_order_claim toc WITH (nolock) ON tm.ProspectID = toc.ProspectID AND toc.last_activity_at > DATEADD(MINUTE, -%d, GETDATE())
 			%s AND tst.source_decision <> '%s'
 			ORDER BY
-				tm.created_at DESC %s`, filter, constant.PRESCREENING, filterPaginate)).Scan(&data).Error; err != nil {
+				tm.created_at DESC %s`, constant.ORDER_CLAIM_TIMEOUT, filter, constant.PRESCREENING, filterPaginate)).Scan(&data).Error; err != nil {
 		return
 	}
 
@@ -2098,7 +2201,6 @@ func (r repoHandler) GetInquiryCa(req request.ReqInquiryCa, pagination interface
 		filterBranch   string
 		filterPaginate string
 		query          string
-		encrypted      entity.EncryptString
 	)
 
 	rangeDays := os.Getenv("DEFAULT_RANGE_DAYS")
@@ -2139,15 +2241,11 @@ func (r repoHandler) GetInquiryCa(req request.ReqInquiryCa, pagination interface
 		switch req.SearchBy {
 		case "order_id":
 			whereConditions = append(whereConditions, fmt.Sprintf("tm.ProspectID = '%s'", req.SearchValue))
-		case "id_number":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.IDNumber = '%s'", encrypted.Encrypt))
-			}
-		case "legal_name":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.LegalName = '%s'", encrypted.Encrypt))
+		case "id_number", "legal_name", "mobile_phone":
+			var condition string
+			condition, err = r.customerSearchCondition(req.SearchBy, req.SearchValue)
+			if err == nil && condition != "" {
+				whereConditions = append(whereConditions, condition)
 			}
 		}
 	} else {
@@ -2915,10 +3013,21 @@ func (r repoHandler) GetInquirySearch(req request.ReqSearchInquiry, pagination i
 	var regexpPpid = regexp.MustCompile(`SAL-|NE-`)
 	var regexpIDNumber = regexp.MustCompile(`^[0-9]*$`)
 	var regexpLegalName = regexp.MustCompile("^[a-zA-Z.,'` ]*$")
+	var regexpPhone = regexp.MustCompile(`^\+?[0-9 -]*$`)
 
 	if search != "" && regexpPpid.MatchString(search) {
 		//query prospect id only
 		qSearch = fmt.Sprintf("(tm.ProspectID = '%s')", search)
+	} else if search != "" && utils.BlindIndexEnabled() {
+		//query through the blind indexes, digits match the start or end of the id number or the mobile phone and words match part of the name
+		if regexpPhone.MatchString(search) {
+			qSearch = fmt.Sprintf("(tcp.id_number_bidx = '%s' OR tcp.mobile_phone_bidx = '%s' OR %s)", utils.BlindIndexIDNumber(search), utils.BlindIndexMobilePhone(search),
+				numberTokenCondition(append(utils.BlindIndexIDNumberSearchTokens(search), utils.BlindIndexPhoneSearchTokens(search)...)))
+		} else if regexpLegalName.MatchString(search) {
+			qSearch = fmt.Sprintf("(%s)", nameTokenCondition(utils.BlindIndexSearchTokens(search)))
+		} else {
+			qSearch = fmt.Sprintf("(tm.ProspectID = '%s' OR tcp.id_number_bidx = '%s' OR %s)", search, utils.BlindIndexIDNumber(search), nameTokenCondition(utils.BlindIndexSearchTokens(search)))
+		}
 	} else if search != "" && regexpIDNumber.MatchString(search) {
 		//query id number only
 		encrypted, _ := r.EncryptString(search)
@@ -3406,39 +3515,15 @@ func (r repoHandler) ProcessTransaction(trxCaDecision entity.TrxCaDecision, trxH
 			}
 		}
 
-		if isCancel {
-			var resultCheckDeviation entity.ResultCheckDeviation
-
-			selectQuery := `
-                SELECT mbd.BranchID, ta.NTF, tf.customer_status, tfa.decision
-				FROM trx_deviasi AS td WITH (nolock)
-				LEFT JOIN trx_apk AS ta ON (td.ProspectID = ta.ProspectID)
-				LEFT JOIN trx_master AS tm ON (td.ProspectID = tm.ProspectID)
-				LEFT JOIN m_branch_deviasi AS mbd ON (tm.BranchID = mbd.BranchID)
-				LEFT JOIN trx_filtering AS tf ON (td.ProspectID = tf.prospect_id)
-				LEFT JOIN trx_final_approval AS tfa ON (td.ProspectID = tfa.ProspectID)
-                WHERE ta.ProspectID = ? AND mbd.is_active = 1
-            `
-			if err = tx.Raw(selectQuery, trxCaDecision.ProspectID).Scan(&resultCheckDeviation).Error; err != nil {
-				if err != gorm.ErrRecordNotFound {
-					return err
-				}
+		// a cancelled or rejected order gives back the quota deviasi it booked
+		if trxStatus.StatusProcess == constant.STATUS_FINAL && (trxStatus.Decision == constant.DB_DECISION_CANCEL || trxStatus.Decision == constant.DB_DECISION_REJECT) {
+			reason := constant.QUOTA_REASON_REJECT
+			if isCancel {
+				reason = constant.QUOTA_REASON_CANCEL
 			}
 
-			if resultCheckDeviation.BranchID != "" && resultCheckDeviation.CustomerStatus != "" && resultCheckDeviation.CustomerStatus == constant.STATUS_KONSUMEN_NEW && resultCheckDeviation.Decision != nil {
-				if decisionStr, ok := resultCheckDeviation.Decision.(string); ok && decisionStr == constant.DB_DECISION_APR {
-					updateQuery := `
-						UPDATE m_branch_deviasi
-						SET booking_amount = booking_amount - ?,
-							booking_account = booking_account - 1,
-							balance_amount = (quota_amount - booking_amount) + ?,
-							balance_account = (quota_account - booking_account) + 1
-						WHERE BranchID = ? AND is_active = 1
-					`
-					if err = tx.Exec(updateQuery, resultCheckDeviation.NTF, resultCheckDeviation.NTF, resultCheckDeviation.BranchID).Error; err != nil {
-						return err
-					}
-				}
+			if _, err = releaseQuotaDeviasi(tx, trxStatus.ProspectID, reason, trxCaDecision.CreatedBy); err != nil {
+				return err
 			}
 		}
 
@@ -3458,6 +3543,11 @@ func (r repoHandler) ProcessReturnOrder(prospectID string, trxStatus entity.TrxS
 			return err
 		}
 
+		// the returned order goes through the deviasi approval again, its booking is given back first
+		if _, err := releaseQuotaDeviasi(tx, prospectID, constant.QUOTA_REASON_RETURN, trxDetail.CreatedBy); err != nil {
+			return err
+		}
+
 		// truncate the order from trx_details
 		if err := tx.Where("ProspectID = ?", prospectID).Delete(&trxDetail).Error; err != nil {
 			return err
@@ -3534,6 +3624,14 @@ func (r repoHandler) ProcessRecalculateOrder(prospectID string, trxStatus entity
 	trxHistoryApproval.ID = uuid.New().String()
 	trxHistoryApproval.CreatedAt = time.Now()
 
+	// opened without figures, the recalculation from sally fills them in
+	trxRecalculateHistory := entity.TrxRecalculateHistory{
+		ID:         uuid.New().String(),
+		ProspectID: prospectID,
+		CreatedBy:  trxHistoryApproval.CreatedBy,
+		CreatedAt:  time.Now(),
+	}
+
 	return r.NewKmb.Transaction(func(tx *gorm.DB) error {
 
 		// update trx_status
@@ -3558,6 +3656,11 @@ func (r repoHandler) ProcessRecalculateOrder(prospectID string, trxStatus entity
 			return err
 		}
 
+		// insert trx_recalculate_history
+		if err := tx.Create(&trxRecalculateHistory).Error; err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -3570,7 +3673,6 @@ func (r repoHandler) GetDatatableApproval(req request.ReqInquiryApproval, pagina
 		filterPaginate string
 		query          string
 		alias          string
-		encrypted      entity.EncryptString
 	)
 
 	alias = req.Alias
@@ -3613,15 +3715,11 @@ func (r repoHandler) GetDatatableApproval(req request.ReqInquiryApproval, pagina
 		switch req.SearchBy {
 		case "order_id":
 			whereConditions = append(whereConditions, fmt.Sprintf("tm.ProspectID = '%s'", req.SearchValue))
-		case "id_number":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.IDNumber = '%s'", encrypted.Encrypt))
-			}
-		case "legal_name":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.LegalName = '%s'", encrypted.Encrypt))
+		case "id_number", "legal_name", "mobile_phone":
+			var condition string
+			condition, err = r.customerSearchCondition(req.SearchBy, req.SearchValue)
+			if err == nil && condition != "" {
+				whereConditions = append(whereConditions, condition)
 			}
 		}
 	} else {
@@ -3672,7 +3770,7 @@ func (r repoHandler) GetDatatableApproval(req request.ReqInquiryApproval, pagina
 		filter = filterBranch
 	}
 
-	filter = filter + query
+	filter = filter + query + slaFilter(req.AgingFilter)
 
 	if pagination != nil {
 		page, _ := json.Marshal(pagination)
@@ -3749,6 +3847,9 @@ func (r repoHandler) GetDatatableApproval(req request.ReqInquiryApproval, pagina
 				tcp.BirthDate,
 				tst.decision,
 				tst.reason,
+				tst.source_decision AS stage,
+				tst.status_process AS stage_status,
+				tst.created_at AS stage_entered_at,
 				CASE
 					WHEN (tfa.decision IS NULL)
 					AND (tcd.decision <> 'CAN') 
@@ -3808,7 +3909,6 @@ func (r repoHandler) GetInquiryApproval(req request.ReqInquiryApproval, paginati
 		filterPaginate string
 		query          string
 		alias          string
-		encrypted      entity.EncryptString
 	)
 
 	alias = req.Alias
@@ -3851,15 +3951,11 @@ func (r repoHandler) GetInquiryApproval(req request.ReqInquiryApproval, paginati
 		switch req.SearchBy {
 		case "order_id":
 			whereConditions = append(whereConditions, fmt.Sprintf("tm.ProspectID = '%s'", req.SearchValue))
-		case "id_number":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.IDNumber = '%s'", encrypted.Encrypt))
-			}
-		case "legal_name":
-			encrypted, err = r.EncryptString(req.SearchValue)
-			if err == nil {
-				whereConditions = append(whereConditions, fmt.Sprintf("tcp.LegalName = '%s'", encrypted.Encrypt))
+		case "id_number", "legal_name", "mobile_phone":
+			var condition string
+			condition, err = r.customerSearchCondition(req.SearchBy, req.SearchValue)
+			if err == nil && condition != "" {
+				whereConditions = append(whereConditions, condition)
 			}
 		}
 	} else {
@@ -4419,6 +4515,10 @@ func (r repoHandler) SubmitApproval(req request.ReqSubmitApproval, trxStatus ent
 				info, _ := json.Marshal(confirmDeviasi)
 				trxDetail.Info = string(info)
 
+				if err = insertQuotaDeviasiLedger(tx, trxStatus.ProspectID, confirmDeviasi.BranchID, constant.QUOTA_LEDGER_BOOKING, constant.QUOTA_REASON_APPROVE, confirmDeviasi.NTF, req.CreatedBy); err != nil {
+					return err
+				}
+
 			} else {
 
 				// kuota tidak tersedia, reject deviasi
@@ -4881,6 +4981,15 @@ func (r repoHandler) ProcessResetQuotaDeviasiBranch(branchID string, updatedBy s
 			return err
 		}
 
+		// keep the totals of the closed period before they are cleared
+		if err := archiveQuotaDeviasiPeriod(tx, branchID, constant.QUOTA_RESET_MANUAL, updatedBy); err != nil {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE m_branch_deviasi SET period_start = GETDATE() WHERE BranchID = ?", branchID).Error; err != nil {
+			return err
+		}
+
 		if err := tx.Model(&entity.MappingBranchDeviasi{}).
 			Where("BranchID = ?", branchID).
 			Updates(map[string]interface{}{
@@ -4912,6 +5021,15 @@ func (r repoHandler) ProcessResetQuotaDeviasiBranch(branchID string, updatedBy s
 func (r repoHandler) ProcessResetAllQuotaDeviasi(updatedBy string) (err error) {
 	err = r.NewKmb.Transaction(func(tx *gorm.DB) error {
 
+		// keep the totals of the closed period before they are cleared
+		if err := archiveQuotaDeviasiPeriod(tx, "", constant.QUOTA_RESET_MANUAL, updatedBy); err != nil {
+			return err
+		}
+
+		if err := tx.Exec("UPDATE m_branch_deviasi SET period_start = GETDATE()").Error; err != nil {
+			return err
+		}
+
 		if err := tx.Model(&entity.MappingBranchDeviasi{}).
 			Updates(map[string]interface{}{
 				"quota_amount":    0,
@@ -5282,20 +5400,7 @@ func (r repoHandler) BatchUpdateMappingCluster(data []entity.MasterMappingCluste
 		}
 	}()
 
-	var cluster entity.MasterMappingCluster
-	if err = db.Delete(&cluster).Error; err != nil {
-		return err
-	}
-
-	for _, val := range data {
-		if err = db.Create(&val).Error; err != nil {
-			return err
-		}
-	}
-
-	if err = db.Create(&history).Error; err != nil {
-		return err
-	}
+	err = replaceMappingCluster(db, data, history, constant.MAPPING_CLUSTER_UPLOAD, nil)
 
 	return err
 }
@@ -5427,7 +5532,7 @@ func (r repoHandler) GetMappingClusterChangeLog(pagination interface{}) (data []
 	}
 
 	if err = r.losDB.Raw(fmt.Sprintf(`SELECT
-			hcc.id, hcc.data_before, hcc.data_after, hcc.created_at, ud.name AS user_name
+			hcc.id, hcc.action, hcc.data_before, hcc.data_after, hcc.created_at, ud.name AS user_name
 		FROM history_config_changes hcc 
 		LEFT JOIN user_details ud ON ud.user_id = hcc.created_by 
 		WHERE hcc.config_id = 'kmb_mapping_cluster_branch'
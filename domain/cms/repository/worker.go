@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func (r repoHandler) GetWorkers(req request.ReqListWorker, pagination interface{}) (data []entity.InquiryWorker, rowTotal int, err error) {
+
+	var (
+		conditions     []string
+		args           []interface{}
+		filter         string
+		filterPaginate string
+		x              sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.losDB.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if req.ProspectID != "" {
+		conditions = append(conditions, "ProspectID = ?")
+		args = append(args, req.ProspectID)
+	}
+
+	if req.Category != "" {
+		conditions = append(conditions, "category = ?")
+		args = append(args, req.Category)
+	}
+
+	if req.Activity != "" {
+		conditions = append(conditions, "activity = ?")
+		args = append(args, req.Activity)
+	}
+
+	if len(conditions) > 0 {
+		filter = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw(fmt.Sprintf(`SELECT COUNT(*) AS totalRow FROM trx_worker WITH (nolock) %s`, filter), args...).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT ProspectID, category, action, activity, endpoint_target, endpoint_method, max_retry, count_retry,
+			ISNULL(status_code, '') AS status_code, ISNULL(last_error, '') AS last_error, ISNULL(locked_by, '') AS locked_by,
+			ISNULL(FORMAT(next_retry_at, 'yyyy-MM-dd HH:mm:ss'), '') AS next_retry_at,
+			FORMAT(created_at, 'yyyy-MM-dd HH:mm:ss') AS created_at
+		FROM trx_worker WITH (nolock) %s
+		ORDER BY created_at DESC %s`, filter, filterPaginate), args...).Scan(&data).Error; err != nil {
+		return
+	}
+
+	if len(data) == 0 {
+		return data, 0, fmt.Errorf(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
+
+// RequeueWorker makes a dead or waiting row due now with a fresh retry count, a running row is left alone
+func (r repoHandler) RequeueWorker(req request.ReqRequeueWorker) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.losDB.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	result := db.Exec(`UPDATE trx_worker SET activity = ?, count_retry = 0, next_retry_at = NULL, last_error = NULL, locked_by = NULL, locked_at = NULL
+		WHERE ProspectID = ? AND category = ? AND action = ? AND activity IN (?, ?)`,
+		constant.ACTIVITY_UNPROCESS, req.ProspectID, req.Category, req.Action, constant.WORKER_ACTIVITY_DEAD, constant.ACTIVITY_UNPROCESS)
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = errors.New(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
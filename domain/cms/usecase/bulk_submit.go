@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+)
+
+// BulkSubmitDecision submits one ca decision to every order, each order is processed in its own transaction
+func (u usecase) BulkSubmitDecision(ctx context.Context, req request.ReqBulkSubmitDecision) (data response.BulkSubmitResponse, err error) {
+
+	data.Items = []response.BulkSubmitItem{}
+
+	for _, prospectID := range req.ProspectIDs {
+
+		result, errItem := u.bulkSubmitDecisionItem(ctx, prospectID, req)
+
+		data.Items = append(data.Items, bulkSubmitItem(prospectID, result, errItem))
+	}
+
+	data.Total, data.Success, data.Failed = bulkSubmitSummary(data.Items)
+
+	return
+}
+
+func (u usecase) bulkSubmitDecisionItem(ctx context.Context, prospectID string, req request.ReqBulkSubmitDecision) (data response.CAResponse, err error) {
+
+	// a claim the submitter held before the bulk is kept, a claim taken here is given back when the order fails
+	holder, errHolder := u.repository.GetOrderClaim(prospectID)
+	heldBefore := errHolder == nil && holder.ClaimedBy == req.CreatedBy
+
+	// every order is claimed for the submitter, an order held by another ca is skipped
+	if _, err = u.ClaimOrder(ctx, request.ReqClaimOrder{
+		ProspectID:    prospectID,
+		ClaimedBy:     req.CreatedBy,
+		ClaimedByName: req.DecisionBy,
+	}); err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil && !heldBefore {
+			_ = u.ReleaseOrder(ctx, request.ReqReleaseOrder{
+				ProspectID:     prospectID,
+				ReleasedBy:     req.CreatedBy,
+				ReleasedByName: req.DecisionBy,
+			})
+		}
+	}()
+
+	trxEDD, err := u.repository.GetTrxEDD(prospectID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get trx_edd error")
+		return
+	}
+
+	// the edd form is filled per order, a highrisk order is submitted one by one
+	if trxEDD.IsHighrisk {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Form EDD is required, order highrisk harus disubmit satu per satu")
+		return
+	}
+
+	ntfAkumulasi, err := u.repository.GetNTFAkumulasi(prospectID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get NTF akumulasi error")
+		return
+	}
+
+	return u.SubmitDecision(ctx, request.ReqSubmitDecision{
+		ProspectID:   prospectID,
+		NTFAkumulasi: ntfAkumulasi,
+		Decision:     req.Decision,
+		SlikResult:   req.SlikResult,
+		Note:         req.Note,
+		CreatedBy:    req.CreatedBy,
+		DecisionBy:   req.DecisionBy,
+	})
+}
+
+// BulkSubmitApproval submits one approval to every order, the approval scheme and the kuota deviasi are checked per order
+func (u usecase) BulkSubmitApproval(ctx context.Context, req request.ReqBulkSubmitApproval) (data response.BulkSubmitResponse, err error) {
+
+	data.Items = []response.BulkSubmitItem{}
+
+	for _, prospectID := range req.ProspectIDs {
+
+		result, errItem := u.bulkSubmitApprovalItem(ctx, prospectID, req)
+
+		data.Items = append(data.Items, bulkSubmitItem(prospectID, result, errItem))
+	}
+
+	data.Total, data.Success, data.Failed = bulkSubmitSummary(data.Items)
+
+	return
+}
+
+// bulkSubmitApprovalItem takes the final approval level from the order itself, the orders of one bulk can be
+// decided up to different levels
+func (u usecase) bulkSubmitApprovalItem(ctx context.Context, prospectID string, req request.ReqBulkSubmitApproval) (data response.ApprovalResponse, err error) {
+
+	state, err := u.repository.GetApprovalState(prospectID)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Order tidak ditemukan")
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get approval state error")
+		return
+	}
+
+	if state.StatusProcess != constant.STATUS_ONPROCESS || state.Decision != constant.DB_DECISION_CREDIT_PROCESS || state.SourceDecision != req.Alias {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Order tidak berada di tahap approval " + req.Alias)
+		return
+	}
+
+	if state.FinalApproval == "" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Final approval order belum ditentukan")
+		return
+	}
+
+	return u.SubmitApproval(ctx, request.ReqSubmitApproval{
+		ProspectID:     prospectID,
+		FinalApproval:  state.FinalApproval,
+		Decision:       req.Decision,
+		RuleCode:       req.RuleCode,
+		Alias:          req.Alias,
+		Reason:         req.Reason,
+		NeedEscalation: req.NeedEscalation,
+		Note:           req.Note,
+		CreatedBy:      req.CreatedBy,
+		DecisionBy:     req.DecisionBy,
+	})
+}
+
+func bulkSubmitItem(prospectID string, result interface{}, err error) response.BulkSubmitItem {
+
+	if err != nil {
+		return response.BulkSubmitItem{
+			ProspectID: prospectID,
+			Status:     constant.BULK_ITEM_FAILED,
+			Message:    err.Error(),
+		}
+	}
+
+	return response.BulkSubmitItem{
+		ProspectID: prospectID,
+		Status:     constant.BULK_ITEM_SUCCESS,
+		Message:    constant.MESSAGE_SUCCESS,
+		Data:       result,
+	}
+}
+
+func bulkSubmitSummary(items []response.BulkSubmitItem) (total, success, failed int) {
+
+	total = len(items)
+
+	for _, item := range items {
+		if item.Status == constant.BULK_ITEM_SUCCESS {
+			success++
+		}
+	}
+
+	failed = total - success
+
+	return
+}
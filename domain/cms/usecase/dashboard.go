@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GetDashboardStats returns the aggregates of the branches the user can access, the result is cached for a few minutes
+func (u usecase) GetDashboardStats(ctx context.Context, req request.ReqDashboardStats) (data response.DashboardStats, err error) {
+
+	startDate, _ := time.Parse(constant.FORMAT_DATE, req.StartDate)
+	endDate, _ := time.Parse(constant.FORMAT_DATE, req.EndDate)
+
+	if endDate.Before(startDate) {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - end_date tidak boleh lebih kecil dari start_date")
+		return
+	}
+
+	if endDate.Sub(startDate) > time.Duration(constant.DASHBOARD_MAX_RANGE_DAYS)*24*time.Hour {
+		err = fmt.Errorf("%s - Rentang tanggal maksimal %d hari", constant.ERROR_BAD_REQUEST, constant.DASHBOARD_MAX_RANGE_DAYS)
+		return
+	}
+
+	branchIDs, err := u.dashboardBranches(req)
+	if err != nil {
+		return
+	}
+
+	sort.Strings(branchIDs)
+
+	hash := md5.Sum([]byte(strings.Join(branchIDs, ",")))
+	cacheKey := fmt.Sprintf("%s_%s_%s_%s", constant.DASHBOARD_CACHE_KEY_STATS, req.StartDate, req.EndDate, hex.EncodeToString(hash[:]))
+
+	if cached, errCache := u.cache.GetWithExpiration(cacheKey); errCache == nil {
+		if json.Unmarshal(cached, &data) == nil {
+			return
+		}
+	}
+
+	stats, err := u.repository.GetDashboardStats(branchIDs, startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Dashboard Stats error")
+		return
+	}
+
+	data = response.DashboardStats{
+		BranchIDs:     branchIDs,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		Orders:        stats.Orders,
+		TopRejections: stats.Rejections,
+		QuotaDeviasi: response.DashboardQuotaDeviasi{
+			QuotaAmount:    stats.QuotaDeviasi.QuotaAmount,
+			QuotaAccount:   stats.QuotaDeviasi.QuotaAccount,
+			BookingAmount:  stats.QuotaDeviasi.BookingAmount,
+			BookingAccount: stats.QuotaDeviasi.BookingAccount,
+		},
+		TimeToDecision: response.DashboardTimeToDecision{
+			TotalDecided:   stats.TimeToDecision.TotalDecided,
+			AverageMinutes: math.Round(stats.TimeToDecision.AverageMinutes*100) / 100,
+		},
+		GeneratedAt: time.Now().Format(constant.FORMAT_DATE_TIME),
+	}
+
+	if stats.QuotaDeviasi.QuotaAmount > 0 {
+		data.QuotaDeviasi.AmountUtilisation = dashboardPercentage(stats.QuotaDeviasi.BookingAmount, stats.QuotaDeviasi.QuotaAmount)
+	}
+
+	if stats.QuotaDeviasi.QuotaAccount > 0 {
+		data.QuotaDeviasi.AccountUtilisation = dashboardPercentage(float64(stats.QuotaDeviasi.BookingAccount), float64(stats.QuotaDeviasi.QuotaAccount))
+	}
+
+	rates := map[string]*response.DashboardApprovalRate{}
+	var aliases []string
+
+	for _, row := range stats.AliasDecisions {
+		rate, ok := rates[row.Alias]
+		if !ok {
+			rate = &response.DashboardApprovalRate{Alias: row.Alias}
+			rates[row.Alias] = rate
+			aliases = append(aliases, row.Alias)
+		}
+
+		rate.Total += row.Total
+
+		switch row.Decision {
+		case constant.DB_DECISION_APR:
+			rate.Approved += row.Total
+		case constant.DB_DECISION_REJECT:
+			rate.Rejected += row.Total
+		case constant.DB_DECISION_RTN:
+			rate.Returned += row.Total
+		}
+	}
+
+	for _, alias := range aliases {
+		rate := rates[alias]
+		rate.ApprovalRate = dashboardPercentage(float64(rate.Approved), float64(rate.Total))
+		rate.RejectionRate = dashboardPercentage(float64(rate.Rejected), float64(rate.Total))
+		data.ApprovalRates = append(data.ApprovalRates, *rate)
+	}
+
+	if cached, errMarshal := json.Marshal(data); errMarshal == nil {
+		u.cache.SetWithExpiration(cacheKey, cached, time.Duration(constant.DASHBOARD_CACHE_TTL)*time.Minute)
+	}
+
+	return
+}
+
+// dashboardBranches scopes the request the same way the inquiries do, a multi branch user gets every branch from GetListBranch
+// and region or branch_filter may only narrow that scope
+func (u usecase) dashboardBranches(req request.ReqDashboardStats) (branchIDs []string, err error) {
+
+	branchIDs = []string{req.BranchID}
+
+	if req.MultiBranch == "1" {
+		_, listBranches, errBranch := u.repository.GetListBranch(request.ReqListBranch{
+			UserID:         req.UserID,
+			IsMultiBranch:  1,
+			SingleBranchID: req.BranchID,
+		})
+		if errBranch != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get List Branch error")
+			return
+		}
+
+		if len(listBranches) > 0 {
+			branchIDs = nil
+			for _, branch := range listBranches {
+				branchIDs = append(branchIDs, branch.BranchID)
+			}
+		}
+	}
+
+	if req.Region != "" {
+		members, errRegion := u.repository.GetRegionBranches(req.Region)
+		if errRegion != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Region Branches error")
+			return
+		}
+
+		allowed := map[string]bool{}
+		for _, branchID := range members {
+			allowed[branchID] = true
+		}
+
+		var scoped []string
+		for _, branchID := range branchIDs {
+			if allowed[branchID] {
+				scoped = append(scoped, branchID)
+			}
+		}
+
+		if len(scoped) == 0 {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Region tidak termasuk dalam akses user")
+			return
+		}
+
+		branchIDs = scoped
+	}
+
+	if req.BranchFilter != "" {
+		for _, branchID := range branchIDs {
+			if branchID == req.BranchFilter {
+				branchIDs = []string{req.BranchFilter}
+				return
+			}
+		}
+
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Branch tidak termasuk dalam akses user")
+		return
+	}
+
+	return
+}
+
+func dashboardPercentage(value, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return math.Round(value/total*10000) / 100
+}
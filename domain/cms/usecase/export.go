@@ -0,0 +1,452 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	// exportWriter receives the rows of an export one by one, so a page can be released once written
+	exportWriter interface {
+		WriteRow(row []interface{}) error
+		Close() error
+	}
+
+	xlsxExportWriter struct {
+		file   *excelize.File
+		stream *excelize.StreamWriter
+		path   string
+		row    int
+	}
+
+	csvExportWriter struct {
+		file   *os.File
+		writer *csv.Writer
+	}
+)
+
+var exportHeaders = map[string][]string{
+	constant.EXPORT_TYPE_PRESCREENING: {"Prospect ID", "Cabang", "Incoming Source", "Tanggal Order", "Status Konsumen", "No KTP", "Nama Konsumen", "No HP", "Dealer", "Asset", "Tahun", "No Polisi", "OTR", "DP", "NTF", "Tenor", "Angsuran", "Activity", "Source Decision", "Decision", "Reason", "Decision By", "Decision At"},
+	constant.EXPORT_TYPE_CA:           {"Prospect ID", "Cabang", "Incoming Source", "Tanggal Order", "Status Konsumen", "No KTP", "Nama Konsumen", "No HP", "Dealer", "Asset", "Tahun", "No Polisi", "OTR", "DP", "NTF", "Tenor", "Angsuran", "Activity", "Source Decision", "Decision", "Reason", "CA Decision", "Final Approval"},
+	constant.EXPORT_TYPE_APPROVAL:     {"Prospect ID", "Cabang", "Incoming Source", "Tanggal Order", "Status Konsumen", "No KTP", "Nama Konsumen", "No HP", "Dealer", "Asset", "Tahun", "No Polisi", "OTR", "DP", "NTF", "Tenor", "Angsuran", "Activity", "Source Decision", "Decision", "Reason", "CA Decision", "Final Approval"},
+	constant.EXPORT_TYPE_SEARCH:       {"Prospect ID", "Cabang", "Incoming Source", "Tanggal Order", "Status Konsumen", "No KTP", "Nama Konsumen", "No HP", "Dealer", "Asset", "Tahun", "No Polisi", "OTR", "DP", "NTF", "Tenor", "Angsuran", "Final Status"},
+	constant.EXPORT_TYPE_LIST_ORDER:   {"Tanggal Order", "Cabang", "Prospect ID", "Nama Konsumen", "No KTP", "Tanggal Lahir", "Profesi", "Jenis Pekerjaan", "Jabatan", "High Risk", "Decision", "Source Decision", "Rule Code", "Reason", "Decision By", "Decision At"},
+}
+
+// CreateExportJob records a pending export and runs it in background, the caller polls the job status
+func (u usecase) CreateExportJob(ctx context.Context, req request.ReqCreateExportJob) (data response.ExportJob, err error) {
+
+	filter, err := json.Marshal(req.Filter)
+	if err != nil {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - filter tidak valid")
+		return
+	}
+
+	now := time.Now()
+
+	job := entity.TrxExportJob{
+		ID:          utils.GenerateUUID(),
+		InquiryType: req.InquiryType,
+		FileFormat:  req.FileFormat,
+		Filter:      string(filter),
+		Status:      constant.EXPORT_STATUS_PENDING,
+		FileName:    fmt.Sprintf("Export_%s_%s.%s", req.InquiryType, now.Format("20060102150405"), req.FileFormat),
+		CreatedBy:   req.CreatedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = u.repository.SaveExportJob(job); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Save Export Job error")
+		return
+	}
+
+	go u.runExportJob(job)
+
+	data = exportJobResponse(job)
+
+	return
+}
+
+func (u usecase) GetExportJob(id string) (data response.ExportJob, err error) {
+
+	job, err := u.repository.GetExportJob(id)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Export job tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Export Job error")
+		}
+		return
+	}
+
+	data = exportJobResponse(job)
+
+	return
+}
+
+// GetExportFile returns the media url of the file of a finished job
+func (u usecase) GetExportFile(id string) (fileURL string, err error) {
+
+	job, err := u.repository.GetExportJob(id)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Export job tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Export Job error")
+		}
+		return
+	}
+
+	if job.Status != constant.EXPORT_STATUS_DONE {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Export job belum selesai, status " + job.Status)
+		return
+	}
+
+	if job.FileURL == nil || *job.FileURL == "" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - File export tidak tersedia")
+		return
+	}
+
+	fileURL = *job.FileURL
+
+	return
+}
+
+// runExportJob writes the export to a temporary file and stores it in media, so the file can be downloaded from any
+// instance and nothing is left on the local disk
+func (u usecase) runExportJob(job entity.TrxExportJob) {
+
+	var (
+		totalRows int
+		fileURL   string
+		err       error
+		path      = exportFilePath(job)
+	)
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+
+		os.Remove(path)
+
+		finishedAt := time.Now()
+		fields := map[string]interface{}{
+			"status":      constant.EXPORT_STATUS_DONE,
+			"total_rows":  totalRows,
+			"file_url":    fileURL,
+			"finished_at": finishedAt,
+		}
+
+		if err != nil {
+			fields["status"] = constant.EXPORT_STATUS_FAILED
+			fields["error_message"] = err.Error()
+		}
+
+		u.repository.UpdateExportJob(job.ID, fields)
+	}()
+
+	if err = u.repository.UpdateExportJob(job.ID, map[string]interface{}{"status": constant.EXPORT_STATUS_PROCESSING}); err != nil {
+		return
+	}
+
+	writer, err := newExportWriter(job.FileFormat, path, job.InquiryType)
+	if err != nil {
+		return
+	}
+
+	headers := exportHeaders[job.InquiryType]
+	header := make([]interface{}, len(headers))
+	for i, val := range headers {
+		header[i] = val
+	}
+
+	if err = writer.WriteRow(header); err != nil {
+		writer.Close()
+		return
+	}
+
+	for page := 1; ; page++ {
+		var rows [][]interface{}
+
+		rows, err = u.exportPage(job.InquiryType, job.Filter, request.RequestPagination{Page: page, Limit: constant.EXPORT_PAGE_LIMIT})
+		if err != nil {
+			writer.Close()
+			return
+		}
+
+		for _, row := range rows {
+			if err = writer.WriteRow(row); err != nil {
+				writer.Close()
+				return
+			}
+		}
+
+		totalRows += len(rows)
+
+		if len(rows) < constant.EXPORT_PAGE_LIMIT {
+			break
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return
+	}
+
+	fileURL, err = u.uploadExportFile(job, path)
+}
+
+func (u usecase) uploadExportFile(job entity.TrxExportJob, path string) (fileURL string, err error) {
+
+	// the file goes to media as a stream, an export can be too large to hold in memory
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	contentType := "text/csv"
+	if job.FileFormat != constant.EXPORT_FORMAT_CSV {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	// the job runs after the request is finished, it needs its own platform token
+	if _, err = middlewares.GetPlatformAuth(); err != nil {
+		return
+	}
+
+	media, err := u.uploadMedia(context.Background(), request.ReqMediaUpload{
+		FileName:    job.FileName,
+		ContentType: contentType,
+		Content:     file,
+		Type:        "EXPORT_INQUIRY",
+	}, middlewares.UserInfoData.AccessToken)
+	if err != nil {
+		return
+	}
+
+	return media.MediaUrl, nil
+}
+
+// exportPage runs one page of the inquiry query of the job and flattens it into rows, a file leaves the cms so the
+// NIK and phone numbers are always masked whatever the role of the requester
+func (u usecase) exportPage(inquiryType, filter string, pagination request.RequestPagination) (rows [][]interface{}, err error) {
+
+	switch inquiryType {
+	case constant.EXPORT_TYPE_PRESCREENING:
+		var (
+			req  request.ReqInquiryPrescreening
+			data []entity.InquiryPrescreening
+		)
+		if err = json.Unmarshal([]byte(filter), &req); err != nil {
+			return
+		}
+		if data, _, err = u.repository.GetInquiryPrescreening(req, pagination); err != nil {
+			break
+		}
+		for _, val := range data {
+			rows = append(rows, exportValues(val.ProspectID, val.BranchName, val.IncomingSource, val.OrderAt, val.CustomerStatus, utils.MaskIDNumber(val.IDNumber), val.LegalName, utils.MaskPhone(val.MobilePhone),
+				val.Supplier, val.AssetDescription, val.ManufacturingYear, val.LicensePlate, val.OTR, val.DPAmount, val.NTF, val.InstallmentPeriod, val.MonthlyInstallment,
+				val.Activity, val.SourceDecision, val.Decision, val.Reason, val.DecisionName, val.DecisionAt))
+		}
+
+	case constant.EXPORT_TYPE_CA, constant.EXPORT_TYPE_APPROVAL:
+		var data []entity.InquiryCa
+		if inquiryType == constant.EXPORT_TYPE_CA {
+			var req request.ReqInquiryCa
+			if err = json.Unmarshal([]byte(filter), &req); err != nil {
+				return
+			}
+			data, _, err = u.repository.GetInquiryCa(req, pagination)
+		} else {
+			var req request.ReqInquiryApproval
+			if err = json.Unmarshal([]byte(filter), &req); err != nil {
+				return
+			}
+			data, _, err = u.repository.GetInquiryApproval(req, pagination)
+		}
+		if err != nil {
+			break
+		}
+		for _, val := range data {
+			rows = append(rows, exportValues(val.ProspectID, val.BranchName, val.IncomingSource, val.OrderAt, val.CustomerStatus, utils.MaskIDNumber(val.IDNumber), val.LegalName, utils.MaskPhone(val.MobilePhone),
+				val.Supplier, val.AssetDescription, val.ManufacturingYear, val.LicensePlate, val.OTR, val.DPAmount, val.NTF, val.InstallmentPeriod, val.MonthlyInstallment,
+				val.Activity, val.SourceDecision, val.StatusDecision, val.StatusReason, val.CaDecision, val.FinalApproval))
+		}
+
+	case constant.EXPORT_TYPE_SEARCH:
+		var (
+			req  request.ReqSearchInquiry
+			data []entity.InquirySearch
+		)
+		if err = json.Unmarshal([]byte(filter), &req); err != nil {
+			return
+		}
+		if data, _, err = u.repository.GetInquirySearch(req, pagination); err != nil {
+			break
+		}
+		for _, val := range data {
+			rows = append(rows, exportValues(val.ProspectID, val.BranchName, val.IncomingSource, val.OrderAt, val.CustomerStatus, utils.MaskIDNumber(val.IDNumber), val.LegalName, utils.MaskPhone(val.MobilePhone),
+				val.Supplier, val.AssetDescription, val.ManufacturingYear, val.LicensePlate, val.OTR, val.DPAmount, val.NTF, val.InstallmentPeriod, val.MonthlyInstallment,
+				val.FinalStatus))
+		}
+
+	case constant.EXPORT_TYPE_LIST_ORDER:
+		var (
+			req  request.ReqInquiryListOrder
+			data []entity.InquiryDataListOrder
+		)
+		if err = json.Unmarshal([]byte(filter), &req); err != nil {
+			return
+		}
+		if data, _, err = u.repository.GetInquiryListOrder(req, pagination); err != nil {
+			break
+		}
+		for _, val := range data {
+			rows = append(rows, exportValues(val.OrderAt, val.BranchName, val.ProspectID, val.LegalName, utils.MaskIDNumber(val.IDNumber), val.BirthDate.Format(constant.FORMAT_DATE),
+				val.Profession, val.JobType, val.JobPosition, val.IsHighRisk, val.Decision, val.SourceDecision, val.RuleCode, val.Reason, val.DecisionBy, val.DecisionAt))
+		}
+
+	default:
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - inquiry_type tidak dikenali")
+	}
+
+	// a page past the last row is not an error, it ends the export
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		err = nil
+	}
+
+	return
+}
+
+// exportValues normalizes the values of a row so both xlsx and csv render them the same way
+func exportValues(values ...interface{}) []interface{} {
+
+	for i, val := range values {
+		switch v := val.(type) {
+		case nil:
+			values[i] = ""
+		case *string:
+			if v == nil {
+				values[i] = ""
+			} else {
+				values[i] = *v
+			}
+		case time.Time:
+			if v.IsZero() {
+				values[i] = ""
+			} else {
+				values[i] = v.Format(constant.FORMAT_DATE_TIME)
+			}
+		case []byte:
+			values[i] = string(v)
+		}
+	}
+
+	return values
+}
+
+func exportJobResponse(job entity.TrxExportJob) response.ExportJob {
+
+	data := response.ExportJob{
+		ID:          job.ID,
+		InquiryType: job.InquiryType,
+		FileFormat:  job.FileFormat,
+		Status:      job.Status,
+		TotalRows:   job.TotalRows,
+	}
+
+	if job.Status == constant.EXPORT_STATUS_DONE {
+		data.DownloadURL = fmt.Sprintf("/api/v3/kmb/cms/export/%s/download", job.ID)
+	}
+
+	return data
+}
+
+func exportFilePath(job entity.TrxExportJob) string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("export_%s.%s", job.ID, job.FileFormat))
+}
+
+func newExportWriter(format, path, sheetName string) (exportWriter, error) {
+
+	if format == constant.EXPORT_FORMAT_CSV {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &csvExportWriter{file: file, writer: csv.NewWriter(file)}, nil
+	}
+
+	xlsx := excelize.NewFile()
+
+	if err := xlsx.SetSheetName("Sheet1", sheetName); err != nil {
+		xlsx.Close()
+		return nil, err
+	}
+
+	stream, err := xlsx.NewStreamWriter(sheetName)
+	if err != nil {
+		xlsx.Close()
+		return nil, err
+	}
+
+	return &xlsxExportWriter{file: xlsx, stream: stream, path: path}, nil
+}
+
+func (w *xlsxExportWriter) WriteRow(row []interface{}) error {
+
+	w.row++
+
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+
+	return w.stream.SetRow(cell, row)
+}
+
+func (w *xlsxExportWriter) Close() error {
+
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.SaveAs(w.path)
+}
+
+func (w *csvExportWriter) WriteRow(row []interface{}) error {
+
+	record := make([]string, len(row))
+	for i, val := range row {
+		record[i] = fmt.Sprint(val)
+	}
+
+	return w.writer.Write(record)
+}
+
+func (w *csvExportWriter) Close() error {
+
+	defer w.file.Close()
+
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// generateLocalFormAKKK renders the form akkk of the order with the current layout and stores it in media as a pdf
+func (u usecase) generateLocalFormAKKK(ctx context.Context, prospectID, accessToken string) (data response.ResponseGenerateFormAKKK, err error) {
+
+	akkk, err := u.GetAkkk(prospectID)
+	if err != nil {
+		return
+	}
+
+	layout, err := renderFormAKKK(akkk, constant.FORM_AKKK_TEMPLATE_VERSION)
+	if err != nil {
+		return
+	}
+
+	document, err := formAkkkPDF(layout)
+	if err != nil {
+		return
+	}
+
+	return u.uploadMedia(ctx, request.ReqMediaUpload{
+		FileName:    fmt.Sprintf("FORM_AKKK_%s_%s.pdf", prospectID, constant.FORM_AKKK_TEMPLATE_VERSION),
+		ContentType: "application/pdf",
+		Content:     bytes.NewReader(document),
+		Type:        "FORM_AKKK",
+	}, accessToken)
+}
+
+// uploadMedia stores a file generated by los in media, the file is served from the returned url
+func (u usecase) uploadMedia(ctx context.Context, upload request.ReqMediaUpload, accessToken string) (data response.ResponseGenerateFormAKKK, err error) {
+
+	header := map[string]string{
+		"Authorization": os.Getenv("MEDIA_AUTH"),
+	}
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	resp, err := u.httpclient.MediaClient(ctx, constant.NEW_KMB_LOG, os.Getenv("MEDIA_UPLOAD_URL"), constant.METHOD_POST, upload, header, timeout, 0, accessToken)
+	if err != nil || resp.StatusCode() != 200 {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Upload " + upload.Type + " to media error")
+		return
+	}
+
+	json.Unmarshal([]byte(jsoniter.Get(resp.Body(), "data").ToString()), &data)
+
+	if data.MediaUrl == "" {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Unmarshal MediaUrl " + upload.Type + " Error")
+		return
+	}
+
+	return
+}
+
+// renderFormAKKK fills a version of the form akkk layout
+func renderFormAKKK(akkk entity.Akkk, version string) (layout formAkkkLayout, err error) {
+
+	fill, ok := formAkkkLayouts[version]
+	if !ok {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Template Form AKKK " + version + " tidak ditemukan")
+		return
+	}
+
+	layout = fill(akkk)
+	layout.Subtitle = fmt.Sprintf("Prospect ID %s · dibuat %s · template %s", akkk.ProspectID, time.Now().Format(constant.FORMAT_DATE_TIME), version)
+
+	return
+}
+
+func formAkkkValue(value interface{}) string {
+
+	switch v := value.(type) {
+	case nil:
+		return "-"
+	case []byte:
+		return formAkkkValue(string(v))
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return "-"
+		}
+		return v
+	case time.Time:
+		return v.Format(constant.FORMAT_DATE_TIME)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "Ya"
+		}
+		return "Tidak"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func formAkkkDate(value interface{}) string {
+
+	if t, ok := value.(time.Time); ok {
+		return t.Format(constant.FORMAT_DATE)
+	}
+
+	return formAkkkValue(value)
+}
+
+func formAkkkRupiah(value interface{}) string {
+
+	if value == nil {
+		return "-"
+	}
+
+	amount, err := utils.GetFloat(value)
+	if err != nil {
+		return formAkkkValue(value)
+	}
+
+	digits := strconv.FormatFloat(amount, 'f', 0, 64)
+
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "." + digits[i:]
+	}
+
+	return "Rp " + sign + digits
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"los-kmb-api/models/entity"
+)
+
+// formAkkkLayout is the whole form akkk, every part of it is drawn by formAkkkPDF
+type formAkkkLayout struct {
+	Title    string
+	Subtitle string
+	Sections []formAkkkSection
+}
+
+// formAkkkSection is a titled table of the form, a labeled section shades its first column
+type formAkkkSection struct {
+	Title   string
+	Header  []string
+	Labeled bool
+	Rows    [][]string
+}
+
+// formAkkkLayouts are the versions of the form akkk, a version is never changed once used so old forms can be rendered again
+var formAkkkLayouts = map[string]func(akkk entity.Akkk) formAkkkLayout{
+	"v1": formAkkkLayoutV1,
+}
+
+func formAkkkLayoutV1(akkk entity.Akkk) formAkkkLayout {
+
+	return formAkkkLayout{Title: "ANALISA KELAYAKAN KREDIT KONSUMEN (AKKK)", Sections: []formAkkkSection{
+		{
+			Title:   "Data Pengajuan",
+			Labeled: true,
+			Rows: [][]string{
+				{"Tujuan Pembiayaan", formAkkkValue(akkk.FinancePurpose)},
+				{"Status Konsumen", formAkkkValue(akkk.CustomerStatus)},
+				{"Segmen Konsumen", formAkkkValue(akkk.CustomerSegment)},
+				{"Nama BPKB", formAkkkValue(akkk.BpkbName)},
+			},
+		},
+		{
+			Title:   "Data Konsumen",
+			Labeled: true,
+			Rows: [][]string{
+				{"Nama Lengkap", formAkkkValue(akkk.LegalName)},
+				{"No. KTP", formAkkkValue(akkk.IDNumber)},
+				{"NPWP", formAkkkValue(akkk.PersonalNPWP)},
+				{"Nama Ibu Kandung", formAkkkValue(akkk.SurgateMotherName)},
+				{"Tipe Konsumen", formAkkkValue(akkk.CustomerType)},
+				{"Jenis Kelamin", formAkkkValue(akkk.Gender)},
+				{"Tempat, Tanggal Lahir", formAkkkValue(akkk.BirthPlace) + ", " + formAkkkDate(akkk.BirthDate)},
+				{"Pendidikan", formAkkkValue(akkk.Education)},
+				{"No. HP", formAkkkValue(akkk.MobilePhone)},
+				{"Email", formAkkkValue(akkk.Email)},
+				{"Alamat", formAkkkValue(akkk.Address)},
+				{"Lama Tinggal", formAkkkValue(akkk.StaySinceYear) + " tahun " + formAkkkValue(akkk.StaySinceMonth) + " bulan"},
+			},
+		},
+		{
+			Title:   "Data Pasangan",
+			Labeled: true,
+			Rows: [][]string{
+				{"Nama Lengkap", formAkkkValue(akkk.SpouseLegalName)},
+				{"No. KTP", formAkkkValue(akkk.SpouseIDNumber)},
+				{"Nama Ibu Kandung", formAkkkValue(akkk.SpouseSurgateMotherName)},
+				{"Pekerjaan", formAkkkValue(akkk.SpouseProfessionID)},
+				{"Jenis Kelamin", formAkkkValue(akkk.SpouseGender)},
+				{"Tempat, Tanggal Lahir", formAkkkValue(akkk.SpouseBirthPlace) + ", " + formAkkkDate(akkk.SpouseBirthDate)},
+				{"No. HP", formAkkkValue(akkk.SpouseMobilePhone)},
+			},
+		},
+		{
+			Title:   "Verifikasi",
+			Labeled: true,
+			Rows: [][]string{
+				{"Verifikasi Dengan", formAkkkValue(akkk.VerificationWith)},
+				{"Hubungan Emergency Contact", formAkkkValue(akkk.EmconRelationship)},
+				{"No. HP Emergency Contact", formAkkkValue(akkk.EmconMobilePhone)},
+				{"Emergency Contact Terverifikasi", formAkkkValue(akkk.EmconVerified)},
+				{"Diverifikasi Oleh", formAkkkValue(akkk.VerifyBy)},
+				{"Mengetahui Alamat Konsumen", formAkkkValue(akkk.KnownCustomerAddress)},
+				{"Mengetahui Pekerjaan Konsumen", formAkkkValue(akkk.KnownCustomerJob)},
+				{"Sumber E-KYC", formAkkkValue(akkk.EkycSource)},
+				{"Kemiripan E-KYC", formAkkkValue(akkk.EkycSimiliarity)},
+			},
+		},
+		{
+			Title:   "Pekerjaan dan Penghasilan",
+			Labeled: true,
+			Rows: [][]string{
+				{"Pekerjaan", formAkkkValue(akkk.Job)},
+				{"Profesi", formAkkkValue(akkk.ProfessionID)},
+				{"Jenis Industri", formAkkkValue(akkk.IndustryType)},
+				{"Lama Bekerja", formAkkkValue(akkk.EmploymentSinceYear) + " tahun " + formAkkkValue(akkk.EmploymentSinceMonth) + " bulan"},
+				{"Penghasilan Tetap", formAkkkRupiah(akkk.MonthlyFixedIncome)},
+				{"Penghasilan Lainnya", formAkkkRupiah(akkk.MonthlyVariableIncome)},
+				{"Penghasilan Pasangan", formAkkkRupiah(akkk.SpouseIncome)},
+				{"Total Penghasilan", formAkkkRupiah(akkk.TotalIncome)},
+				{"Total Angsuran", formAkkkRupiah(akkk.TotalInstallment)},
+				{"Total DSR", formAkkkValue(akkk.TotalDSR)},
+			},
+		},
+		{
+			Title:   "SLIK",
+			Header:  []string{"", "Konsumen", "Pasangan"},
+			Labeled: true,
+			Rows: [][]string{
+				{"Plafond", formAkkkRupiah(akkk.Plafond), formAkkkRupiah(akkk.SpousePlafond)},
+				{"Baki Debet", formAkkkRupiah(akkk.BakiDebet), formAkkkRupiah(akkk.SpouseBakiDebet)},
+				{"Fasilitas Aktif", formAkkkValue(akkk.FasilitasAktif), formAkkkValue(akkk.SpouseFasilitasAktif)},
+				{"Kolektibilitas Terburuk", formAkkkValue(akkk.ColTerburuk), formAkkkValue(akkk.SpouseColTerburuk)},
+				{"Baki Debet Terburuk", formAkkkRupiah(akkk.BakiDebetTerburuk), formAkkkRupiah(akkk.SpouseBakiDebetTerburuk)},
+				{"Kolektibilitas Terakhir Aktif", formAkkkValue(akkk.ColTerakhirAktif), formAkkkValue(akkk.SpouseColTerakhirAktif)},
+			},
+		},
+		{
+			Title:   "Data Internal",
+			Labeled: true,
+			Rows: [][]string{
+				{"SCS Score", formAkkkValue(akkk.ScsScore)},
+				{"Status Agreement", formAkkkValue(akkk.AgreementStatus)},
+				{"Total Agreement Aktif", formAkkkValue(akkk.TotalAgreementAktif)},
+				{"Max OVD Agreement Aktif", formAkkkValue(akkk.MaxOVDAgreementAktif)},
+				{"Max OVD Agreement Terakhir", formAkkkValue(akkk.LastMaxOVDAgreement)},
+				{"Angsuran Terakhir", formAkkkRupiah(akkk.LatestInstallment)},
+				{"NTF Akumulasi", formAkkkRupiah(akkk.NTFAkumulasi)},
+			},
+		},
+		{
+			Title:  "Keputusan",
+			Header: []string{"Level", "Nama", "Keputusan", "Catatan", "Tanggal"},
+			Rows: [][]string{
+				{"CMO", formAkkkValue(akkk.CmoName), formAkkkValue(akkk.CmoDecision), "-", formAkkkDate(akkk.CmoDate)},
+				{"CA", formAkkkValue(akkk.CaName), formAkkkValue(akkk.CaDecision), formAkkkValue(akkk.CaNote), formAkkkDate(akkk.CaDate)},
+				{"CBM", formAkkkValue(akkk.CbmName), formAkkkValue(akkk.CbmDecision), formAkkkValue(akkk.CbmNote), formAkkkDate(akkk.CbmDate)},
+				{"DRM", formAkkkValue(akkk.DrmName), formAkkkValue(akkk.DrmDecision), formAkkkValue(akkk.DrmNote), formAkkkDate(akkk.DrmDate)},
+				{"GMO", formAkkkValue(akkk.GmoName), formAkkkValue(akkk.GmoDecision), formAkkkValue(akkk.GmoNote), formAkkkDate(akkk.GmoDate)},
+			},
+		},
+	}}
+}
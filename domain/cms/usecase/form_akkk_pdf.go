@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"los-kmb-api/shared/constant"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	formAkkkPageMargin  = 15.0
+	formAkkkLineHeight  = 4.5
+	formAkkkLabelWidth  = 0.3
+	formAkkkCellPadding = 1.5
+)
+
+// formAkkkPDF draws the form akkk layout as an A4 pdf, the title and subtitle centered and every section a titled table
+func formAkkkPDF(layout formAkkkLayout) (data []byte, err error) {
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(formAkkkPageMargin, formAkkkPageMargin, formAkkkPageMargin)
+	pdf.SetAutoPageBreak(true, formAkkkPageMargin)
+	pdf.SetTextColor(34, 34, 34)
+	pdf.AddPage()
+
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	width, _ := pdf.GetPageSize()
+	width -= 2 * formAkkkPageMargin
+
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 8, translate(layout.Title), "", 1, "C", false, 0, "")
+
+	pdf.SetFont("Arial", "", 8)
+	pdf.SetTextColor(102, 102, 102)
+	pdf.CellFormat(0, 5, translate(layout.Subtitle), "", 1, "C", false, 0, "")
+	pdf.SetTextColor(34, 34, 34)
+	pdf.Ln(2)
+
+	for _, section := range layout.Sections {
+		pdf.Ln(3)
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetFillColor(232, 232, 232)
+		pdf.CellFormat(0, 6, translate(section.Title), "", 1, "L", true, 0, "")
+		pdf.Ln(1)
+
+		widths := formAkkkWidths(width, section)
+
+		if len(section.Header) > 0 {
+			formAkkkRow(pdf, translate, widths, section.Header, true, false)
+		}
+
+		for _, row := range section.Rows {
+			formAkkkRow(pdf, translate, widths, row, false, section.Labeled)
+		}
+	}
+
+	if err = pdf.Error(); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Render PDF Form AKKK error")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = pdf.Output(&buf); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Render PDF Form AKKK error")
+		return
+	}
+
+	return buf.Bytes(), nil
+}
+
+// formAkkkWidths gives the label column of a labeled section 30% of the width and shares the rest evenly
+func formAkkkWidths(width float64, section formAkkkSection) []float64 {
+
+	columns := len(section.Header)
+	for _, row := range section.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	if columns == 0 {
+		return nil
+	}
+
+	widths := make([]float64, columns)
+
+	if section.Labeled && columns > 1 {
+		widths[0] = width * formAkkkLabelWidth
+		for i := 1; i < columns; i++ {
+			widths[i] = (width - widths[0]) / float64(columns-1)
+		}
+		return widths
+	}
+
+	for i := range widths {
+		widths[i] = width / float64(columns)
+	}
+
+	return widths
+}
+
+// formAkkkRow draws one row of bordered cells as high as its longest cell, moving to a new page when it does not fit
+func formAkkkRow(pdf *fpdf.Fpdf, translate func(string) string, widths []float64, cells []string, header, labeled bool) {
+
+	style := ""
+	if header {
+		style = "B"
+	}
+
+	pdf.SetFont("Arial", style, 8)
+
+	lines := 1
+	for i, cell := range cells {
+		if n := len(pdf.SplitLines([]byte(translate(cell)), widths[i]-2*formAkkkCellPadding)); n > lines {
+			lines = n
+		}
+	}
+
+	height := float64(lines)*formAkkkLineHeight + formAkkkCellPadding
+
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+height > pageHeight-formAkkkPageMargin {
+		pdf.AddPage()
+	}
+
+	x, y := pdf.GetXY()
+
+	for i := range widths {
+
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+
+		fill := "D"
+		if header || (labeled && i == 0) {
+			pdf.SetFillColor(244, 244, 244)
+			fill = "FD"
+		}
+
+		pdf.SetDrawColor(204, 204, 204)
+		pdf.Rect(x, y, widths[i], height, fill)
+
+		pdf.SetXY(x+formAkkkCellPadding, y+formAkkkCellPadding/2)
+		pdf.MultiCell(widths[i]-2*formAkkkCellPadding, formAkkkLineHeight, translate(cell), "", "L", false)
+
+		x += widths[i]
+	}
+
+	pdf.SetXY(formAkkkPageMargin, y+height)
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"bytes"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderFormAKKK(t *testing.T) {
+
+	akkk := entity.Akkk{
+		ProspectID:         "SAL-1140024080800004",
+		LegalName:          "BUDI SANTOSO",
+		BirthPlace:         "JAKARTA",
+		BirthDate:          time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		MonthlyFixedIncome: float64(7500000),
+		Plafond:            float64(25000000),
+		CaNote:             strings.Repeat("catatan panjang yang harus dibungkus ke beberapa baris ", 20),
+	}
+
+	testcases := []struct {
+		name    string
+		version string
+		err     string
+	}{
+		{name: "current version", version: constant.FORM_AKKK_TEMPLATE_VERSION},
+		{name: "v1", version: "v1"},
+		{name: "unknown version", version: "v0", err: constant.ERROR_UPSTREAM + " - Template Form AKKK v0 tidak ditemukan"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			layout, err := renderFormAKKK(akkk, tc.version)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, layout.Title)
+			assert.Contains(t, layout.Subtitle, akkk.ProspectID)
+			assert.NotEmpty(t, layout.Sections)
+
+			for _, section := range layout.Sections {
+				assert.NotEmpty(t, section.Title)
+				assert.NotEmpty(t, section.Rows, section.Title)
+
+				for _, row := range section.Rows {
+					if len(section.Header) > 0 {
+						assert.Len(t, row, len(section.Header), section.Title)
+					}
+				}
+			}
+
+			assert.Contains(t, layout.Sections[1].Rows[6][1], "JAKARTA, 1990-05-17")
+
+			document, err := formAkkkPDF(layout)
+
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(document, []byte("%PDF-")))
+		})
+	}
+}
+
+func TestFormAkkkValue(t *testing.T) {
+
+	testcases := []struct {
+		name     string
+		format   func(value interface{}) string
+		value    interface{}
+		expected string
+	}{
+		{name: "value nil", format: formAkkkValue, value: nil, expected: "-"},
+		{name: "value blank", format: formAkkkValue, value: "  ", expected: "-"},
+		{name: "value bytes", format: formAkkkValue, value: []byte("KARYAWAN"), expected: "KARYAWAN"},
+		{name: "value float", format: formAkkkValue, value: float64(35.5), expected: "35.5"},
+		{name: "value bool", format: formAkkkValue, value: true, expected: "Ya"},
+		{name: "value time", format: formAkkkValue, value: time.Date(2024, 8, 8, 10, 30, 0, 0, time.UTC), expected: "2024-08-08 10:30:00"},
+		{name: "date time", format: formAkkkDate, value: time.Date(2024, 8, 8, 10, 30, 0, 0, time.UTC), expected: "2024-08-08"},
+		{name: "date nil", format: formAkkkDate, value: nil, expected: "-"},
+		{name: "rupiah nil", format: formAkkkRupiah, value: nil, expected: "-"},
+		{name: "rupiah thousands", format: formAkkkRupiah, value: float64(7500000), expected: "Rp 7.500.000"},
+		{name: "rupiah below thousand", format: formAkkkRupiah, value: 950, expected: "Rp 950"},
+		{name: "rupiah negative", format: formAkkkRupiah, value: float64(-1250000), expected: "Rp -1.250.000"},
+		{name: "rupiah string", format: formAkkkRupiah, value: "2000000", expected: "Rp 2.000.000"},
+		{name: "rupiah not a number", format: formAkkkRupiah, value: "N/A", expected: "N/A"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.format(tc.value))
+		})
+	}
+}
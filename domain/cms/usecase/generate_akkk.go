@@ -7,6 +7,7 @@ import (
 	"los-kmb-api/models/entity"
 	"los-kmb-api/models/request"
 	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common"
 	"los-kmb-api/shared/constant"
 	"os"
 
@@ -27,6 +28,28 @@ func (u usecase) GenerateFormAKKK(ctx context.Context, req request.RequestGenera
 		return
 	}
 
+	// render the form locally, the generator api is the fallback
+	if os.Getenv("FORM_AKKK_RENDERER") != constant.FORM_AKKK_RENDERER_REMOTE {
+		responseAkkk, errLocal := u.generateLocalFormAKKK(ctx, req.ProspectID, accessToken)
+		if errLocal == nil {
+			if errLocal = u.repository.SaveUrlFormAKKK(req.ProspectID, responseAkkk.MediaUrl); errLocal == nil {
+				data = responseAkkk
+				return
+			}
+		}
+
+		common.CentralizeLog(ctx, accessToken, common.CentralizeLogParameter{
+			Link:       os.Getenv("DUMMY_URL_LOGS"),
+			Action:     "GENERATE_FORM_AKKK",
+			Type:       "LOCAL",
+			LogFile:    constant.NEW_KMB_LOG,
+			MsgLogFile: "LOS - Generate Form AKKK",
+			LevelLog:   constant.PLATFORM_LOG_LEVEL_ERROR,
+			Request:    req,
+			Response:   errLocal.Error(),
+		})
+	}
+
 	// call generator form akkk api
 	payload, _ := json.Marshal(req)
 	respAPI, errAPI := u.httpclient.EngineAPI(ctx, constant.NEW_KMB_LOG, os.Getenv("GENERATOR_FORM_AKKK_URL"), payload, map[string]string{}, constant.METHOD_POST, false, 0, 60, req.ProspectID, accessToken)
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"time"
+)
+
+func (u usecase) GetInquiryLockSystem(req request.ReqListLockSystem, pagination interface{}) (data []entity.InquiryLockSystem, rowTotal int, err error) {
+
+	if req.IDNumber == "" && req.ChassisNumber == "" && req.EngineNumber == "" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - id_number, chassis_number atau engine_number wajib diisi")
+		return
+	}
+
+	data, rowTotal, err = u.repository.GetInquiryLockSystem(req, pagination)
+
+	if err != nil {
+		if err.Error() != constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Inquiry Lock System error")
+		}
+		return
+	}
+
+	return
+}
+
+// UpdateLockSystem lifts, extends or shortens one lock, every change is kept in trx_lock_override
+func (u usecase) UpdateLockSystem(ctx context.Context, req request.ReqUpdateLockSystem) (data response.UpdateLockSystemResponse, err error) {
+
+	var (
+		entry     entity.TrxLockOverride
+		unbanDate time.Time
+	)
+
+	entry, err = u.repository.GetLockSystemEntry(req.LockType, req.ProspectID)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Data lock tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Lock System Entry error")
+		}
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	currentUnbanDate := time.Date(entry.UnbanDate.Year(), entry.UnbanDate.Month(), entry.UnbanDate.Day(), 0, 0, 0, 0, now.Location())
+	isActive := currentUnbanDate.After(today)
+
+	if req.Action == constant.LOCK_ACTION_LIFT {
+		if !isActive {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Lock sudah tidak aktif")
+			return
+		}
+		// the lock checks treat an unban date of today as released
+		unbanDate = today
+	} else {
+		unbanDate, err = time.ParseInLocation(constant.FORMAT_DATE, req.UnbanDate, now.Location())
+		if err != nil {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Format unban_date tidak valid")
+			return
+		}
+
+		if !unbanDate.After(today) {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - unban_date harus lebih dari hari ini")
+			return
+		}
+
+		if req.Action == constant.LOCK_ACTION_EXTEND && !unbanDate.After(currentUnbanDate) {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - unban_date harus lebih dari unban date saat ini")
+			return
+		}
+
+		if req.Action == constant.LOCK_ACTION_SHORTEN {
+			if !isActive {
+				err = errors.New(constant.ERROR_BAD_REQUEST + " - Lock sudah tidak aktif")
+				return
+			}
+			if !unbanDate.Before(currentUnbanDate) {
+				err = errors.New(constant.ERROR_BAD_REQUEST + " - unban_date harus kurang dari unban date saat ini")
+				return
+			}
+		}
+	}
+
+	override := entity.TrxLockOverride{
+		ID:              utils.GenerateUUID(),
+		LockType:        req.LockType,
+		BannedType:      entry.BannedType,
+		ProspectID:      req.ProspectID,
+		IDNumber:        entry.IDNumber,
+		ChassisNumber:   entry.ChassisNumber,
+		EngineNumber:    entry.EngineNumber,
+		Action:          req.Action,
+		UnbanDateBefore: currentUnbanDate,
+		UnbanDate:       unbanDate,
+		Reason:          req.Reason,
+		CreatedBy:       req.UpdatedBy,
+		CreatedByName:   req.UpdatedByName,
+		CreatedAt:       now,
+	}
+
+	if err = u.repository.SaveLockOverride(override); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Save Lock Override error")
+		return
+	}
+
+	data = response.UpdateLockSystemResponse{
+		Status:          constant.RESULT_OK,
+		Message:         constant.LOCK_OVERRIDE_SUCCESS,
+		LockType:        req.LockType,
+		ProspectID:      req.ProspectID,
+		Action:          req.Action,
+		UnbanDateBefore: currentUnbanDate.Format(constant.FORMAT_DATE),
+		UnbanDateAfter:  unbanDate.Format(constant.FORMAT_DATE),
+	}
+
+	return
+}
+
+func (u usecase) GetLockSystemHistory(lockType, prospectID string) (data []entity.TrxLockOverride, err error) {
+
+	data, err = u.repository.GetLockOverrideHistory(lockType, prospectID)
+
+	if err != nil {
+		if err.Error() != constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Lock Override History error")
+		}
+		return
+	}
+
+	return
+}
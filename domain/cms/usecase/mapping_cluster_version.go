@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"time"
+)
+
+func (u usecase) GetMappingClusterVersions(pagination interface{}) (data []entity.InquiryMappingClusterVersion, rowTotal int, err error) {
+
+	data, rowTotal, err = u.repository.GetMappingClusterVersions(pagination)
+
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// DiffMappingClusterVersion lists the mappings added, changed or removed going from one version to another
+func (u usecase) DiffMappingClusterVersion(ctx context.Context, req request.ReqDiffMappingCluster) (data response.MappingClusterVersionDiff, err error) {
+
+	from, err := u.mappingClusterVersion(req.FromVersion)
+	if err != nil {
+		return
+	}
+
+	to, err := u.mappingClusterVersion(req.ToVersion)
+	if err != nil {
+		return
+	}
+
+	data = response.MappingClusterVersionDiff{
+		FromVersion: req.FromVersion,
+		ToVersion:   req.ToVersion,
+		Changes:     diffMappingCluster(from, to),
+	}
+
+	return
+}
+
+// RollbackMappingCluster replaces the current mapping with the snapshot of a previous version in one transaction
+func (u usecase) RollbackMappingCluster(ctx context.Context, req request.ReqRollbackMappingCluster) (data response.MappingClusterVersionDiff, err error) {
+
+	cluster, err := u.mappingClusterVersion(req.Version)
+	if err != nil {
+		return
+	}
+
+	if len(cluster) == 0 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + fmt.Sprintf(" - Mapping cluster versi %d kosong", req.Version))
+		return
+	}
+
+	jsonDataAfter, err := json.Marshal(cluster)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error converting to JSON mapping cluster after")
+		return
+	}
+
+	// data_before is filled in by the repository from the mapping it locks
+	history := entity.HistoryConfigChanges{
+		ID:         utils.GenerateUUID(),
+		ConfigID:   constant.MAPPING_CLUSTER_CONFIG_ID,
+		ObjectName: constant.MAPPING_CLUSTER_CONFIG_ID,
+		Action:     constant.MAPPING_CLUSTER_ROLLBACK,
+		DataAfter:  string(jsonDataAfter),
+		CreatedBy:  req.UserID,
+		CreatedAt:  time.Now(),
+	}
+
+	existingCluster, err := u.repository.RollbackMappingCluster(cluster, history, req.Version)
+	if err != nil {
+		if err.Error() == constant.ERROR_ROWS_AFFECTED {
+			err = errors.New(constant.ERROR_BAD_REQUEST + fmt.Sprintf(" - Mapping cluster sudah sama dengan versi %d", req.Version))
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Rollback mapping cluster error")
+		return
+	}
+
+	data = response.MappingClusterVersionDiff{
+		ToVersion: req.Version,
+		Changes:   diffMappingCluster(existingCluster, cluster),
+	}
+
+	return
+}
+
+func (u usecase) mappingClusterVersion(version int) (cluster []entity.MasterMappingCluster, err error) {
+
+	snapshot, err := u.repository.GetMappingClusterVersion(version)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + fmt.Sprintf(" - Mapping cluster versi %d tidak ditemukan", version))
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get mapping cluster version error")
+		return
+	}
+
+	if err = json.Unmarshal([]byte(snapshot.Data), &cluster); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error unmarshal mapping cluster version")
+		return
+	}
+
+	return
+}
@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"strings"
+	"time"
+)
+
+// masterDataConfigID is the config_id the changes of a reference list are audited with in history_config_changes
+func masterDataConfigID(dataType string) string {
+	switch dataType {
+	case constant.MASTER_DATA_CANCEL_REASON:
+		return "m_cancel_reason"
+	case constant.MASTER_DATA_APPROVAL_REASON:
+		return "m_approval_reason"
+	case constant.MASTER_DATA_PRESCREENING_REASON:
+		return "m_reason_message"
+	case constant.MASTER_DATA_INDUSTRY_TYPE:
+		return "m_industry_type"
+	}
+	return dataType
+}
+
+func (u usecase) GetMasterData(req request.ReqListMasterData, pagination interface{}) (data []entity.MasterData, rowTotal int, err error) {
+
+	data, err = u.repository.GetMasterData(req.Type)
+	if err != nil {
+		if err.Error() != constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Master Data error")
+		}
+		return
+	}
+
+	rowTotal = len(data)
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		json.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+		if offset > len(data) {
+			offset = len(data)
+		}
+
+		last := offset + paginationFilter.Limit
+		if last > len(data) {
+			last = len(data)
+		}
+
+		data = data[offset:last]
+	}
+
+	return
+}
+
+func (u usecase) CreateMasterData(ctx context.Context, req request.ReqMasterData) (data entity.MasterData, err error) {
+
+	rows, err := u.masterDataRows(req.Type)
+	if err != nil {
+		return
+	}
+
+	if _, exists := findMasterData(rows, req.ID, req.Group); exists {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - id sudah digunakan")
+		return
+	}
+
+	// a new row goes to the end of the list unless the position is given
+	sortOrder := req.SortOrder
+	if sortOrder == 0 {
+		for _, row := range rows {
+			if row.SortOrder >= sortOrder {
+				sortOrder = row.SortOrder + 1
+			}
+		}
+	}
+
+	now := time.Now()
+
+	data = entity.MasterData{
+		ID:          req.ID,
+		Group:       req.Group,
+		Code:        req.Code,
+		Description: req.Description,
+		SortOrder:   sortOrder,
+		IsActive:    true,
+		UpdatedBy:   req.UpdatedBy,
+		UpdatedAt:   &now,
+	}
+
+	err = u.saveMasterData(ctx, req.Type, constant.MASTER_DATA_ACTION_CREATE, nil, []entity.MasterData{data}, true)
+
+	return
+}
+
+// UpdateMasterData also restores a deleted row
+func (u usecase) UpdateMasterData(ctx context.Context, req request.ReqMasterData) (data entity.MasterData, err error) {
+
+	rows, err := u.masterDataRows(req.Type)
+	if err != nil {
+		return
+	}
+
+	before, exists := findMasterData(rows, req.ID, req.Group)
+	if !exists {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - data tidak ditemukan")
+		return
+	}
+
+	now := time.Now()
+
+	data = before
+	data.Code = req.Code
+	data.Description = req.Description
+	data.IsActive = true
+	data.UpdatedBy = req.UpdatedBy
+	data.UpdatedAt = &now
+
+	// the position is kept unless a new one is given
+	if req.SortOrder != 0 {
+		data.SortOrder = req.SortOrder
+	}
+
+	err = u.saveMasterData(ctx, req.Type, constant.MASTER_DATA_ACTION_UPDATE, []entity.MasterData{before}, []entity.MasterData{data}, false)
+
+	return
+}
+
+// DeleteMasterData only deactivates the row, orders created with it still show its description
+func (u usecase) DeleteMasterData(ctx context.Context, req request.ReqDeleteMasterData) (err error) {
+
+	rows, err := u.masterDataRows(req.Type)
+	if err != nil {
+		return
+	}
+
+	before, exists := findMasterData(rows, req.ID, req.Group)
+	if !exists {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - data tidak ditemukan")
+		return
+	}
+
+	now := time.Now()
+
+	data := before
+	data.IsActive = false
+	data.UpdatedBy = req.UpdatedBy
+	data.UpdatedAt = &now
+
+	return u.saveMasterData(ctx, req.Type, constant.MASTER_DATA_ACTION_DELETE, []entity.MasterData{before}, []entity.MasterData{data}, false)
+}
+
+// ReorderMasterData sets the sort_order of the given rows following their position in the request
+func (u usecase) ReorderMasterData(ctx context.Context, req request.ReqReorderMasterData) (err error) {
+
+	rows, err := u.masterDataRows(req.Type)
+	if err != nil {
+		return
+	}
+
+	var (
+		before []entity.MasterData
+		after  []entity.MasterData
+		now    = time.Now()
+	)
+
+	for i, id := range req.IDs {
+		row, exists := findMasterData(rows, id, req.Group)
+		if !exists {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - data " + id + " tidak ditemukan")
+			return
+		}
+
+		before = append(before, row)
+
+		row.SortOrder = i + 1
+		row.UpdatedBy = req.UpdatedBy
+		row.UpdatedAt = &now
+		after = append(after, row)
+	}
+
+	return u.saveMasterData(ctx, req.Type, constant.MASTER_DATA_ACTION_REORDER, before, after, false)
+}
+
+func (u usecase) GetMasterDataHistory(req request.ReqListMasterData, pagination interface{}) (data []entity.MasterDataChangeLog, rowTotal int, err error) {
+
+	data, rowTotal, err = u.repository.GetMasterDataHistory(masterDataConfigID(req.Type), pagination)
+
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Master Data History error")
+	}
+
+	return
+}
+
+// SyncMasterDataHistory copies the history a save could not copy to los right away, a change shows up in the history
+// once it is copied
+func (u usecase) SyncMasterDataHistory(ctx context.Context) (synced int, err error) {
+
+	synced, err = u.repository.SyncMasterDataHistory(constant.MASTER_DATA_HISTORY_SYNC_LIMIT)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Sync Master Data History error")
+	}
+
+	return
+}
+
+// RefreshIndustryType replaces the cached description of an industry type
+func (u usecase) RefreshIndustryType(id, description string) {
+	u.cache.SetWithExpiration(strings.ReplaceAll(id, " ", ""), []byte(description), time.Duration(constant.MASTER_DATA_CACHE_TTL)*time.Minute)
+}
+
+func (u usecase) masterDataRows(dataType string) (rows []entity.MasterData, err error) {
+
+	rows, err = u.repository.GetMasterData(dataType)
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Master Data error")
+		return
+	}
+
+	return rows, nil
+}
+
+func findMasterData(rows []entity.MasterData, id, group string) (entity.MasterData, bool) {
+	for _, row := range rows {
+		if strings.TrimSpace(row.ID) == strings.TrimSpace(id) && strings.TrimSpace(row.Group) == strings.TrimSpace(group) {
+			return row, true
+		}
+	}
+	return entity.MasterData{}, false
+}
+
+// saveMasterData writes the rows with the record of the change and refreshes the cached copy of the list, the record
+// is copied to history_config_changes right away or else by the master data history scheduler
+func (u usecase) saveMasterData(ctx context.Context, dataType, action string, before, after []entity.MasterData, isNew bool) (err error) {
+
+	dataBefore, _ := json.Marshal(before)
+	dataAfter, _ := json.Marshal(after)
+
+	history := entity.HistoryConfigChanges{
+		ID:         utils.GenerateUUID(),
+		ConfigID:   masterDataConfigID(dataType),
+		ObjectName: masterDataConfigID(dataType),
+		Action:     action,
+		DataBefore: string(dataBefore),
+		DataAfter:  string(dataAfter),
+		CreatedBy:  after[0].UpdatedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = u.repository.SaveMasterData(dataType, after, isNew, history); err != nil {
+		if err.Error() == constant.ERROR_ROWS_AFFECTED {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - data tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Save Master Data error")
+		}
+		return
+	}
+
+	// industry type descriptions are cached per id by the inquiries of every replica, the change is broadcast so each
+	// replica refreshes its own copy, a replica missing the event picks the change up when its entry expires
+	if dataType == constant.MASTER_DATA_INDUSTRY_TYPE {
+		industryTypes := map[string]interface{}{}
+		for _, row := range after {
+			u.RefreshIndustryType(row.ID, row.Description)
+			industryTypes[row.ID] = row.Description
+		}
+
+		_ = u.producer.PublishEvent(ctx, middlewares.UserInfoData.AccessToken, constant.TOPIC_MASTER_DATA, constant.KEY_PREFIX_MASTER_DATA, dataType, map[string]interface{}{
+			"type": dataType,
+			"data": industryTypes,
+		}, 0)
+	}
+
+	_, _ = u.repository.SyncMasterDataHistory(constant.MASTER_DATA_HISTORY_SYNC_LIMIT)
+
+	return
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SaveNEDraft keeps an unfinished new entry so the creator can resume it later, the payload is stored encrypted
+func (u usecase) SaveNEDraft(ctx context.Context, req request.ReqSaveNEDraft) (data response.NEDraft, err error) {
+
+	var metrics request.MetricsNE
+
+	if err = json.Unmarshal(req.Data, &metrics); err != nil {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - data draft tidak valid")
+		return
+	}
+
+	payload, err := utils.PlatformEncryptText(string(req.Data))
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Encrypt NE Draft error")
+		return
+	}
+
+	now := time.Now()
+
+	draft := entity.TrxNEDraft{
+		ID:            uuid.New().String(),
+		CreatedBy:     req.CreatedBy,
+		CreatedByName: req.CreatedByName,
+		BranchID:      metrics.Transaction.BranchID,
+		ProspectID:    metrics.Transaction.ProspectID,
+		Payload:       payload,
+		Status:        constant.NE_DRAFT_STATUS_DRAFT,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err = u.repository.SaveNEDraft(draft); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Save NE Draft error")
+		return
+	}
+
+	data = response.NEDraft{
+		ID:        draft.ID,
+		Status:    draft.Status,
+		Data:      req.Data,
+		UpdatedAt: now.Format(constant.FORMAT_DATE_TIME),
+	}
+
+	return
+}
+
+func (u usecase) UpdateNEDraft(ctx context.Context, req request.ReqUpdateNEDraft) (data response.NEDraft, err error) {
+
+	var metrics request.MetricsNE
+
+	if err = json.Unmarshal(req.Data, &metrics); err != nil {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - data draft tidak valid")
+		return
+	}
+
+	payload, err := utils.PlatformEncryptText(string(req.Data))
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Encrypt NE Draft error")
+		return
+	}
+
+	now := time.Now()
+
+	err = u.repository.UpdateNEDraft(req.ID, req.CreatedBy, map[string]interface{}{
+		"BranchID":   metrics.Transaction.BranchID,
+		"ProspectID": metrics.Transaction.ProspectID,
+		"payload":    payload,
+		"updated_at": now,
+	})
+
+	if err != nil {
+		if err.Error() == constant.ERROR_ROWS_AFFECTED {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - draft tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Update NE Draft error")
+		}
+		return
+	}
+
+	data = response.NEDraft{
+		ID:        req.ID,
+		Status:    constant.NE_DRAFT_STATUS_DRAFT,
+		Data:      req.Data,
+		UpdatedAt: now.Format(constant.FORMAT_DATE_TIME),
+	}
+
+	return
+}
+
+func (u usecase) GetNEDrafts(req request.ReqListNEDraft, pagination interface{}) (data []entity.InquiryNEDraft, rowTotal int, err error) {
+
+	data, rowTotal, err = u.repository.GetNEDrafts(req, pagination)
+
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get NE Drafts error")
+	}
+
+	return
+}
+
+// ResumeNEDraft returns the decrypted payload of an open draft owned by the creator
+func (u usecase) ResumeNEDraft(ctx context.Context, req request.ReqNEDraft) (data response.NEDraft, err error) {
+
+	draft, err := u.repository.GetNEDraft(req.ID, req.CreatedBy)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - draft tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get NE Draft error")
+		}
+		return
+	}
+
+	payload, err := utils.PlatformDecryptText(draft.Payload)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Decrypt NE Draft error")
+		return
+	}
+
+	data = response.NEDraft{
+		ID:        draft.ID,
+		Status:    draft.Status,
+		Data:      json.RawMessage(payload),
+		UpdatedAt: draft.UpdatedAt.Format(constant.FORMAT_DATE_TIME),
+	}
+
+	return
+}
+
+func (u usecase) DiscardNEDraft(ctx context.Context, req request.ReqNEDraft) (err error) {
+	return u.closeNEDraft(req.ID, req.CreatedBy, constant.NE_DRAFT_STATUS_DISCARDED)
+}
+
+// CompleteNEDraft closes the draft the order was submitted from
+func (u usecase) CompleteNEDraft(ctx context.Context, req request.ReqNEDraft) (err error) {
+	return u.closeNEDraft(req.ID, req.CreatedBy, constant.NE_DRAFT_STATUS_SUBMITTED)
+}
+
+func (u usecase) closeNEDraft(id, createdBy, status string) (err error) {
+
+	err = u.repository.UpdateNEDraft(id, createdBy, map[string]interface{}{
+		"status":     status,
+		"updated_at": time.Now(),
+	})
+
+	if err != nil {
+		if err.Error() == constant.ERROR_ROWS_AFFECTED {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - draft tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Update NE Draft error")
+		}
+	}
+
+	return
+}
+
+// PrecheckNE runs the synchronous rules of a new entry and returns every issue found instead of stopping at the first one
+func (u usecase) PrecheckNE(ctx context.Context, req request.MetricsNE) (data response.NEPrecheck, err error) {
+
+	data.Issues = []response.NEPrecheckIssue{}
+
+	if req.CustomerPersonal.OtherMobilePhone != "" && req.CustomerPersonal.OtherMobilePhone == req.CustomerPersonal.MobilePhone {
+		data.Issues = append(data.Issues, response.NEPrecheckIssue{
+			Rule:     constant.NE_PRECHECK_RULE_MOBILE_PHONE,
+			Severity: constant.NE_PRECHECK_SEVERITY_ERROR,
+			Message:  "Nomor HP lain harus berbeda dengan nomor HP utama",
+		})
+	}
+
+	locks, _, err := u.repository.GetInquiryLockSystem(request.ReqListLockSystem{
+		IDNumber:      req.CustomerPersonal.IDNumber,
+		ChassisNumber: req.Item.NoChassis,
+		EngineNumber:  req.Item.NoEngine,
+		IsActive:      "1",
+	}, nil)
+
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Lock System error")
+		return
+	}
+
+	for _, lock := range locks {
+		if !lock.IsActive {
+			continue
+		}
+		data.Issues = append(data.Issues, response.NEPrecheckIssue{
+			Rule:     lock.LockType,
+			Severity: constant.NE_PRECHECK_SEVERITY_ERROR,
+			Message:  fmt.Sprintf("%s aktif sampai %s (%s)", lock.LockType, lock.UnbanDate.Format(constant.FORMAT_DATE), lock.Reason),
+		})
+	}
+
+	incomes, err := u.repository.GetMappingIncomePMK(req.Transaction.BranchID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Income PMK error")
+		return
+	}
+
+	totalIncome := req.CustomerEmployment.MonthlyFixedIncome
+	if req.CustomerEmployment.MonthlyVariableIncome != nil {
+		totalIncome += *req.CustomerEmployment.MonthlyVariableIncome
+	}
+	if req.CustomerEmployment.SpouseIncome != nil {
+		totalIncome += *req.CustomerEmployment.SpouseIncome
+	}
+
+	var belowIncome []string
+	for _, income := range incomes {
+		if totalIncome < float64(income.Income) {
+			belowIncome = append(belowIncome, income.StatusKonsumen)
+		}
+	}
+
+	if len(belowIncome) > 0 {
+		issue := response.NEPrecheckIssue{
+			Rule:     constant.NE_PRECHECK_RULE_INCOME_PMK,
+			Severity: constant.NE_PRECHECK_SEVERITY_WARNING,
+			Message:  fmt.Sprintf("Total penghasilan di bawah minimum PMK untuk status konsumen %s", strings.Join(belowIncome, ", ")),
+		}
+		if len(belowIncome) == len(incomes) {
+			issue.Severity = constant.NE_PRECHECK_SEVERITY_ERROR
+			issue.Message = "Total penghasilan di bawah minimum PMK"
+		}
+		data.Issues = append(data.Issues, issue)
+	}
+
+	if req.Apk.Tenor >= constant.NE_PRECHECK_TENOR_THRESHOLD {
+
+		bpkbNameType := 0
+		if namaSama, _ := utils.ItemExists(req.Item.BPKBName, utils.AizuArrayString(os.Getenv("NAMA_SAMA"))); namaSama {
+			bpkbNameType = 1
+		}
+
+		clusters, err := u.repository.GetBranchClusters(req.Transaction.BranchID, bpkbNameType)
+		if err != nil {
+			return data, errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Cluster Branch error")
+		}
+
+		var rejectTenor []string
+		for _, cluster := range clusters {
+			if cluster.Cluster == constant.CLUSTER_E || cluster.Cluster == constant.CLUSTER_F {
+				rejectTenor = append(rejectTenor, cluster.CustomerStatus)
+			}
+		}
+
+		if len(rejectTenor) > 0 {
+			issue := response.NEPrecheckIssue{
+				Rule:     constant.NE_PRECHECK_RULE_TENOR,
+				Severity: constant.NE_PRECHECK_SEVERITY_WARNING,
+				Message:  fmt.Sprintf("Tenor %d bulan ditolak untuk status konsumen %s", req.Apk.Tenor, strings.Join(rejectTenor, ", ")),
+			}
+			if len(rejectTenor) == len(clusters) {
+				issue.Severity = constant.NE_PRECHECK_SEVERITY_ERROR
+				issue.Message = fmt.Sprintf("Tenor %d bulan ditolak untuk cluster cabang", req.Apk.Tenor)
+			}
+			data.Issues = append(data.Issues, issue)
+		}
+	}
+
+	data.Passed = true
+	for _, issue := range data.Issues {
+		if issue.Severity == constant.NE_PRECHECK_SEVERITY_ERROR {
+			data.Passed = false
+			break
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+	"os"
+	"time"
+)
+
+// notify sends the event in the background, a failed notification never fails the workflow transition
+func (u usecase) notify(event entity.NotificationEvent) {
+
+	if u.notification == nil {
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+
+		defer func() {
+			if r := recover(); r != nil {
+				logNotifyError(ctx, event, fmt.Errorf("panic: %v", r))
+			}
+		}()
+
+		if _, err := u.notification.Notify(ctx, event); err != nil {
+			logNotifyError(ctx, event, err)
+		}
+	}()
+}
+
+func logNotifyError(ctx context.Context, event entity.NotificationEvent, err error) {
+
+	common.CentralizeLog(ctx, middlewares.UserInfoData.AccessToken, common.CentralizeLogParameter{
+		Link:       os.Getenv("DUMMY_URL_LOGS"),
+		Action:     "NOTIFICATION",
+		Type:       event.EventType,
+		LogFile:    constant.NEW_KMB_LOG,
+		MsgLogFile: "LOS - Notify",
+		LevelLog:   constant.PLATFORM_LOG_LEVEL_ERROR,
+		Request:    event,
+		Response:   err.Error(),
+	})
+}
+
+// notifyApproval tells the next approver about the order, a returned order goes back to the credit analyst
+// and a final order goes back to the cmo
+func (u usecase) notifyApproval(req request.ReqSubmitApproval, status entity.TrxStatus, approvalScheme response.RespApprovalScheme) {
+
+	event := entity.NotificationEvent{
+		ProspectID: req.ProspectID,
+		Data: map[string]interface{}{
+			"decision":    status.Decision,
+			"reason":      status.Reason,
+			"decision_by": req.DecisionBy,
+			"from_alias":  req.Alias,
+		},
+	}
+
+	switch {
+	case status.StatusProcess == constant.STATUS_FINAL:
+		event.EventType = constant.NOTIFICATION_FINAL_DECISION
+		event.Alias = constant.CMO_AGENT
+	case req.Decision == constant.DECISION_RETURN:
+		event.EventType = constant.NOTIFICATION_ORDER_RETURNED
+		event.Alias = constant.DB_DECISION_CREDIT_ANALYST
+	case approvalScheme.NextStep != "":
+		event.EventType = constant.NOTIFICATION_APPROVAL_ROUTED
+		event.Alias = approvalScheme.NextStep
+	default:
+		return
+	}
+
+	u.notify(event)
+}
+
+func (u usecase) GetNotificationPreferences(userID string) (data []entity.MappingNotificationPreference, err error) {
+
+	data, err = u.repository.GetNotificationPreferences(userID)
+	if err != nil {
+		if err.Error() != constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Notification Preferences error")
+		}
+		return
+	}
+
+	return
+}
+
+// SaveNotificationPreference mutes or unmutes an event type of the user on a channel, ALL covers every channel
+func (u usecase) SaveNotificationPreference(ctx context.Context, req request.ReqNotificationPreference) (data entity.MappingNotificationPreference, err error) {
+
+	data = entity.MappingNotificationPreference{
+		UserID:    req.UserID,
+		EventType: req.EventType,
+		Channel:   req.Channel,
+		IsMuted:   req.IsMuted,
+		UpdatedBy: req.UpdatedBy,
+		UpdatedAt: time.Now(),
+	}
+
+	if err = u.repository.SaveNotificationPreference(data); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Save Notification Preference error")
+		return
+	}
+
+	return
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClaimOrder gives the user exclusive access to an order in the ca queue until it is released or expired
+func (u usecase) ClaimOrder(ctx context.Context, req request.ReqClaimOrder) (data response.OrderClaim, err error) {
+
+	inQueue, err := u.inCaQueue(req.ProspectID)
+	if err != nil {
+		return
+	}
+
+	if !inQueue {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_ORDER_NOT_CLAIMABLE)
+		return
+	}
+
+	claim := entity.TrxOrderClaim{
+		ProspectID:    req.ProspectID,
+		ClaimedBy:     req.ClaimedBy,
+		ClaimedByName: req.ClaimedByName,
+	}
+
+	history := entity.TrxOrderClaimHistory{
+		ID:         uuid.New().String(),
+		ProspectID: req.ProspectID,
+		Action:     constant.CLAIM_ACTION_CLAIM,
+		HolderBy:   req.ClaimedBy,
+		UserID:     req.ClaimedBy,
+		UserName:   req.ClaimedByName,
+		CreatedAt:  time.Now(),
+	}
+
+	if errClaim := u.repository.ClaimOrder(claim, history); errClaim != nil {
+
+		// a duplicate insert means another user claimed the order at the same time
+		holder, errHolder := u.repository.GetOrderClaim(req.ProspectID)
+		if errHolder == nil && holder.ClaimedBy != req.ClaimedBy {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_ORDER_CLAIMED + holder.ClaimedByName)
+			return
+		}
+
+		err = errors.New(constant.ERROR_UPSTREAM + " - Claim order error")
+		return
+	}
+
+	return u.GetOrderClaim(req.ProspectID)
+}
+
+// ReleaseOrder gives up the claim of the holder
+func (u usecase) ReleaseOrder(ctx context.Context, req request.ReqReleaseOrder) (err error) {
+
+	history := entity.TrxOrderClaimHistory{
+		ID:         uuid.New().String(),
+		ProspectID: req.ProspectID,
+		Action:     constant.CLAIM_ACTION_RELEASE,
+		UserID:     req.ReleasedBy,
+		UserName:   req.ReleasedByName,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = u.repository.ReleaseOrderClaim(req.ProspectID, req.ReleasedBy, history); err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Order tidak sedang di-claim oleh user")
+			return
+		}
+
+		err = errors.New(constant.ERROR_UPSTREAM + " - Release order error")
+		return
+	}
+
+	return
+}
+
+// ForceReleaseOrder lets a supervisor remove the claim of any holder, the role comes from the session of the caller
+func (u usecase) ForceReleaseOrder(ctx context.Context, req request.ReqForceReleaseOrder) (err error) {
+
+	if !canForceRelease(req.RoleAlias) {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_ORDER_FORCE_ROLE)
+		return
+	}
+
+	history := entity.TrxOrderClaimHistory{
+		ID:         uuid.New().String(),
+		ProspectID: req.ProspectID,
+		Action:     constant.CLAIM_ACTION_FORCE,
+		UserID:     req.ReleasedBy,
+		UserName:   req.ReleasedByName,
+		RoleAlias:  req.RoleAlias,
+		Reason:     req.Reason,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = u.repository.ReleaseOrderClaim(req.ProspectID, "", history); err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Order tidak sedang di-claim")
+			return
+		}
+
+		err = errors.New(constant.ERROR_UPSTREAM + " - Force release order error")
+		return
+	}
+
+	return
+}
+
+func (u usecase) GetOrderClaim(prospectID string) (data response.OrderClaim, err error) {
+
+	data.ProspectID = prospectID
+
+	claim, err := u.repository.GetOrderClaim(prospectID)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = nil
+			return
+		}
+
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get order claim error")
+		return
+	}
+
+	data.IsClaimed = true
+	data.ClaimedBy = claim.ClaimedBy
+	data.ClaimedByName = claim.ClaimedByName
+	data.ClaimedAt = claim.ClaimedAt.Format(constant.FORMAT_DATE_TIME)
+	data.ExpiredAt = claimExpiredAt(claim.LastActivityAt)
+
+	return
+}
+
+// validateOrderClaim rejects a ca action from anyone but the holder, an order in the ca queue must be claimed first
+func (u usecase) validateOrderClaim(prospectID, userID string) (err error) {
+
+	claim, err := u.repository.GetOrderClaim(prospectID)
+	if err == nil {
+		if claim.ClaimedBy != userID {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_ORDER_CLAIMED + claim.ClaimedByName)
+		}
+		return
+	}
+
+	if err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get order claim error")
+		return
+	}
+
+	inQueue, err := u.inCaQueue(prospectID)
+	if err != nil {
+		return
+	}
+
+	if inQueue {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_ORDER_NOT_CLAIMED)
+	}
+
+	return
+}
+
+// releaseOrderClaim drops the claim once the order leaves the ca queue, a left over claim simply expires
+func (u usecase) releaseOrderClaim(prospectID, userID, userName, reason string) {
+
+	u.repository.ReleaseOrderClaim(prospectID, userID, entity.TrxOrderClaimHistory{
+		ID:         uuid.New().String(),
+		ProspectID: prospectID,
+		Action:     constant.CLAIM_ACTION_RELEASE,
+		UserID:     userID,
+		UserName:   userName,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	})
+}
+
+func (u usecase) inCaQueue(prospectID string) (inQueue bool, err error) {
+
+	status, err := u.repository.GetTrxStatus(prospectID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get status order error")
+		return
+	}
+
+	inQueue = status.SourceDecision == constant.DB_DECISION_CREDIT_ANALYST && status.Activity == constant.ACTIVITY_UNPROCESS && status.Decision == constant.DB_DECISION_CREDIT_PROCESS
+
+	return
+}
+
+func canForceRelease(role string) bool {
+
+	for _, alias := range strings.Split(constant.ORDER_CLAIM_FORCE_ROLES, ",") {
+		if role != "" && role == alias {
+			return true
+		}
+	}
+
+	return false
+}
+
+func claimExpiredAt(lastActivityAt time.Time) string {
+	return lastActivityAt.Add(constant.ORDER_CLAIM_TIMEOUT * time.Minute).Format(constant.FORMAT_DATE_TIME)
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"time"
+)
+
+// piiAccess describes who is looking at the personal data of the orders and for what purpose, the user and role
+// come from the cms session
+type piiAccess struct {
+	UserID  string
+	Role    string
+	Purpose string
+}
+
+// shapePII tells whether the caller gets the personal data unmasked, unmasked views are written to the access log
+// so every full NIK, mother name, phone and address shown in the cms can be traced back to the user.
+// When the access log can not be written the data is served masked instead
+func (u usecase) shapePII(access piiAccess, prospectIDs []string) (fullAccess bool) {
+
+	if access.UserID == "" || !utils.PIIFullAccess(access.Role) || len(prospectIDs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	logs := make([]entity.TrxPIIAccessLog, 0, len(prospectIDs))
+	for _, prospectID := range prospectIDs {
+		logs = append(logs, entity.TrxPIIAccessLog{
+			ID:         utils.GenerateUUID(),
+			ProspectID: prospectID,
+			UserID:     access.UserID,
+			Role:       access.Role,
+			Purpose:    access.Purpose,
+			CreatedAt:  now,
+		})
+	}
+
+	return u.repository.SavePIIAccessLog(logs) == nil
+}
+
+func maskPersonal(personal *entity.DataPersonal) {
+	personal.IDNumber = utils.MaskIDNumber(personal.IDNumber)
+	personal.SurgateMotherName = ""
+	personal.MobilePhone = utils.MaskPhone(personal.MobilePhone)
+	personal.WhatsAppNumber = utils.MaskPhone(personal.WhatsAppNumber)
+	personal.OtherMobilePhone = utils.MaskPhone(personal.OtherMobilePhone)
+}
+
+func maskSpouse(spouse *entity.CustomerSpouse) {
+	spouse.IDNumber = utils.MaskIDNumber(spouse.IDNumber)
+	spouse.MobilePhone = utils.MaskPhone(spouse.MobilePhone)
+	if phone, ok := spouse.CompanyPhone.(string); ok {
+		spouse.CompanyPhone = utils.MaskPhone(phone)
+	}
+}
+
+func maskEmcon(emcon *entity.CustomerEmcon) {
+	emcon.MobilePhone = utils.MaskPhone(emcon.MobilePhone)
+}
+
+// maskAddress hides the street and rt/rw, the kelurahan, kecamatan and city are kept for the area analysis
+func maskAddress(address *entity.DataAddress) {
+	address.LegalAddress = utils.MaskValue(address.LegalAddress)
+	address.LegalRTRW = utils.MaskValue(address.LegalRTRW)
+	address.ResidenceAddress = utils.MaskValue(address.ResidenceAddress)
+	address.ResidenceRTRW = utils.MaskValue(address.ResidenceRTRW)
+	address.CompanyAddress = utils.MaskValue(address.CompanyAddress)
+	address.CompanyRTRW = utils.MaskValue(address.CompanyRTRW)
+	address.CompanyPhone = utils.MaskPhone(address.CompanyPhone)
+	address.EmergencyAddress = utils.MaskValue(address.EmergencyAddress)
+	address.EmergencyRTRW = utils.MaskValue(address.EmergencyRTRW)
+	address.EmergencyPhone = utils.MaskPhone(address.EmergencyPhone)
+}
+
+func maskAkkk(akkk *entity.Akkk) {
+	akkk.IDNumber = maskInterface(akkk.IDNumber, utils.MaskIDNumber)
+	akkk.SurgateMotherName = nil
+	akkk.MobilePhone = maskInterface(akkk.MobilePhone, utils.MaskPhone)
+	akkk.SpouseIDNumber = maskInterface(akkk.SpouseIDNumber, utils.MaskIDNumber)
+	akkk.SpouseSurgateMotherName = nil
+	akkk.SpouseMobilePhone = maskInterface(akkk.SpouseMobilePhone, utils.MaskPhone)
+	akkk.Address = maskInterface(akkk.Address, utils.MaskValue)
+	akkk.EmconMobilePhone = maskInterface(akkk.EmconMobilePhone, utils.MaskPhone)
+}
+
+func maskInterface(value interface{}, mask func(string) string) interface{} {
+	switch text := value.(type) {
+	case string:
+		return mask(text)
+	case []byte:
+		return mask(string(text))
+	}
+	return value
+}
+
+func (u usecase) GetAkkkView(req request.ReqViewAkkk) (data entity.Akkk, err error) {
+
+	data, err = u.GetAkkk(req.ProspectID)
+	if err != nil {
+		return
+	}
+
+	if !u.shapePII(piiAccess{UserID: req.Session.UserID, Role: req.Session.Role, Purpose: constant.PII_PURPOSE_AKKK}, []string{req.ProspectID}) {
+		maskAkkk(&data)
+	}
+
+	return
+}
@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"sort"
+)
+
+// GetRecalculateHistory lists the returns and recalculations of an order, every recalculation is compared to the original submission
+func (u usecase) GetRecalculateHistory(ctx context.Context, prospectID string) (data response.RecalculateHistory, err error) {
+
+	current, err := u.repository.GetRecalculateCurrent(prospectID)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - prospect_id tidak ditemukan")
+		} else {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Recalculate Current error")
+		}
+		return
+	}
+
+	histories, err := u.repository.GetRecalculateHistory(prospectID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Recalculate History error")
+		return
+	}
+
+	events, err := u.repository.GetRecalculateEvents(prospectID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Recalculate Events error")
+		return
+	}
+
+	limit, err := u.repository.GetRecalculateLimit(prospectID)
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Recalculate Limit error")
+		return
+	}
+
+	data = response.RecalculateHistory{
+		ProspectID: prospectID,
+		Current:    recalculateFigures(current),
+		Original:   recalculateFigures(current),
+		Histories:  []response.RecalculateHistoryItem{},
+	}
+
+	var recalculated int
+	for _, history := range histories {
+		var before, after entity.TrxRecalculate
+
+		// a request sally has not recalculated yet has no figures, it is listed through its event
+		if history.DataAfter == "" {
+			continue
+		}
+
+		if json.Unmarshal([]byte(history.DataBefore), &before) != nil || json.Unmarshal([]byte(history.DataAfter), &after) != nil {
+			continue
+		}
+
+		beforeFigures := recalculateFigures(before)
+		afterFigures := recalculateFigures(after)
+
+		// the first recalculation starts from the figures of the submission
+		if recalculated == 0 {
+			data.Original = beforeFigures
+		}
+		recalculated++
+
+		data.Histories = append(data.Histories, response.RecalculateHistoryItem{
+			Type:      constant.RECALCULATE_HISTORY_RECALCULATE,
+			Actor:     history.CreatedBy,
+			CreatedAt: history.CreatedAt.Format(constant.FORMAT_DATE_TIME),
+			Before:    &beforeFigures,
+			After:     &afterFigures,
+			Limit:     u.recalculateLimit(ctx, prospectID, afterFigures, limit),
+		})
+	}
+
+	for i := range data.Histories {
+		data.Histories[i].Diff = recalculateDiff(data.Original, *data.Histories[i].After)
+	}
+
+	for _, event := range events {
+		item := response.RecalculateHistoryItem{
+			Type:      constant.RECALCULATE_HISTORY_REQUEST,
+			Actor:     event.DecisionBy,
+			Note:      event.Note,
+			CreatedAt: event.CreatedAt.Format(constant.FORMAT_DATE_TIME),
+		}
+		if event.Decision == constant.DB_DECISION_RTN {
+			item.Type = constant.RECALCULATE_HISTORY_RETURN
+		}
+		data.Histories = append(data.Histories, item)
+	}
+
+	sort.SliceStable(data.Histories, func(i, j int) bool {
+		return data.Histories[i].CreatedAt < data.Histories[j].CreatedAt
+	})
+
+	return
+}
+
+func (u usecase) recalculateLimit(ctx context.Context, prospectID string, figures response.RecalculateFigures, limit entity.RecalculateLimit) *response.RecalculateLimit {
+
+	check := response.RecalculateLimit{
+		MaxLTV:  limit.MaxLTV,
+		PassLTV: true,
+	}
+
+	if limit.OTR > 0 {
+		check.LTV = utils.ToFixed((figures.NTF/limit.OTR)*100, 0)
+	}
+
+	if check.MaxLTV > 0 && check.LTV > check.MaxLTV {
+		check.PassLTV = false
+	}
+
+	totalIncome := limit.MonthlyFixedIncome + limit.MonthlyVariableIncome + limit.SpouseIncome
+
+	dsr, err := u.dsr.Calculate(ctx, request.DsrCalculate{
+		ProspectID:                   prospectID,
+		CustomerStatus:               limit.CustomerStatus,
+		CustomerSegment:              limit.CustomerSegment,
+		Cluster:                      limit.Cluster,
+		MonthlyFixedIncome:           limit.MonthlyFixedIncome,
+		MonthlyVariableIncome:        limit.MonthlyVariableIncome,
+		SpouseIncome:                 limit.SpouseIncome,
+		NewInstallment:               figures.InstallmentAmount,
+		InstallmentAmountFMF:         limit.InstallmentAmountFMF,
+		InstallmentAmountSpouseFMF:   limit.InstallmentAmountSpouseFMF,
+		InstallmentAmountOther:       limit.InstallmentAmountOther,
+		InstallmentAmountOtherSpouse: limit.InstallmentAmountOtherSpouse,
+		InstallmentTopup:             limit.InstallmentTopup,
+		InstallmentPBK:               limit.DSRPBK * totalIncome / 100,
+	})
+
+	if err != nil {
+		check.Reason = "DSR tidak dapat dihitung"
+		return &check
+	}
+
+	check.DSRFMF = dsr.DSRFMF
+	check.MaxDSRFMF = dsr.MaxDSRFMF
+	check.TotalDSR = dsr.TotalDSR
+	check.MaxTotalDSR = dsr.MaxTotalDSR
+	check.PassDSR = dsr.Result.Result == constant.DECISION_PASS
+	check.Reason = dsr.Result.Reason
+
+	return &check
+}
+
+func recalculateFigures(data entity.TrxRecalculate) (figures response.RecalculateFigures) {
+
+	figures = response.RecalculateFigures{
+		DPAmount:          data.DPAmount,
+		PercentDP:         data.PercentDP,
+		LoanAmount:        data.LoanAmount,
+		NTF:               data.NTF,
+		NTFAkumulasi:      data.NTFAkumulasi,
+		AF:                data.AF,
+		InstallmentAmount: data.InstallmentAmount,
+		DSRFMF:            data.DSRFMF,
+		TotalDSR:          data.TotalDSR,
+	}
+
+	if data.Tenor != nil {
+		figures.Tenor = *data.Tenor
+	}
+
+	return
+}
+
+// recalculateDiff returns only the fields whose value differs from the original submission
+func recalculateDiff(original, value response.RecalculateFigures) (diff []response.RecalculateFieldDiff) {
+
+	fields := []response.RecalculateFieldDiff{
+		{Field: "tenor", Original: float64(original.Tenor), Value: float64(value.Tenor)},
+		{Field: "dp_amount", Original: original.DPAmount, Value: value.DPAmount},
+		{Field: "percent_dp", Original: original.PercentDP, Value: value.PercentDP},
+		{Field: "loan_amount", Original: original.LoanAmount, Value: value.LoanAmount},
+		{Field: "ntf", Original: original.NTF, Value: value.NTF},
+		{Field: "ntf_akumulasi", Original: original.NTFAkumulasi, Value: value.NTFAkumulasi},
+		{Field: "af", Original: original.AF, Value: value.AF},
+		{Field: "installment_amount", Original: original.InstallmentAmount, Value: value.InstallmentAmount},
+		{Field: "dsr_fmf", Original: original.DSRFMF, Value: value.DSRFMF},
+		{Field: "total_dsr", Original: original.TotalDSR, Value: value.TotalDSR},
+	}
+
+	diff = []response.RecalculateFieldDiff{}
+	for _, field := range fields {
+		if field.Original != field.Value {
+			field.Delta = utils.ToFixed(field.Value-field.Original, 2)
+			diff = append(diff, field)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"encoding/json"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"time"
+)
+
+// slaConfig loads the sla setting, the datatables show no aging when it is not configured
+func (u usecase) slaConfig() (config response.DataSlaConfig, ok bool) {
+
+	var slaConfig response.SlaConfig
+
+	configData, err := u.repository.GetConfig(constant.SLA_CONFIG_GROUP, constant.LOB_KMB_OFF, constant.SLA_CONFIG_KEY)
+	if err != nil || configData.Value == "" {
+		return
+	}
+
+	if err = json.Unmarshal([]byte(configData.Value), &slaConfig); err != nil {
+		return
+	}
+
+	return slaConfig.Data, true
+}
+
+// stageAging returns the aging of an order still waiting in its stage, nil once the order is final or the stage has no sla
+func stageAging(config response.DataSlaConfig, stage, stageStatus string, enteredAt time.Time) *entity.SlaAging {
+
+	if stageStatus != constant.STATUS_ONPROCESS {
+		return nil
+	}
+
+	aging, tracked := utils.SlaAging(stage, enteredAt, time.Now(), config)
+	if !tracked {
+		return nil
+	}
+
+	return &aging
+}
@@ -0,0 +1,609 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"mime/multipart"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// PreviewUploadQuotaDeviasi validates every row of the file without applying it and shows the quota of each branch before and after
+func (u usecase) PreviewUploadQuotaDeviasi(req request.ReqUploadSettingQuotaDeviasi, file multipart.File) (data response.UploadPreview, err error) {
+
+	rows, err := quotaDeviasiRows(file)
+	if err != nil {
+		return
+	}
+
+	updates, rowNumbers, rowErrors := parseQuotaDeviasiRows(rows[1:], req.UpdatedByName)
+
+	changes := []response.QuotaDeviasiPreviewChange{}
+
+	if len(updates) > 0 {
+		var branchIDs []string
+		for _, update := range updates {
+			branchIDs = append(branchIDs, update.BranchID)
+		}
+
+		current, errCurrent := u.repository.GetQuotaDeviasiBranches(branchIDs)
+		if errCurrent != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get kuota deviasi branch error")
+			return
+		}
+
+		currentMap := make(map[string]entity.MappingBranchDeviasi)
+		for _, branch := range current {
+			currentMap[branch.BranchID] = branch
+		}
+
+		for i, update := range updates {
+			rowNumber := rowNumbers[i]
+
+			before, exists := currentMap[update.BranchID]
+			if !exists {
+				rowErrors = append(rowErrors, uploadRowError(rowNumber, 1, "branch_id", fmt.Sprintf("branch_id %s tidak terdaftar pada baris ke %d, kolom 1", update.BranchID, rowNumber)))
+				continue
+			}
+
+			change := response.QuotaDeviasiPreviewChange{
+				BranchID: update.BranchID,
+				Before:   quotaDeviasiBranchData(before),
+				After: entity.DataQuotaDeviasiBranch{
+					QuotaAmount:    update.QuotaAmount,
+					QuotaAccount:   update.QuotaAccount,
+					BookingAmount:  before.BookingAmount,
+					BookingAccount: before.BookingAccount,
+					BalanceAmount:  update.QuotaAmount - before.BookingAmount,
+					BalanceAccount: update.QuotaAccount - before.BookingAccount,
+					IsActive:       update.IsActive,
+					UpdatedBy:      req.UpdatedByName,
+				},
+			}
+
+			// the booking already made in the period can not be taken back by a lower quota
+			if change.After.BalanceAmount < 0 {
+				change.Conflict = fmt.Sprintf("booking_amount %.0f melebihi quota_amount %.0f", before.BookingAmount, update.QuotaAmount)
+				rowErrors = append(rowErrors, uploadRowError(rowNumber, 3, "quota_amount", fmt.Sprintf("quota_amount lebih kecil dari booking_amount pada baris ke %d, kolom 3", rowNumber)))
+			}
+
+			if change.After.BalanceAccount < 0 {
+				change.Conflict = strings.TrimPrefix(change.Conflict+fmt.Sprintf(", booking_account %d melebihi quota_account %d", before.BookingAccount, update.QuotaAccount), ", ")
+				rowErrors = append(rowErrors, uploadRowError(rowNumber, 4, "quota_account", fmt.Sprintf("quota_account lebih kecil dari booking_account pada baris ke %d, kolom 4", rowNumber)))
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	data, err = u.saveUploadPreview(constant.UPLOAD_TYPE_QUOTA_DEVIASI, len(rows)-1, updates, rowErrors, changes, req.UpdatedByName)
+
+	return
+}
+
+// ConfirmUploadQuotaDeviasi applies a valid preview once, the balance is checked again against the booking at the time of applying
+func (u usecase) ConfirmUploadQuotaDeviasi(ctx context.Context, req request.ReqConfirmUploadQuotaDeviasi) (data response.UploadQuotaDeviasiBranchResponse, err error) {
+
+	var updates []entity.MappingBranchDeviasi
+
+	if err = u.claimUploadPreview(req.PreviewID, constant.UPLOAD_TYPE_QUOTA_DEVIASI, req.UpdatedByName, &updates); err != nil {
+		return
+	}
+
+	for i := range updates {
+		updates[i].UpdatedBy = req.UpdatedByName
+		updates[i].UpdatedAt = time.Now()
+	}
+
+	data, err = u.applyQuotaDeviasi(updates)
+	if err != nil {
+		u.repository.UpdateUploadPreviewStatus(req.PreviewID, constant.UPLOAD_PREVIEW_STATUS_APPLIED, constant.UPLOAD_PREVIEW_STATUS_PREVIEW, nil)
+		return
+	}
+
+	return
+}
+
+// PreviewMappingCluster validates every row of the file without applying it and lists the mappings that are added, changed or removed
+func (u usecase) PreviewMappingCluster(req request.ReqUploadMappingCluster, file multipart.File) (data response.UploadPreview, err error) {
+
+	rows, err := mappingClusterRows(file)
+	if err != nil {
+		return
+	}
+
+	cluster, rowErrors := parseMappingClusterRows(rows[1:])
+
+	existingCluster, err := u.repository.GetMappingCluster()
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get existing mapping cluster branch error")
+		return
+	}
+	err = nil
+
+	changes := []response.MappingClusterPreviewChange{}
+
+	// the upload replaces the whole mapping, anything not in the file is removed
+	if len(cluster) > 0 {
+		changes = diffMappingCluster(existingCluster, cluster)
+	} else if len(rowErrors) == 0 {
+		rowErrors = append(rowErrors, uploadRowError(0, 0, "", "Mapping cluster branch dalam file excel kosong"))
+	}
+
+	data, err = u.saveUploadPreview(constant.UPLOAD_TYPE_MAPPING_CLUSTER, len(rows)-1, cluster, rowErrors, changes, req.UserID)
+
+	return
+}
+
+// ConfirmMappingCluster applies a valid preview once
+func (u usecase) ConfirmMappingCluster(ctx context.Context, req request.ReqConfirmUploadMappingCluster) (err error) {
+
+	var cluster []entity.MasterMappingCluster
+
+	if err = u.claimUploadPreview(req.PreviewID, constant.UPLOAD_TYPE_MAPPING_CLUSTER, req.UserID, &cluster); err != nil {
+		return
+	}
+
+	if err = u.applyMappingCluster(cluster, req.UserID); err != nil {
+		u.repository.UpdateUploadPreviewStatus(req.PreviewID, constant.UPLOAD_PREVIEW_STATUS_APPLIED, constant.UPLOAD_PREVIEW_STATUS_PREVIEW, nil)
+		return
+	}
+
+	return
+}
+
+func (u usecase) saveUploadPreview(uploadType string, totalRows int, payload interface{}, rowErrors []response.UploadRowError, changes interface{}, createdBy string) (data response.UploadPreview, err error) {
+
+	if rowErrors == nil {
+		rowErrors = []response.UploadRowError{}
+	}
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error converting to JSON upload preview")
+		return
+	}
+
+	preview := entity.TrxUploadPreview{
+		ID:         utils.GenerateUUID(),
+		UploadType: uploadType,
+		IsValid:    len(rowErrors) == 0,
+		TotalRows:  totalRows,
+		ErrorCount: len(rowErrors),
+		Payload:    string(payloadJSON),
+		Status:     constant.UPLOAD_PREVIEW_STATUS_PREVIEW,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+
+	if err = u.repository.SaveUploadPreview(preview); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Save upload preview error")
+		return
+	}
+
+	data = response.UploadPreview{
+		PreviewID:  preview.ID,
+		UploadType: uploadType,
+		IsValid:    preview.IsValid,
+		TotalRows:  totalRows,
+		ErrorCount: preview.ErrorCount,
+		Errors:     rowErrors,
+		Changes:    changes,
+	}
+
+	return
+}
+
+// claimUploadPreview marks a valid and recent preview as applied so it can not be confirmed twice and returns its rows
+func (u usecase) claimUploadPreview(previewID, uploadType, appliedBy string, payload interface{}) (err error) {
+
+	preview, err := u.repository.GetUploadPreview(previewID)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Preview upload tidak ditemukan")
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get upload preview error")
+		return
+	}
+
+	if preview.UploadType != uploadType {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Preview upload tidak ditemukan")
+		return
+	}
+
+	if !preview.IsValid {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Preview upload masih memiliki error, perbaiki file dan upload ulang")
+		return
+	}
+
+	if preview.Status != constant.UPLOAD_PREVIEW_STATUS_PREVIEW {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Preview upload sudah diterapkan")
+		return
+	}
+
+	if time.Since(preview.CreatedAt) > constant.UPLOAD_PREVIEW_EXPIRY*time.Minute {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Preview upload sudah kedaluwarsa, silakan upload ulang")
+		return
+	}
+
+	if err = json.Unmarshal([]byte(preview.Payload), payload); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error unmarshal upload preview")
+		return
+	}
+
+	if err = u.repository.UpdateUploadPreviewStatus(previewID, constant.UPLOAD_PREVIEW_STATUS_PREVIEW, constant.UPLOAD_PREVIEW_STATUS_APPLIED, &appliedBy); err != nil {
+		if err.Error() == constant.ERROR_ROWS_AFFECTED {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Preview upload sudah diterapkan")
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Update upload preview error")
+		return
+	}
+
+	return
+}
+
+func (u usecase) applyQuotaDeviasi(updates []entity.MappingBranchDeviasi) (data response.UploadQuotaDeviasiBranchResponse, err error) {
+
+	dataBeforeUpdate, dataAfterUpdate, err := u.repository.BatchUpdateQuotaDeviasi(updates)
+	if err != nil {
+		return
+	}
+
+	data = response.UploadQuotaDeviasiBranchResponse{
+		Status:           constant.RESULT_OK,
+		Message:          constant.UPDATE_DEVIASI_SUCCESS,
+		DataBeforeUpdate: dataBeforeUpdate,
+		DataAfterUpdate:  dataAfterUpdate,
+	}
+
+	return
+}
+
+func (u usecase) applyMappingCluster(cluster []entity.MasterMappingCluster, userID string) (err error) {
+
+	existingCluster, err := u.repository.GetMappingCluster()
+	if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get existing mapping cluster branch error")
+		return err
+	}
+
+	jsonDataBefore, err := json.Marshal(existingCluster)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error converting to JSON mapping cluster before")
+		return err
+	}
+
+	jsonDataAfter, err := json.Marshal(cluster)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error converting to JSON mapping cluster after")
+		return err
+	}
+
+	history := entity.HistoryConfigChanges{
+		ID:         utils.GenerateUUID(),
+		ConfigID:   constant.MAPPING_CLUSTER_CONFIG_ID,
+		ObjectName: constant.MAPPING_CLUSTER_CONFIG_ID,
+		Action:     "UPDATE",
+		DataBefore: string(jsonDataBefore),
+		DataAfter:  string(jsonDataAfter),
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+	}
+
+	err = u.repository.BatchUpdateMappingCluster(cluster, history)
+	if err != nil {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + err.Error())
+		return err
+	}
+
+	return
+}
+
+// quotaDeviasiRows reads the quota deviasi sheet, only a wrong file or header stops the validation
+func quotaDeviasiRows(file multipart.File) (rows [][]string, err error) {
+
+	xlsx, err := excelize.OpenReader(file)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("%s - gagal membuka file excel", constant.ERROR_BAD_REQUEST))
+		return
+	}
+	defer xlsx.Close()
+
+	sheetName := "Quota Deviasi Branch"
+	rows, err = xlsx.GetRows(sheetName)
+	if err != nil {
+		err = errors.New(fmt.Sprintf("%s - gagal mendapatkan baris dari sheet excel", constant.ERROR_BAD_REQUEST))
+		return
+	}
+
+	if len(rows) < 2 {
+		err = errors.New(fmt.Sprintf("%s - file excel membutuhkan setidaknya 1 baris data", constant.ERROR_BAD_REQUEST))
+		return
+	}
+
+	headers := rows[0]
+	expectedHeaders := []string{"branch_id", "branch_name", "quota_amount", "quota_account", "is_active"}
+	if len(headers) > len(expectedHeaders) {
+		err = errors.New(fmt.Sprintf("%s - format header tidak valid", constant.ERROR_BAD_REQUEST))
+		return
+	}
+
+	for i, header := range headers {
+		if strings.ToLower(header) != expectedHeaders[i] {
+			err = errors.New(fmt.Sprintf("%s - format header tidak valid", constant.ERROR_BAD_REQUEST))
+			return
+		}
+	}
+
+	return
+}
+
+// parseQuotaDeviasiRows validates every column of every row, rowNumbers holds the excel row of each update
+func parseQuotaDeviasiRows(rows [][]string, updatedBy string) (updates []entity.MappingBranchDeviasi, rowNumbers []int, rowErrors []response.UploadRowError) {
+
+	branchRows := make(map[string]int)
+
+	for rowIndex, row := range rows {
+		rowNumber := rowIndex + 2
+
+		if len(row) < 5 {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 0, "", fmt.Sprintf("setiap baris harus memiliki 5 kolom, kesalahan pada baris ke %d", rowNumber)))
+			continue
+		}
+
+		valid := true
+
+		branchID := strings.TrimSpace(row[0])
+		if branchID == "" {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 1, "branch_id", fmt.Sprintf("branch_id tidak boleh kosong pada baris ke %d, kolom 1", rowNumber)))
+			valid = false
+		} else if firstRow, exists := branchRows[branchID]; exists {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 1, "branch_id", fmt.Sprintf("branch_id %s duplikat pada baris ke %d dan %d, kolom 1", branchID, firstRow, rowNumber)))
+			valid = false
+		} else {
+			branchRows[branchID] = rowNumber
+		}
+
+		quotaAmount, errParse := strconv.ParseFloat(row[2], 64)
+		if errParse != nil {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 3, "quota_amount", fmt.Sprintf("nilai quota_amount tidak valid pada baris ke %d, kolom 3", rowNumber)))
+			valid = false
+		} else if quotaAmount < 0 {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 3, "quota_amount", fmt.Sprintf("quota_amount tidak diperbolehkan negatif pada baris ke %d, kolom 3", rowNumber)))
+			valid = false
+		} else if len(fmt.Sprintf("%.0f", quotaAmount)) > 11 {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 3, "quota_amount", fmt.Sprintf("quota_amount lebih dari 11 digit pada baris ke %d, kolom 3", rowNumber)))
+			valid = false
+		}
+
+		quotaAccount, errParse := strconv.Atoi(row[3])
+		if errParse != nil {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 4, "quota_account", fmt.Sprintf("nilai quota_account tidak valid pada baris ke %d, kolom 4", rowNumber)))
+			valid = false
+		} else if quotaAccount < 0 {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 4, "quota_account", fmt.Sprintf("quota_account tidak diperbolehkan negatif pada baris ke %d, kolom 4", rowNumber)))
+			valid = false
+		} else if len(fmt.Sprintf("%d", quotaAccount)) > 3 {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 4, "quota_account", fmt.Sprintf("quota_account lebih dari 3 digit pada baris ke %d, kolom 4", rowNumber)))
+			valid = false
+		}
+
+		isActive := false
+		if strings.ToLower(row[4]) == "true" {
+			isActive = true
+		} else if strings.ToLower(row[4]) != "false" {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 5, "is_active", fmt.Sprintf("nilai is_active tidak valid pada baris ke %d, kolom 5", rowNumber)))
+			valid = false
+		}
+
+		if !valid {
+			continue
+		}
+
+		updates = append(updates, entity.MappingBranchDeviasi{
+			BranchID:     branchID,
+			QuotaAmount:  quotaAmount,
+			QuotaAccount: quotaAccount,
+			IsActive:     isActive,
+			UpdatedBy:    updatedBy,
+			UpdatedAt:    time.Now(),
+		})
+		rowNumbers = append(rowNumbers, rowNumber)
+	}
+
+	return
+}
+
+// mappingClusterRows reads the first sheet of the mapping cluster file, only a wrong file or header stops the validation
+func mappingClusterRows(file multipart.File) (rows [][]string, err error) {
+
+	f, err := excelize.OpenReader(file)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Open file excel mapping cluster error")
+		return
+	}
+	defer f.Close()
+
+	sheetName := f.GetSheetName(0)
+	rows, err = f.GetRows(sheetName)
+	if err != nil {
+		return
+	}
+
+	if len(rows) == 0 || len(rows[0]) == 0 || rows[0][0] != "branch_id" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom pertama harus berjudul 'branch_id'")
+	} else if uploadCell(rows[0], 2) != "customer_status" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom ketiga harus berjudul 'customer_status'")
+	} else if uploadCell(rows[0], 3) != "bpkb_name_type" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom keempat harus berjudul 'bpkb_name_type'")
+	} else if len(rows[0]) < 5 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom kelima harus berjudul 'cluster'")
+	}
+
+	return
+}
+
+// parseMappingClusterRows validates every column of every row of the mapping cluster file
+func parseMappingClusterRows(rows [][]string) (cluster []entity.MasterMappingCluster, rowErrors []response.UploadRowError) {
+
+	var clusterRegex = regexp.MustCompile(`^Cluster [A-Z]$`)
+	var uniqueMappingData = make(map[string]int)
+
+	for rowIndex, row := range rows {
+		rowNumber := rowIndex + 2
+		prefix := "row " + strconv.Itoa(rowNumber) + ", "
+		valid := true
+
+		branchID := strings.TrimSpace(uploadCell(row, 0))
+		if branchID == "" {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 1, "branch_id", prefix+"nilai branch_id tidak boleh kosong"))
+			valid = false
+		} else if branchID == "0" {
+			branchID = constant.BRANCH_ID_PRIME_PRIORITY
+		}
+
+		customerStatus := strings.ToUpper(strings.TrimSpace(uploadCell(row, 2)))
+		if customerStatus != constant.STATUS_KONSUMEN_NEW && customerStatus != "AO/RO" {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 3, "customer_status", prefix+"nilai customer_status harus "+constant.STATUS_KONSUMEN_NEW+" atau AO/RO"))
+			valid = false
+		}
+
+		bpkbName, errParse := strconv.Atoi(uploadCell(row, 3))
+		if errParse != nil || (bpkbName != 0 && bpkbName != 1) {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 4, "bpkb_name_type", prefix+"nilai bpkb_name_type harus 0 atau 1"))
+			valid = false
+		}
+
+		clusterStr := strings.TrimSpace(uploadCell(row, 4))
+		if len(row) < 5 {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 5, "cluster", prefix+"nilai cluster tidak boleh kosong"))
+			valid = false
+		} else {
+			if strings.EqualFold(clusterStr, constant.CLUSTER_PRIME_PRIORITY) {
+				clusterStr = strings.ToUpper(clusterStr)
+			} else {
+				clusterStr = strings.Title(strings.ToLower(clusterStr))
+			}
+
+			if clusterStr != constant.CLUSTER_PRIME_PRIORITY && !clusterRegex.MatchString(clusterStr) {
+				rowErrors = append(rowErrors, uploadRowError(rowNumber, 5, "cluster", prefix+"nilai cluster tidak sesuai ketentuan"))
+				valid = false
+			}
+		}
+
+		if !valid {
+			continue
+		}
+
+		uniqueKey := fmt.Sprintf("%s-%s-%d", branchID, customerStatus, bpkbName)
+
+		if firstRow, exists := uniqueMappingData[uniqueKey]; exists {
+			rowErrors = append(rowErrors, uploadRowError(rowNumber, 0, "", "row "+strconv.Itoa(rowNumber)+" dan row "+strconv.Itoa(firstRow)+", entri duplikat untuk nilai branch_id, customer_status, dan bpkb_name_type"))
+			continue
+		}
+
+		uniqueMappingData[uniqueKey] = rowNumber
+
+		cluster = append(cluster, entity.MasterMappingCluster{
+			BranchID:       branchID,
+			CustomerStatus: customerStatus,
+			BpkbNameType:   bpkbName,
+			Cluster:        clusterStr,
+		})
+	}
+
+	return
+}
+
+// diffMappingCluster lists the mappings added, changed or removed going from before to after
+func diffMappingCluster(before, after []entity.MasterMappingCluster) []response.MappingClusterPreviewChange {
+
+	changes := []response.MappingClusterPreviewChange{}
+
+	beforeMap := make(map[string]entity.MasterMappingCluster)
+	for _, existing := range before {
+		beforeMap[fmt.Sprintf("%s-%s-%d", existing.BranchID, existing.CustomerStatus, existing.BpkbNameType)] = existing
+	}
+
+	for _, mapping := range after {
+		key := fmt.Sprintf("%s-%s-%d", mapping.BranchID, mapping.CustomerStatus, mapping.BpkbNameType)
+
+		change := response.MappingClusterPreviewChange{
+			BranchID:       mapping.BranchID,
+			CustomerStatus: mapping.CustomerStatus,
+			BpkbNameType:   mapping.BpkbNameType,
+			ClusterAfter:   mapping.Cluster,
+			Action:         constant.UPLOAD_CHANGE_ADD,
+		}
+
+		if existing, exists := beforeMap[key]; exists {
+			delete(beforeMap, key)
+
+			if existing.Cluster == mapping.Cluster {
+				continue
+			}
+
+			change.ClusterBefore = existing.Cluster
+			change.Action = constant.UPLOAD_CHANGE_UPDATE
+		}
+
+		changes = append(changes, change)
+	}
+
+	for _, existing := range before {
+		if _, removed := beforeMap[fmt.Sprintf("%s-%s-%d", existing.BranchID, existing.CustomerStatus, existing.BpkbNameType)]; removed {
+			changes = append(changes, response.MappingClusterPreviewChange{
+				BranchID:       existing.BranchID,
+				CustomerStatus: existing.CustomerStatus,
+				BpkbNameType:   existing.BpkbNameType,
+				ClusterBefore:  existing.Cluster,
+				Action:         constant.UPLOAD_CHANGE_DELETE,
+			})
+		}
+	}
+
+	return changes
+}
+
+func quotaDeviasiBranchData(branch entity.MappingBranchDeviasi) entity.DataQuotaDeviasiBranch {
+	return entity.DataQuotaDeviasiBranch{
+		QuotaAmount:    branch.QuotaAmount,
+		QuotaAccount:   branch.QuotaAccount,
+		BookingAmount:  branch.BookingAmount,
+		BookingAccount: branch.BookingAccount,
+		BalanceAmount:  branch.BalanceAmount,
+		BalanceAccount: branch.BalanceAccount,
+		IsActive:       branch.IsActive,
+		UpdatedAt:      branch.UpdatedAt,
+		UpdatedBy:      branch.UpdatedBy,
+	}
+}
+
+func uploadRowError(row, column int, field, message string) response.UploadRowError {
+	return response.UploadRowError{
+		Row:     row,
+		Column:  column,
+		Field:   field,
+		Message: message,
+	}
+}
+
+func uploadCell(row []string, index int) string {
+	if index < len(row) {
+		return row[index]
+	}
+	return ""
+}
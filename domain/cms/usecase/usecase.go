@@ -7,16 +7,18 @@ import (
 	"fmt"
 	cache "los-kmb-api/domain/cache/interfaces"
 	"los-kmb-api/domain/cms/interfaces"
+	dsr "los-kmb-api/domain/dsr/interfaces"
+	notification "los-kmb-api/domain/notification/interfaces"
 	"los-kmb-api/models/entity"
 	"los-kmb-api/models/request"
 	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common/platformevent"
 	"los-kmb-api/shared/constant"
 	"los-kmb-api/shared/httpclient"
 	"los-kmb-api/shared/utils"
 	"mime/multipart"
 	"net/url"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -29,17 +31,23 @@ import (
 
 type (
 	usecase struct {
-		repository interfaces.Repository
-		httpclient httpclient.HttpClient
-		cache      cache.Repository
+		repository   interfaces.Repository
+		httpclient   httpclient.HttpClient
+		cache        cache.Repository
+		notification notification.Usecase
+		dsr          dsr.Usecase
+		producer     platformevent.PlatformEventInterface
 	}
 )
 
-func NewUsecase(repository interfaces.Repository, httpclient httpclient.HttpClient, cache cache.Repository) interfaces.Usecase {
+func NewUsecase(repository interfaces.Repository, httpclient httpclient.HttpClient, cache cache.Repository, notification notification.Usecase, dsr dsr.Usecase, producer platformevent.PlatformEventInterface) interfaces.Usecase {
 	return &usecase{
-		repository: repository,
-		httpclient: httpclient,
-		cache:      cache,
+		repository:   repository,
+		httpclient:   httpclient,
+		cache:        cache,
+		notification: notification,
+		dsr:          dsr,
+		producer:     producer,
 	}
 }
 
@@ -55,7 +63,7 @@ func (u usecase) GetAkkk(prospectID string) (data entity.Akkk, err error) {
 		return
 	}
 
-	industryType, _ := u.cache.Get(data.IndustryTypeID.(string))
+	industryType, _ := u.cache.GetWithExpiration(data.IndustryTypeID.(string))
 
 	var industry []entity.SpIndustryTypeMaster
 	if industryType == nil {
@@ -67,7 +75,7 @@ func (u usecase) GetAkkk(prospectID string) (data entity.Akkk, err error) {
 		}
 
 		for _, description := range industry {
-			u.cache.Set(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description))
+			u.cache.SetWithExpiration(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description), time.Duration(constant.MASTER_DATA_CACHE_TTL)*time.Minute)
 
 			if strings.ToUpper(data.IndustryTypeID.(string)) == strings.ToUpper(strings.ReplaceAll(description.IndustryTypeID, " ", "")) {
 				industryType = []byte(description.Description)
@@ -415,7 +423,7 @@ func (u usecase) GetInquiryPrescreening(ctx context.Context, req request.ReqInqu
 
 	for _, inq := range result {
 
-		industryType, _ := u.cache.Get(inq.IndustryTypeID)
+		industryType, _ := u.cache.GetWithExpiration(inq.IndustryTypeID)
 
 		if industryType == nil {
 			industry, err = u.repository.GetSpIndustryTypeMaster()
@@ -425,7 +433,7 @@ func (u usecase) GetInquiryPrescreening(ctx context.Context, req request.ReqInqu
 			}
 
 			for _, description := range industry {
-				u.cache.Set(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description))
+				u.cache.SetWithExpiration(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description), time.Duration(constant.MASTER_DATA_CACHE_TTL)*time.Minute)
 
 				if strings.ToUpper(inq.IndustryTypeID) == strings.ToUpper(strings.ReplaceAll(description.IndustryTypeID, " ", "")) {
 					industryType = []byte(description.Description)
@@ -514,6 +522,8 @@ func (u usecase) GetInquiryPrescreening(ctx context.Context, req request.ReqInqu
 				DecisionBy:        inq.DecisionBy,
 				DecisionName:      inq.DecisionName,
 				DecisionAt:        inq.DecisionAt,
+				AutoDecision:      inq.AutoDecision,
+				AutoRuleCode:      inq.AutoRuleCode,
 			},
 			General: entity.DataGeneral{
 				ProspectID:     inq.ProspectID,
@@ -786,6 +796,8 @@ func (u usecase) GetDatatableCa(ctx context.Context, req request.ReqInquiryCa, p
 		return []entity.RespDatatableCA{}, 0, err
 	}
 
+	slaConfig, slaOk := u.slaConfig()
+
 	prospectIDs := make([]string, len(result))
 	for i, inq := range result {
 		prospectIDs[i] = inq.ProspectID
@@ -841,6 +853,18 @@ func (u usecase) GetDatatableCa(ctx context.Context, req request.ReqInquiryCa, p
 			ShowAction:     action,
 		}
 
+		if slaOk {
+			row.Aging = stageAging(slaConfig, inq.Stage, inq.StageStatus, inq.StageEnteredAt)
+		}
+
+		if inq.ClaimedBy != "" {
+			row.Claim = &entity.OrderClaim{
+				ClaimedBy:     inq.ClaimedBy,
+				ClaimedByName: inq.ClaimedByName,
+				ExpiredAt:     claimExpiredAt(inq.ClaimActivityAt),
+			}
+		}
+
 		data = append(data, row)
 	}
 
@@ -867,7 +891,7 @@ func (u usecase) GetInquiryCa(ctx context.Context, req request.ReqInquiryCa, pag
 
 	for _, inq := range result {
 
-		industryType, _ := u.cache.Get(inq.IndustryTypeID)
+		industryType, _ := u.cache.GetWithExpiration(inq.IndustryTypeID)
 
 		if industryType == nil {
 			industry, err = u.repository.GetSpIndustryTypeMaster()
@@ -877,7 +901,7 @@ func (u usecase) GetInquiryCa(ctx context.Context, req request.ReqInquiryCa, pag
 			}
 
 			for _, description := range industry {
-				u.cache.Set(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description))
+				u.cache.SetWithExpiration(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description), time.Duration(constant.MASTER_DATA_CACHE_TTL)*time.Minute)
 
 				if strings.ToUpper(inq.IndustryTypeID) == strings.ToUpper(strings.ReplaceAll(description.IndustryTypeID, " ", "")) {
 					industryType = []byte(description.Description)
@@ -1184,6 +1208,20 @@ func (u usecase) GetInquiryCa(ctx context.Context, req request.ReqInquiryCa, pag
 
 	}
 
+	prospectIDs := make([]string, 0, len(data))
+	for _, row := range data {
+		prospectIDs = append(prospectIDs, row.General.ProspectID)
+	}
+
+	if !u.shapePII(piiAccess{UserID: req.Session.UserID, Role: req.Session.Role, Purpose: constant.PII_PURPOSE_INQUIRY_CA}, prospectIDs) {
+		for i := range data {
+			maskPersonal(&data[i].Personal)
+			maskSpouse(&data[i].Spouse)
+			maskEmcon(&data[i].Emcon)
+			maskAddress(&data[i].Address)
+		}
+	}
+
 	return
 }
 
@@ -1195,6 +1233,10 @@ func (u usecase) SaveAsDraft(ctx context.Context, req request.ReqSaveAsDraft) (d
 		EDD      request.EDD
 	)
 
+	if err = u.validateOrderClaim(req.ProspectID, req.CreatedBy); err != nil {
+		return
+	}
+
 	switch req.Decision {
 	case constant.DECISION_REJECT:
 		decision = constant.DB_DECISION_REJECT
@@ -1236,6 +1278,8 @@ func (u usecase) SaveAsDraft(ctx context.Context, req request.ReqSaveAsDraft) (d
 		return
 	}
 
+	u.repository.TouchOrderClaim(req.ProspectID, req.CreatedBy)
+
 	return
 }
 
@@ -1253,6 +1297,10 @@ func (u usecase) SubmitDecision(ctx context.Context, req request.ReqSubmitDecisi
 		trxEdd             entity.TrxEDD
 	)
 
+	if err = u.validateOrderClaim(req.ProspectID, req.CreatedBy); err != nil {
+		return
+	}
+
 	status, err := u.repository.GetTrxStatus(req.ProspectID)
 
 	if err != nil {
@@ -1345,16 +1393,35 @@ func (u usecase) SubmitDecision(ctx context.Context, req request.ReqSubmitDecisi
 			json.Unmarshal(byteEdd, &trxEdd)
 		}
 
+		processed := true
+
 		err = u.repository.ProcessTransaction(trxCaDecision, trxHistoryApproval, trxStatus, trxDetail, false, trxEdd)
 		if err != nil {
 			if err.Error() == constant.ERROR_ROWS_AFFECTED {
 				err = nil
+				processed = false
 			} else {
 				err = errors.New(constant.ERROR_UPSTREAM + " - Submit Decision error " + err.Error())
 				return
 			}
 		}
 
+		// no rows affected means the order was already decided, the claim and the notification belong to that submit
+		if processed {
+			u.releaseOrderClaim(req.ProspectID, req.CreatedBy, req.DecisionBy, constant.CLAIM_REASON_DECIDED)
+
+			u.notify(entity.NotificationEvent{
+				EventType:  constant.NOTIFICATION_APPROVAL_ROUTED,
+				ProspectID: req.ProspectID,
+				Alias:      constant.DB_DECISION_BRANCH_MANAGER,
+				Data: map[string]interface{}{
+					"decision":    req.Decision,
+					"decision_by": req.DecisionBy,
+					"note":        req.Note,
+				},
+			})
+		}
+
 		data = response.CAResponse{
 			ProspectID: req.ProspectID,
 			Decision:   req.Decision,
@@ -1387,7 +1454,7 @@ func (u usecase) GetSearchInquiry(ctx context.Context, req request.ReqSearchInqu
 
 	for _, inq := range result {
 
-		industryType, _ := u.cache.Get(inq.IndustryTypeID)
+		industryType, _ := u.cache.GetWithExpiration(inq.IndustryTypeID)
 
 		if industryType == nil {
 			industry, err = u.repository.GetSpIndustryTypeMaster()
@@ -1397,7 +1464,7 @@ func (u usecase) GetSearchInquiry(ctx context.Context, req request.ReqSearchInqu
 			}
 
 			for _, description := range industry {
-				u.cache.Set(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description))
+				u.cache.SetWithExpiration(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description), time.Duration(constant.MASTER_DATA_CACHE_TTL)*time.Minute)
 
 				if strings.ToUpper(inq.IndustryTypeID) == strings.ToUpper(strings.ReplaceAll(description.IndustryTypeID, " ", "")) {
 					industryType = []byte(description.Description)
@@ -1639,6 +1706,20 @@ func (u usecase) GetSearchInquiry(ctx context.Context, req request.ReqSearchInqu
 
 	}
 
+	prospectIDs := make([]string, 0, len(data))
+	for _, row := range data {
+		prospectIDs = append(prospectIDs, row.General.ProspectID)
+	}
+
+	if !u.shapePII(piiAccess{UserID: req.Session.UserID, Role: req.Session.Role, Purpose: constant.PII_PURPOSE_SEARCH}, prospectIDs) {
+		for i := range data {
+			maskPersonal(&data[i].Personal)
+			maskSpouse(&data[i].Spouse)
+			maskEmcon(&data[i].Emcon)
+			maskAddress(&data[i].Address)
+		}
+	}
+
 	return
 }
 
@@ -1652,6 +1733,10 @@ func (u usecase) CancelOrder(ctx context.Context, req request.ReqCancelOrder) (d
 		trxedd             entity.TrxEDD
 	)
 
+	if err = u.validateOrderClaim(req.ProspectID, req.CreatedBy); err != nil {
+		return
+	}
+
 	status, err := u.repository.GetTrxStatus(req.ProspectID)
 
 	if err != nil {
@@ -1712,6 +1797,8 @@ func (u usecase) CancelOrder(ctx context.Context, req request.ReqCancelOrder) (d
 			return
 		}
 
+		u.releaseOrderClaim(req.ProspectID, req.CreatedBy, req.DecisionBy, constant.CLAIM_REASON_CANCELED)
+
 		data = response.CancelResponse{
 			ProspectID: req.ProspectID,
 			Reason:     req.CancelReason,
@@ -1760,6 +1847,15 @@ func (u usecase) ReturnOrder(ctx context.Context, req request.ReqReturnOrder) (d
 		return
 	}
 
+	u.notify(entity.NotificationEvent{
+		EventType:  constant.NOTIFICATION_ORDER_RETURNED,
+		ProspectID: req.ProspectID,
+		Alias:      constant.PRESCREENING,
+		Data: map[string]interface{}{
+			"reason": constant.REASON_RETURN_ORDER,
+		},
+	})
+
 	data = response.ReturnResponse{
 		ProspectID: req.ProspectID,
 		Status:     constant.RETURN_STATUS_SUCCESS,
@@ -1872,6 +1968,8 @@ func (u usecase) GetDatatableApproval(ctx context.Context, req request.ReqInquir
 		return []entity.RespDatatableApproval{}, 0, err
 	}
 
+	slaConfig, slaOk := u.slaConfig()
+
 	prospectIDs := make([]string, len(result))
 	for i, inq := range result {
 		prospectIDs[i] = inq.ProspectID
@@ -1910,6 +2008,10 @@ func (u usecase) GetDatatableApproval(ctx context.Context, req request.ReqInquir
 			},
 		}
 
+		if slaOk {
+			row.Aging = stageAging(slaConfig, inq.Stage, inq.StageStatus, inq.StageEnteredAt)
+		}
+
 		data = append(data, row)
 	}
 
@@ -1935,7 +2037,7 @@ func (u usecase) GetInquiryApproval(ctx context.Context, req request.ReqInquiryA
 
 	for _, inq := range result {
 
-		industryType, _ := u.cache.Get(inq.IndustryTypeID)
+		industryType, _ := u.cache.GetWithExpiration(inq.IndustryTypeID)
 
 		if industryType == nil {
 			industry, err = u.repository.GetSpIndustryTypeMaster()
@@ -1945,7 +2047,7 @@ func (u usecase) GetInquiryApproval(ctx context.Context, req request.ReqInquiryA
 			}
 
 			for _, description := range industry {
-				u.cache.Set(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description))
+				u.cache.SetWithExpiration(strings.ReplaceAll(description.IndustryTypeID, " ", ""), []byte(description.Description), time.Duration(constant.MASTER_DATA_CACHE_TTL)*time.Minute)
 
 				if strings.ToUpper(inq.IndustryTypeID) == strings.ToUpper(strings.ReplaceAll(description.IndustryTypeID, " ", "")) {
 					industryType = []byte(description.Description)
@@ -2239,6 +2341,20 @@ func (u usecase) GetInquiryApproval(ctx context.Context, req request.ReqInquiryA
 
 	}
 
+	prospectIDs := make([]string, 0, len(data))
+	for _, row := range data {
+		prospectIDs = append(prospectIDs, row.General.ProspectID)
+	}
+
+	if !u.shapePII(piiAccess{UserID: req.Session.UserID, Role: req.Session.Role, Purpose: constant.PII_PURPOSE_INQUIRY_APPROVAL}, prospectIDs) {
+		for i := range data {
+			maskPersonal(&data[i].Personal)
+			maskSpouse(&data[i].Spouse)
+			maskEmcon(&data[i].Emcon)
+			maskAddress(&data[i].Address)
+		}
+	}
+
 	return
 }
 
@@ -2370,6 +2486,8 @@ func (u usecase) SubmitApproval(ctx context.Context, req request.ReqSubmitApprov
 		return
 	}
 
+	u.notifyApproval(req, status, approvalScheme)
+
 	if status.Reason == constant.REASON_REJECT_KUOTA_DEVIASI {
 		data = response.ApprovalResponse{
 			ProspectID:     req.ProspectID,
@@ -2406,6 +2524,29 @@ func (u usecase) GetInquiryQuotaDeviasi(req request.ReqListQuotaDeviasi, paginat
 	return
 }
 
+func (u usecase) GetQuotaDeviasiConsumption(req request.ReqListQuotaDeviasiConsumption, pagination interface{}) (data []entity.InquiryQuotaDeviasiConsumption, rowTotal int, err error) {
+
+	data, rowTotal, err = u.repository.GetQuotaDeviasiConsumption(req, pagination)
+
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// ReleaseQuotaDeviasi gives back the quota deviasi of an order closed by the journey, an order without booking is left as is
+func (u usecase) ReleaseQuotaDeviasi(ctx context.Context, prospectID, reason, releasedBy string) (released bool, err error) {
+
+	released, err = u.repository.ReleaseQuotaDeviasi(prospectID, reason, releasedBy)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Release Quota Deviasi error")
+		return
+	}
+
+	return
+}
+
 func (u usecase) GetQuotaDeviasiBranch(req request.ReqListQuotaDeviasiBranch) (data []entity.ConfinsBranch, err error) {
 
 	data, err = u.repository.GetQuotaDeviasiBranch(req)
@@ -2566,114 +2707,19 @@ func (u usecase) GenerateExcelQuotaDeviasi() (genName, fileName string, err erro
 }
 
 func (u usecase) UploadQuotaDeviasi(req request.ReqUploadSettingQuotaDeviasi, file multipart.File) (data response.UploadQuotaDeviasiBranchResponse, err error) {
-	xlsx, err := excelize.OpenReader(file)
-	if err != nil {
-		err = errors.New(fmt.Sprintf("%s - gagal membuka file excel", constant.ERROR_BAD_REQUEST))
-		return
-	}
-	defer xlsx.Close()
-
-	sheetName := "Quota Deviasi Branch"
-	rows, err := xlsx.GetRows(sheetName)
-	if err != nil {
-		err = errors.New(fmt.Sprintf("%s - gagal mendapatkan baris dari sheet excel", constant.ERROR_BAD_REQUEST))
-		return
-	}
-
-	if len(rows) < 2 {
-		err = errors.New(fmt.Sprintf("%s - file excel membutuhkan setidaknya 1 baris data", constant.ERROR_BAD_REQUEST))
-		return
-	}
-
-	headers := rows[0]
-	expectedHeaders := []string{"branch_id", "branch_name", "quota_amount", "quota_account", "is_active"}
-	for i, header := range headers {
-		if strings.ToLower(header) != expectedHeaders[i] {
-			err = errors.New(fmt.Sprintf("%s - format header tidak valid", constant.ERROR_BAD_REQUEST))
-			return
-		}
-	}
-
-	var updates []entity.MappingBranchDeviasi
-	var InvalidErr error
-	for rowIndex, row := range rows[1:] {
-		if len(row) < 5 {
-			InvalidErr = errors.New(fmt.Sprintf("%s - setiap baris harus memiliki 5 kolom, kesalahan pada baris ke %d", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		quotaAmount, err := strconv.ParseFloat(row[2], 64)
-		if err != nil {
-			InvalidErr = errors.New(fmt.Sprintf("%s - nilai quota_amount tidak valid pada baris ke %d, kolom 3", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		if quotaAmount < 0 {
-			InvalidErr = errors.New(fmt.Sprintf("%s - quota_amount tidak diperbolehkan negatif pada baris ke %d, kolom 3", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		if len(fmt.Sprintf("%.0f", quotaAmount)) > 11 {
-			InvalidErr = errors.New(fmt.Sprintf("%s - quota_amount lebih dari 11 digit pada baris ke %d, kolom 3", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		quotaAccount, err := strconv.Atoi(row[3])
-		if err != nil {
-			InvalidErr = errors.New(fmt.Sprintf("%s - nilai quota_account tidak valid pada baris ke %d, kolom 4", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		if quotaAccount < 0 {
-			InvalidErr = errors.New(fmt.Sprintf("%s - quota_account tidak diperbolehkan negatif pada baris ke %d, kolom 4", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		if len(fmt.Sprintf("%d", quotaAccount)) > 3 {
-			InvalidErr = errors.New(fmt.Sprintf("%s - quota_account lebih dari 3 digit pada baris ke %d, kolom 4", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		isActive := false
-		if strings.ToLower(row[4]) == "true" {
-			isActive = true
-		} else if strings.ToLower(row[4]) != "false" {
-			InvalidErr = errors.New(fmt.Sprintf("%s - nilai is_active tidak valid pada baris ke %d, kolom 5", constant.ERROR_BAD_REQUEST, rowIndex+2))
-			break
-		}
-
-		updates = append(updates, entity.MappingBranchDeviasi{
-			BranchID:     row[0],
-			QuotaAmount:  quotaAmount,
-			QuotaAccount: quotaAccount,
-			IsActive:     isActive,
-			UpdatedBy:    req.UpdatedByName,
-			UpdatedAt:    time.Now(),
-		})
-	}
 
+	rows, err := quotaDeviasiRows(file)
 	if err != nil {
 		return
 	}
 
-	if InvalidErr != nil {
-		err = InvalidErr
-		return
-	}
-
-	dataBeforeUpdate, dataAfterUpdate, err := u.repository.BatchUpdateQuotaDeviasi(updates)
-	if err != nil {
+	updates, _, rowErrors := parseQuotaDeviasiRows(rows[1:], req.UpdatedByName)
+	if len(rowErrors) > 0 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + rowErrors[0].Message)
 		return
 	}
 
-	data = response.UploadQuotaDeviasiBranchResponse{
-		Status:           constant.RESULT_OK,
-		Message:          constant.UPDATE_DEVIASI_SUCCESS,
-		DataBeforeUpdate: dataBeforeUpdate,
-		DataAfterUpdate:  dataAfterUpdate,
-	}
-
-	return
+	return u.applyQuotaDeviasi(updates)
 }
 
 func (u usecase) ResetQuotaDeviasiBranch(ctx context.Context, req request.ReqResetQuotaDeviasiBranch) (data response.UpdateQuotaDeviasiBranchResponse, err error) {
@@ -2858,148 +2904,21 @@ func (u usecase) GenerateExcelMappingCluster() (genName, fileName string, err er
 
 func (u usecase) UpdateMappingCluster(req request.ReqUploadMappingCluster, file multipart.File) (err error) {
 
-	var (
-		dataClusterBefore string
-		dataClusterAfter  string
-		history           entity.HistoryConfigChanges
-		cluster           []entity.MasterMappingCluster
-	)
-
-	f, err := excelize.OpenReader(file)
-	if err != nil {
-		err = errors.New(constant.ERROR_UPSTREAM + " - Open file excel mapping cluster error")
-		return
-	}
-	defer func() {
-		if err := f.Close(); err != nil {
-			return
-		}
-	}()
-
-	sheetName := f.GetSheetName(0)
-	rows, err := f.GetRows(sheetName)
+	rows, err := mappingClusterRows(file)
 	if err != nil {
 		return
 	}
 
-	var clusterRegex = regexp.MustCompile(`^Cluster [A-Z]$`)
-	var uniqueMappingData = make(map[string]int)
-	for i, row := range rows {
-		if i == 0 {
-			if len(row) == 0 {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom pertama harus berjudul 'branch_id'")
-			} else if row[0] != "branch_id" {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom pertama harus berjudul 'branch_id'")
-			} else if row[2] != "customer_status" {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom ketiga harus berjudul 'customer_status'")
-			} else if row[3] != "bpkb_name_type" {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom keempat harus berjudul 'bpkb_name_type'")
-			} else if len(row) < 5 {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "format file excel tidak sesuai: kolom kelima harus berjudul 'cluster'")
-			}
-		} else {
-			if len(row) == 0 {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai branch_id tidak boleh kosong")
-			}
-
-			branchID := strings.TrimSpace(row[0])
-			if branchID == "" {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai branch_id tidak boleh kosong")
-			} else if branchID == "0" {
-				branchID = constant.BRANCH_ID_PRIME_PRIORITY
-			}
-
-			customerStatus := strings.ToUpper(strings.TrimSpace(row[2]))
-			if customerStatus != constant.STATUS_KONSUMEN_NEW && customerStatus != "AO/RO" {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai customer_status harus " + constant.STATUS_KONSUMEN_NEW + " atau AO/RO")
-			}
-
-			bpkbName, err := strconv.Atoi(row[3])
-			if err != nil {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai bpkb_name_type harus 0 atau 1")
-			}
-
-			if bpkbName != 0 && bpkbName != 1 {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai bpkb_name_type harus 0 atau 1")
-			}
-
-			if len(row) < 5 {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai cluster tidak boleh kosong")
-			}
-
-			clusterStr := strings.TrimSpace(row[4])
-			if strings.EqualFold(clusterStr, constant.CLUSTER_PRIME_PRIORITY) {
-				clusterStr = strings.ToUpper(clusterStr)
-			} else {
-				clusterStr = strings.Title(strings.ToLower(clusterStr))
-			}
-
-			if clusterStr != constant.CLUSTER_PRIME_PRIORITY && !clusterRegex.MatchString(clusterStr) {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + ", nilai cluster tidak sesuai ketentuan")
-			}
-
-			uniqueKey := fmt.Sprintf("%s-%s-%d", branchID, customerStatus, bpkbName)
-
-			if rowIndex, exists := uniqueMappingData[uniqueKey]; exists {
-				return errors.New(constant.ERROR_BAD_REQUEST + " - " + "row " + strconv.Itoa(i+1) + " dan row " + strconv.Itoa(rowIndex+1) + ", entri duplikat untuk nilai branch_id, customer_status, dan bpkb_name_type")
-			}
-
-			uniqueMappingData[uniqueKey] = i
-
-			cluster = append(cluster, entity.MasterMappingCluster{
-				BranchID:       branchID,
-				CustomerStatus: customerStatus,
-				BpkbNameType:   bpkbName,
-				Cluster:        clusterStr,
-			})
-		}
+	cluster, rowErrors := parseMappingClusterRows(rows[1:])
+	if len(rowErrors) > 0 {
+		return errors.New(constant.ERROR_BAD_REQUEST + " - " + rowErrors[0].Message)
 	}
 
-	if len(cluster) > 0 {
-		existingCluster, err := u.repository.GetMappingCluster()
-		if err != nil && err.Error() != constant.RECORD_NOT_FOUND {
-			err = errors.New(constant.ERROR_UPSTREAM + " - Get existing mapping cluster branch error")
-			return err
-		}
-
-		jsonDataBefore, err := json.Marshal(existingCluster)
-		if err != nil {
-			err = errors.New(constant.ERROR_UPSTREAM + " - Error converting to JSON mapping cluster before")
-			return err
-		}
-
-		dataClusterBefore = string(jsonDataBefore)
-
-		jsonDataAfter, err := json.Marshal(cluster)
-		if err != nil {
-			err = errors.New(constant.ERROR_UPSTREAM + " - Error converting to JSON mapping cluster after")
-			return err
-		}
-
-		dataClusterAfter = string(jsonDataAfter)
-
-		history = entity.HistoryConfigChanges{
-			ID:         utils.GenerateUUID(),
-			ConfigID:   "kmb_mapping_cluster_branch",
-			ObjectName: "kmb_mapping_cluster_branch",
-			Action:     "UPDATE",
-			DataBefore: dataClusterBefore,
-			DataAfter:  dataClusterAfter,
-			CreatedBy:  req.UserID,
-			CreatedAt:  time.Now(),
-		}
-
-		err = u.repository.BatchUpdateMappingCluster(cluster, history)
-		if err != nil {
-			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + err.Error())
-			return err
-		}
-	} else {
-		err = errors.New(constant.ERROR_BAD_REQUEST + " - Mapping cluster branch dalam file excel kosong")
-		return err
+	if len(cluster) == 0 {
+		return errors.New(constant.ERROR_BAD_REQUEST + " - Mapping cluster branch dalam file excel kosong")
 	}
 
-	return
+	return u.applyMappingCluster(cluster, req.UserID)
 }
 
 func (u usecase) GetMappingClusterBranch(req request.ReqListMappingClusterBranch) (data []entity.ConfinsBranch, err error) {
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+)
+
+func (u usecase) GetWorkers(req request.ReqListWorker, pagination interface{}) (data []entity.InquiryWorker, rowTotal int, err error) {
+
+	data, rowTotal, err = u.repository.GetWorkers(req, pagination)
+
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// RequeueWorker runs a dead worker row again from its first attempt
+func (u usecase) RequeueWorker(ctx context.Context, req request.ReqRequeueWorker) (err error) {
+
+	if err = u.repository.RequeueWorker(req); err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Worker tidak ditemukan atau sedang diproses")
+			return
+		}
+
+		err = errors.New(constant.ERROR_UPSTREAM + " - Requeue worker error")
+		return
+	}
+
+	return
+}
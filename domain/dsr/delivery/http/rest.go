@@ -0,0 +1,56 @@
+package http
+
+import (
+	"los-kmb-api/domain/dsr/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handlerDsr struct {
+	usecase interfaces.Usecase
+	Json    common.JSON
+}
+
+func DsrHandler(kmbroute *echo.Group, usecase interfaces.Usecase, json common.JSON, middlewares *middlewares.AccessMiddleware) {
+	handler := handlerDsr{
+		usecase: usecase,
+		Json:    json,
+	}
+	kmbroute.POST("/dsr/calculate", handler.Calculate, middlewares.AccessMiddleware())
+}
+
+// DSR Calculate Tools godoc
+// @Description Api Calculate internal DSR, PBK DSR and total DSR against max DSR, income max DSR and deviasi DSR thresholds, a simulation only: the kmb and principle journeys do not decide through this api
+// @Tags DSR
+// @Produce json
+// @Param body body request.DsrCalculate true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.DsrCalculate}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/dsr/calculate [post]
+func (c *handlerDsr) Calculate(ctx echo.Context) (err error) {
+
+	var (
+		req         request.DsrCalculate
+		accessToken = middlewares.UserInfoData.AccessToken
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - DSR Calculate", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - DSR Calculate", req, err)
+	}
+
+	data, err := c.usecase.Calculate(ctx.Request().Context(), req)
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - DSR Calculate", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - DSR Calculate", req, data)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+)
+
+// Usecase is the standalone dsr calculation behind /dsr/calculate and the cms recalculate history,
+// the kmb and principle journeys keep deciding dsr through their own DsrCheck and TotalDsrFmfPbk
+type Usecase interface {
+	Calculate(ctx context.Context, req request.DsrCalculate) (data response.DsrCalculate, err error)
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/dsr/interfaces"
+	kmbInterfaces "los-kmb-api/domain/kmb/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"strconv"
+)
+
+type (
+	usecase struct {
+		repository kmbInterfaces.Repository
+	}
+
+	// thresholds are the dsr limits that apply to one calculation
+	thresholds struct {
+		configMaxDSR  float64
+		clusterMaxDSR float64
+		incomeMaxDSR  float64
+		deviasiDSR    float64
+	}
+)
+
+// NewUsecase reads the dsr mappings through the kmb repository so both flows use the same queries
+func NewUsecase(repository kmbInterfaces.Repository) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+	}
+}
+
+// Calculate loads the dsr thresholds for the customer and computes internal dsr, pbk dsr and total dsr with their decision,
+// a deviasi of an order with branch needs quota deviasi of the branch for its ntf
+func (u usecase) Calculate(ctx context.Context, req request.DsrCalculate) (data response.DsrCalculate, err error) {
+
+	var (
+		mappingCluster entity.MasterMappingMaxDSR
+		mappingIncome  entity.MasterMappingIncomeMaxDSR
+		mappingDeviasi entity.MasterMappingDeviasiDSR
+		limit          = thresholds{configMaxDSR: req.ConfigMaxDSR}
+	)
+
+	totalIncome := req.MonthlyFixedIncome + req.MonthlyVariableIncome + req.SpouseIncome
+	if totalIncome <= 0 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Total income harus lebih dari 0")
+		return
+	}
+
+	if req.Cluster != "" {
+		mappingCluster, err = u.repository.MasterMappingMaxDSR(entity.MasterMappingMaxDSR{Cluster: req.Cluster})
+		if err != nil && err.Error() != constant.DATA_NOT_FOUND {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Max DSR Error")
+			return
+		}
+		err = nil
+		limit.clusterMaxDSR = mappingCluster.DSRThreshold
+	}
+
+	mappingIncome, err = u.repository.MasterMappingIncomeMaxDSR(totalIncome)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Income Max DSR Error")
+		return
+	}
+	limit.incomeMaxDSR = mappingIncome.DSRThreshold
+
+	mappingDeviasi, err = u.repository.MasterMappingDeviasiDSR(totalIncome)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Deviasi DSR Error")
+		return
+	}
+	limit.deviasiDSR = mappingDeviasi.DSRThreshold
+
+	if limit.configMaxDSR <= 0 && limit.clusterMaxDSR <= 0 && limit.incomeMaxDSR <= 0 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - DSR threshold tidak ditemukan")
+		return
+	}
+
+	// the installment of an active agreement on the same chassis is replaced by the new installment
+	if req.InstallmentTopup == 0 && req.AgreementChassisNumber != nil && req.AgreementChassisNumber.IsActive && req.AgreementChassisNumber.IsRegistered {
+		req.InstallmentTopup = req.AgreementChassisNumber.InstallmentAmount
+	}
+
+	data = calculate(req, limit)
+
+	if !data.IsDeviasi || req.BranchID == "" {
+		return
+	}
+
+	branchDeviasi, err := u.repository.GetBranchDeviasi(req.BranchID, req.CustomerStatus, req.NTF)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Branch Deviasi Error")
+		return
+	}
+
+	if branchDeviasi.BranchID == "" {
+		data.IsDeviasi = false
+		data.Result = response.UsecaseApi{
+			Code:           constant.CODE_REJECT_KUOTA_DEVIASI,
+			Result:         constant.DECISION_REJECT,
+			Reason:         constant.REASON_REJECT_KUOTA_DEVIASI,
+			Dsr:            data.TotalDSR,
+			SourceDecision: constant.SOURCE_DECISION_DEVIASI,
+		}
+	}
+
+	return
+}
+
+func calculate(req request.DsrCalculate, limit thresholds) (data response.DsrCalculate) {
+
+	totalIncome := req.MonthlyFixedIncome + req.MonthlyVariableIncome + req.SpouseIncome
+
+	totalInstallmentFMF := req.InstallmentAmountFMF + req.InstallmentAmountSpouseFMF + req.InstallmentAmountOther + req.InstallmentAmountOtherSpouse - req.InstallmentTopup
+	if totalInstallmentFMF < 0 {
+		totalInstallmentFMF = 0
+	}
+
+	data = response.DsrCalculate{
+		ProspectID:          req.ProspectID,
+		TotalIncome:         totalIncome,
+		TotalInstallmentFMF: totalInstallmentFMF,
+		InstallmentPBK:      req.InstallmentPBK,
+		DSRFMF:              utils.ToFixed((req.NewInstallment+totalInstallmentFMF)/totalIncome*100, 2),
+		DSRPBK:              utils.ToFixed(req.InstallmentPBK/totalIncome*100, 2),
+		DeviasiDSR:          limit.deviasiDSR,
+	}
+	data.TotalDSR = utils.ToFixed(data.DSRFMF+data.DSRPBK, 2)
+
+	// internal dsr uses the configured max dsr, falling back to the cluster threshold
+	data.MaxDSRFMF = limit.configMaxDSR
+	if data.MaxDSRFMF <= 0 {
+		data.MaxDSRFMF = limit.clusterMaxDSR
+	}
+
+	// total dsr uses the income threshold first, then cluster, then config
+	switch {
+	case limit.incomeMaxDSR > 0:
+		data.MaxTotalDSR = limit.incomeMaxDSR
+		data.MaxTotalDSRSource = constant.DSR_THRESHOLD_SOURCE_INCOME
+	case limit.clusterMaxDSR > 0:
+		data.MaxTotalDSR = limit.clusterMaxDSR
+		data.MaxTotalDSRSource = constant.DSR_THRESHOLD_SOURCE_CLUSTER
+	default:
+		data.MaxTotalDSR = limit.configMaxDSR
+		data.MaxTotalDSRSource = constant.DSR_THRESHOLD_SOURCE_CONFIG
+	}
+
+	if data.MaxDSRFMF > 0 && data.DSRFMF > data.MaxDSRFMF {
+		data.InternalDSR = response.UsecaseApi{
+			Code:           constant.CODE_DSRGT35,
+			Result:         constant.DECISION_REJECT,
+			Reason:         fmt.Sprintf("%s %s %s", req.CustomerStatus, constant.REASON_DSRGT35, formatPercent(data.MaxDSRFMF)),
+			Dsr:            data.DSRFMF,
+			SourceDecision: constant.SOURCE_DECISION_DSR,
+		}
+		data.Result = data.InternalDSR
+		return
+	}
+
+	data.InternalDSR = response.UsecaseApi{
+		Code:           constant.CODE_DSRLTE35,
+		Result:         constant.DECISION_PASS,
+		Reason:         fmt.Sprintf("%s %s %s", req.CustomerStatus, constant.REASON_DSRLTE35, formatPercent(data.MaxDSRFMF)),
+		Dsr:            data.DSRFMF,
+		SourceDecision: constant.SOURCE_DECISION_DSR,
+	}
+
+	switch {
+	case data.TotalDSR <= data.MaxTotalDSR:
+		data.Result = response.UsecaseApi{
+			Code:           constant.CODE_TOTAL_DSRLTE35,
+			Result:         constant.DECISION_PASS,
+			Reason:         fmt.Sprintf("%s %s %s", req.CustomerStatus, constant.REASON_TOTAL_DSRLTE, formatPercent(data.MaxTotalDSR)),
+			Dsr:            data.TotalDSR,
+			SourceDecision: constant.SOURCE_DECISION_DSR,
+		}
+	case data.DeviasiDSR > 0 && data.TotalDSR <= data.DeviasiDSR:
+		data.IsDeviasi = true
+		data.Result = response.UsecaseApi{
+			Code:           constant.RULE_CODE_DEVIASI_DSR,
+			Result:         constant.DECISION_PASS,
+			Reason:         fmt.Sprintf("%s %s %s", constant.CODE_DEVIASI_DSR, constant.REASON_TOTAL_DSRGT, formatPercent(data.MaxTotalDSR)),
+			Dsr:            data.TotalDSR,
+			SourceDecision: constant.SOURCE_DECISION_DEVIASI,
+			IsDeviasi:      true,
+		}
+	default:
+		data.Result = response.UsecaseApi{
+			Code:           constant.CODE_TOTAL_DSRGT35,
+			Result:         constant.DECISION_REJECT,
+			Reason:         fmt.Sprintf("%s %s %s", req.CustomerStatus, constant.REASON_TOTAL_DSRGT, formatPercent(data.MaxTotalDSR)),
+			Dsr:            data.TotalDSR,
+			SourceDecision: constant.SOURCE_DECISION_DSR,
+		}
+	}
+
+	return
+}
+
+func formatPercent(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + "%"
+}
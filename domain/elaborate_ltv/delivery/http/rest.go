@@ -55,6 +55,7 @@ func ElaborateHandler(kmbroute *echo.Group, usecase interfaces.Usecase, reposito
 	}()
 
 	kmbroute.POST("/elaborate", handler.Elaborate, middlewares.AccessMiddleware())
+	kmbroute.POST("/elaborate/quote", handler.Quote, middlewares.AccessMiddleware())
 }
 
 func (c *handlerKmbElaborate) cleanExpiredCache() {
@@ -174,3 +175,50 @@ func (c *handlerKmbElaborate) Elaborate(ctx echo.Context) (err error) {
 	ctxJson, resp = c.Json.SuccessV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB ELABORATE", req, data)
 	return ctxJson
 }
+
+// ElaborateLTV Quote Tools godoc
+// @Description ElaborateLTV Quote, read-only LTV grid per tenor and manufacturing year
+// @Tags Filtering
+// @Produce json
+// @Param body body request.ElaborateLTVQuote true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.ElaborateLTVQuote}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/elaborate/quote [post]
+func (c *handlerKmbElaborate) Quote(ctx echo.Context) (err error) {
+
+	var (
+		req     request.ElaborateLTVQuote
+		ctxJson error
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorBindV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB ELABORATE QUOTE", req, err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB ELABORATE QUOTE", req, err)
+		return ctxJson
+	}
+
+	_, errAuth := c.authPlatform.Validation(ctx.Request().Header.Get(constant.HEADER_AUTHORIZATION), "")
+	if errAuth != nil {
+		if errAuth.GetErrorCode() == "401" {
+			err = fmt.Errorf(constant.ERROR_UNAUTHORIZED + " - Invalid token")
+		} else {
+			err = fmt.Errorf("%s - %v", constant.ERROR_UNAUTHORIZED, errAuth.ErrorMessage())
+		}
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB ELABORATE QUOTE", req, err)
+		return ctxJson
+	}
+
+	data, err := c.usecase.Quote(ctx.Request().Context(), req)
+	if err != nil {
+		ctxJson, _ = c.Json.ServerSideErrorV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB ELABORATE QUOTE", req, err)
+		return ctxJson
+	}
+
+	ctxJson, _ = c.Json.SuccessV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - KMB ELABORATE QUOTE", req, data)
+	return ctxJson
+}
@@ -8,4 +8,5 @@ import (
 
 type Usecase interface {
 	Elaborate(ctx context.Context, reqs request.ElaborateLTV, accessToken string) (data response.ElaborateLTV, err error)
+	Quote(ctx context.Context, reqs request.ElaborateLTVQuote) (data response.ElaborateLTVQuote, err error)
 }
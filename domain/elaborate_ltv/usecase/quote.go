@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"strconv"
+	"time"
+)
+
+// Quote returns the ltv for every tenor and manufacturing year combination of a filtered prospect.
+// It runs the same mapping as Elaborate but never writes trx_elaborate_ltv.
+func (u usecase) Quote(ctx context.Context, reqs request.ElaborateLTVQuote) (data response.ElaborateLTVQuote, err error) {
+
+	var (
+		param               elaborateParam
+		mappingElaborateLTV []entity.MappingElaborateLTV
+		tenors              = reqs.Tenors
+		manufacturingYears  = reqs.ManufacturingYears
+		now                 = time.Now()
+	)
+
+	param, err = u.getElaborateParam(reqs.ProspectID)
+	if err != nil {
+		return
+	}
+
+	mappingElaborateLTV, err = u.repository.GetMappingElaborateLTV(param.resultPefindo, param.cluster, param.bpkbNameType, param.customerStatus, param.gradePBK, param.gradeBranch)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get mapping elaborate error")
+		return
+	}
+
+	if len(tenors) == 0 {
+		for tenor := constant.ELABORATE_QUOTE_TENOR_STEP; tenor <= constant.ELABORATE_QUOTE_TENOR_MAX; tenor += constant.ELABORATE_QUOTE_TENOR_STEP {
+			tenors = append(tenors, tenor)
+		}
+	}
+
+	if len(manufacturingYears) == 0 {
+		for i := 0; i <= constant.ELABORATE_QUOTE_YEAR_SPAN; i++ {
+			manufacturingYears = append(manufacturingYears, strconv.Itoa(now.Year()-i))
+		}
+	}
+
+	data = response.ElaborateLTVQuote{
+		ProspectID: reqs.ProspectID,
+		Tenors:     tenors,
+		Grid:       []response.ElaborateLTVQuoteRow{},
+	}
+
+	for _, manufacturingYear := range manufacturingYears {
+		year, errParse := time.Parse("2006", manufacturingYear)
+		if errParse != nil {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Format tahun kendaraan tidak sesuai")
+			return
+		}
+
+		row := response.ElaborateLTVQuoteRow{
+			ManufacturingYear: manufacturingYear,
+			VehicleAge:        int((now.Sub(year).Hours() / 24) / 365),
+			LTV:               []response.ElaborateLTVQuoteCell{},
+		}
+
+		for _, tenor := range tenors {
+			var ageS string
+			ageS, err = vehicleAgeGroup(manufacturingYear, tenor, now)
+			if err != nil {
+				return
+			}
+
+			ltv, _ := matchElaborateLTV(mappingElaborateLTV, param, tenor, ageS)
+
+			row.LTV = append(row.LTV, response.ElaborateLTVQuoteCell{
+				Tenor:       tenor,
+				AgeVehicle:  ageS,
+				LTV:         ltv.LTV,
+				AdjustTenor: ltv.AdjustTenor,
+				MaxTenor:    ltv.MaxTenor,
+			})
+
+			if ltv.LTV > 0 && tenor > row.MaxTenor {
+				row.MaxTenor = tenor
+			}
+		}
+
+		if row.MaxTenor > data.MaxTenor {
+			data.MaxTenor = row.MaxTenor
+		}
+
+		data.Grid = append(data.Grid, row)
+	}
+
+	return
+}
@@ -32,15 +32,65 @@ func NewUsecase(repository interfaces.Repository, httpclient httpclient.HttpClie
 	}
 }
 
+// elaborateParam holds everything that selects the elaborate ltv mapping of a prospect, independent of tenor and manufacturing year
+type elaborateParam struct {
+	resultPefindo  string
+	cluster        string
+	bpkbNameType   int
+	customerStatus string
+	bakiDebet      float64
+	gradePBK       string
+	gradeBranch    string
+}
+
 func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, accessToken string) (data response.ElaborateLTV, err error) {
 
+	var (
+		param               elaborateParam
+		ageS                string
+		mappingElaborateLTV []entity.MappingElaborateLTV
+	)
+
+	param, err = u.getElaborateParam(reqs.ProspectID)
+	if err != nil {
+		return
+	}
+
+	ageS, err = vehicleAgeGroup(reqs.ManufacturingYear, reqs.Tenor, time.Now())
+	if err != nil {
+		return
+	}
+
+	trxElaborateLTV := entity.TrxElaborateLTV{
+		ProspectID:        reqs.ProspectID,
+		RequestID:         ctx.Value(echo.HeaderXRequestID).(string),
+		Tenor:             reqs.Tenor,
+		ManufacturingYear: reqs.ManufacturingYear,
+	}
+
+	mappingElaborateLTV, err = u.repository.GetMappingElaborateLTV(param.resultPefindo, param.cluster, param.bpkbNameType, param.customerStatus, param.gradePBK, param.gradeBranch)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get mapping elaborate error")
+		return
+	}
+
+	data, trxElaborateLTV.MappingElaborateLTVID = matchElaborateLTV(mappingElaborateLTV, param, reqs.Tenor, ageS)
+
+	err = u.repository.SaveTrxElaborateLTV(trxElaborateLTV)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " Save elaborate ltv error")
+		return
+	}
+
+	return
+}
+
+func (u usecase) getElaborateParam(prospectID string) (param elaborateParam, err error) {
+
 	var (
 		filteringKMB            entity.FilteringKMB
-		ageS                    string
 		bakiDebet               float64
 		bpkbNameType            int
-		manufacturingYear       time.Time
-		mappingElaborateLTV     []entity.MappingElaborateLTV
 		cluster                 string
 		RrdDateString           string
 		CreatedAtString         string
@@ -51,7 +101,7 @@ func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, acces
 		expiredContractConfig   entity.AppConfig
 	)
 
-	filteringKMB, err = u.repository.GetFilteringResult(reqs.ProspectID)
+	filteringKMB, err = u.repository.GetFilteringResult(prospectID)
 	if err != nil {
 		if err.Error() == constant.RECORD_NOT_FOUND {
 			err = errors.New(constant.ERROR_BAD_REQUEST + " - Silahkan melakukan filtering terlebih dahulu")
@@ -136,21 +186,6 @@ func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, acces
 		bpkbNameType = 1
 	}
 
-	now := time.Now()
-	// convert date to year for age_vehicle
-	manufacturingYear, err = time.Parse("2006", reqs.ManufacturingYear)
-	if err != nil {
-		err = errors.New(constant.ERROR_BAD_REQUEST + " - Format tahun kendaraan tidak sesuai")
-		return
-	}
-	subManufacturingYear := now.Sub(manufacturingYear)
-	age := int((subManufacturingYear.Hours()/24)/365) + (reqs.Tenor / 12)
-	if age <= 12 {
-		ageS = "<=12"
-	} else {
-		ageS = ">12"
-	}
-
 	if filteringKMB.TotalBakiDebetNonCollateralBiro != nil {
 		bakiDebet, err = utils.GetFloat(filteringKMB.TotalBakiDebetNonCollateralBiro)
 		if err != nil {
@@ -159,13 +194,6 @@ func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, acces
 		}
 	}
 
-	trxElaborateLTV := entity.TrxElaborateLTV{
-		ProspectID:        reqs.ProspectID,
-		RequestID:         ctx.Value(echo.HeaderXRequestID).(string),
-		Tenor:             reqs.Tenor,
-		ManufacturingYear: reqs.ManufacturingYear,
-	}
-
 	if OverrideFlowLikeRegular && resultPefindo == constant.DECISION_REJECT {
 		cluster = filteringKMB.CustomerStatus.(string) + " " + constant.CLUSTER_PRIME_PRIORITY
 		if int(bakiDebet) > constant.RANGE_CLUSTER_BAKI_DEBET_REJECT {
@@ -180,7 +208,7 @@ func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, acces
 		mappingBranch        entity.MappingBranchByPBKScore
 	)
 
-	filteringDetail, err = u.repository.GetFilteringDetail(reqs.ProspectID)
+	filteringDetail, err = u.repository.GetFilteringDetail(prospectID)
 	if err != nil {
 		err = errors.New(constant.ERROR_UPSTREAM + " - GetFilteringDetail error - " + err.Error())
 		return
@@ -217,55 +245,91 @@ func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, acces
 		mappingBranch.GradeBranch = constant.GOOD
 	}
 
-	mappingElaborateLTV, err = u.repository.GetMappingElaborateLTV(resultPefindo, cluster, bpkbNameType, filteringKMB.CustomerStatus.(string), gradePBK, mappingBranch.GradeBranch)
+	param = elaborateParam{
+		resultPefindo:  resultPefindo,
+		cluster:        cluster,
+		bpkbNameType:   bpkbNameType,
+		customerStatus: filteringKMB.CustomerStatus.(string),
+		bakiDebet:      bakiDebet,
+		gradePBK:       gradePBK,
+		gradeBranch:    mappingBranch.GradeBranch,
+	}
+
+	return
+}
+
+// vehicleAgeGroup returns the age_vehicle bucket of the asset at the end of the tenor
+func vehicleAgeGroup(manufacturingYear string, tenor int, now time.Time) (ageS string, err error) {
+
+	// convert date to year for age_vehicle
+	year, err := time.Parse("2006", manufacturingYear)
 	if err != nil {
-		err = errors.New(constant.ERROR_UPSTREAM + " - Get mapping elaborate error")
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Format tahun kendaraan tidak sesuai")
 		return
 	}
+	subManufacturingYear := now.Sub(year)
+	age := int((subManufacturingYear.Hours()/24)/365) + (tenor / 12)
+	if age <= 12 {
+		ageS = constant.AGE_VEHICLE_LTE_12
+	} else {
+		ageS = constant.AGE_VEHICLE_GT_12
+	}
+
+	return
+}
+
+// matchElaborateLTV picks the ltv and max tenor for a tenor from the mapping, the last matching row wins
+func matchElaborateLTV(mappingElaborateLTV []entity.MappingElaborateLTV, param elaborateParam, tenor int, ageS string) (data response.ElaborateLTV, mappingID int) {
+
+	var (
+		resultPefindo = param.resultPefindo
+		bpkbNameType  = param.bpkbNameType
+		bakiDebet     = param.bakiDebet
+	)
 
 	for _, m := range mappingElaborateLTV {
-		if reqs.Tenor >= 36 {
+		if tenor >= 36 {
 			//no hit
-			if resultPefindo == constant.DECISION_PBK_NO_HIT && m.TenorStart <= reqs.Tenor && reqs.Tenor <= m.TenorEnd && bpkbNameType == m.BPKBNameType && ageS == m.AgeVehicle {
+			if resultPefindo == constant.DECISION_PBK_NO_HIT && m.TenorStart <= tenor && tenor <= m.TenorEnd && bpkbNameType == m.BPKBNameType && ageS == m.AgeVehicle {
 				data.LTV = m.LTV
-				trxElaborateLTV.MappingElaborateLTVID = m.ID
+				mappingID = m.ID
 			}
 
 			//pass
-			if resultPefindo == constant.DECISION_PASS && m.TenorStart <= reqs.Tenor && reqs.Tenor <= m.TenorEnd && bpkbNameType == m.BPKBNameType && ageS == m.AgeVehicle {
+			if resultPefindo == constant.DECISION_PASS && m.TenorStart <= tenor && tenor <= m.TenorEnd && bpkbNameType == m.BPKBNameType && ageS == m.AgeVehicle {
 				data.LTV = m.LTV
-				trxElaborateLTV.MappingElaborateLTVID = m.ID
+				mappingID = m.ID
 			}
 
 			//reject
-			if resultPefindo == constant.DECISION_REJECT && m.TotalBakiDebetStart <= int(bakiDebet) && int(bakiDebet) <= m.TotalBakiDebetEnd && m.TenorStart <= reqs.Tenor && reqs.Tenor <= m.TenorEnd && bpkbNameType == m.BPKBNameType && ageS == m.AgeVehicle {
+			if resultPefindo == constant.DECISION_REJECT && m.TotalBakiDebetStart <= int(bakiDebet) && int(bakiDebet) <= m.TotalBakiDebetEnd && m.TenorStart <= tenor && tenor <= m.TenorEnd && bpkbNameType == m.BPKBNameType && ageS == m.AgeVehicle {
 				data.LTV = m.LTV
-				trxElaborateLTV.MappingElaborateLTVID = m.ID
+				mappingID = m.ID
 			}
 		} else {
 			//no hit
-			if resultPefindo == constant.DECISION_PBK_NO_HIT && m.TenorStart <= reqs.Tenor && reqs.Tenor <= m.TenorEnd {
+			if resultPefindo == constant.DECISION_PBK_NO_HIT && m.TenorStart <= tenor && tenor <= m.TenorEnd {
 				data.LTV = m.LTV
-				trxElaborateLTV.MappingElaborateLTVID = m.ID
+				mappingID = m.ID
 			}
 
 			//pass
-			if resultPefindo == constant.DECISION_PASS && m.TenorStart <= reqs.Tenor && reqs.Tenor <= m.TenorEnd {
+			if resultPefindo == constant.DECISION_PASS && m.TenorStart <= tenor && tenor <= m.TenorEnd {
 				if m.BPKBNameType == 1 {
 					if bpkbNameType == m.BPKBNameType {
 						data.LTV = m.LTV
-						trxElaborateLTV.MappingElaborateLTVID = m.ID
+						mappingID = m.ID
 					}
 				} else {
 					data.LTV = m.LTV
-					trxElaborateLTV.MappingElaborateLTVID = m.ID
+					mappingID = m.ID
 				}
 			}
 
 			//reject
-			if resultPefindo == constant.DECISION_REJECT && m.TotalBakiDebetStart <= int(bakiDebet) && int(bakiDebet) <= m.TotalBakiDebetEnd && m.TenorStart <= reqs.Tenor && reqs.Tenor <= m.TenorEnd {
+			if resultPefindo == constant.DECISION_REJECT && m.TotalBakiDebetStart <= int(bakiDebet) && int(bakiDebet) <= m.TotalBakiDebetEnd && m.TenorStart <= tenor && tenor <= m.TenorEnd {
 				data.LTV = m.LTV
-				trxElaborateLTV.MappingElaborateLTVID = m.ID
+				mappingID = m.ID
 			}
 		}
 
@@ -288,11 +352,5 @@ func (u usecase) Elaborate(ctx context.Context, reqs request.ElaborateLTV, acces
 		}
 	}
 
-	err = u.repository.SaveTrxElaborateLTV(trxElaborateLTV)
-	if err != nil {
-		err = errors.New(constant.ERROR_UPSTREAM + " Save elaborate ltv error")
-		return
-	}
-
 	return
 }
@@ -546,6 +546,29 @@ func (r *repoHandler) GetConfig(groupName string, lob string, key string) (appCo
 	return appConfig, err
 }
 
+// assetCheckStartDate moves the asset history window past the latest manual lift of an asset lock from cms
+func (r repoHandler) assetCheckStartDate(db *gorm.DB, chassisNumber, engineNumber string, startDate time.Time) (string, error) {
+	var lifted struct {
+		LiftedAt *time.Time `gorm:"column:lifted_at"`
+	}
+
+	err := db.Raw(`
+        SELECT MAX(created_at) AS lifted_at
+        FROM trx_lock_override WITH (NOLOCK)
+        WHERE banned_type = ? AND action = ? AND (chassis_number = ? OR engine_number = ?)
+    `, constant.BANNED_TYPE_ASSET, constant.LOCK_ACTION_LIFT, chassisNumber, engineNumber).Scan(&lifted).Error
+
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return "", err
+	}
+
+	if lifted.LiftedAt != nil && lifted.LiftedAt.After(startDate) {
+		return lifted.LiftedAt.Format("2006-01-02 15:04:05.000"), nil
+	}
+
+	return startDate.Format("2006-01-02"), nil
+}
+
 func (r repoHandler) getLatestRetryNumber(db *gorm.DB, chassisNumber, engineNumber, decision string, startDate string) (int, error) {
 	var maxRetry struct {
 		LatestRetryNumber int `gorm:"column:latest_retry_number"`
@@ -579,7 +602,10 @@ func (r repoHandler) GetAssetCancel(chassisNumber string, engineNumber string, l
 	currentDate := time.Now()
 	startDate := currentDate.AddDate(0, 0, -lockSystemConfig.Data.LockAssetCheck)
 
-	startDateStr := startDate.Format("2006-01-02")
+	startDateStr, err := r.assetCheckStartDate(db, chassisNumber, engineNumber, startDate)
+	if err != nil {
+		return historyData, false, err
+	}
 
 	query := `
         SELECT TOP 1
@@ -644,7 +670,10 @@ func (r repoHandler) GetAssetReject(chassisNumber string, engineNumber string, l
 	currentDate := time.Now()
 	startDate := currentDate.AddDate(0, 0, -lockSystemConfig.Data.LockAssetCheck)
 
-	startDateStr := startDate.Format("2006-01-02")
+	startDateStr, err := r.assetCheckStartDate(db, chassisNumber, engineNumber, startDate)
+	if err != nil {
+		return historyData, false, err
+	}
 
 	query := `
 		WITH journey_results AS (
@@ -0,0 +1,185 @@
+package checkpoint
+
+import (
+	"context"
+	"los-kmb-api/domain/kmb/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// checkpointJson ignores the json tags, the stage results carry fields hidden from the api responses (json:"-")
+// that are still needed to save the transaction at the end of the journey
+var checkpointJson = jsoniter.Config{TagKey: "checkpoint"}.Froze()
+
+type usecase struct {
+	interfaces.Usecase
+	repository interfaces.Repository
+}
+
+type multiUsecase struct {
+	interfaces.MultiUsecase
+	repository interfaces.Repository
+}
+
+type dupcheckResult struct {
+	Mapping   response.SpDupcheckMap
+	Status    string
+	Data      response.UsecaseApi
+	TrxFMF    response.TrxFMF
+	TrxDetail []entity.TrxDetail
+}
+
+type ekycResult struct {
+	Data      response.Ekyc
+	TrxDetail []entity.TrxDetail
+	TrxFMF    response.TrxFMF
+}
+
+type scoreproResult struct {
+	ResponseScs response.IntegratorScorePro
+	Data        response.ScorePro
+	PefindoIDX  response.PefindoIDX
+}
+
+// NewUsecase wraps the journey usecase so the scorepro stage, which also brings the pefindo result, is checkpointed per order
+func NewUsecase(repository interfaces.Repository, kmbUsecase interfaces.Usecase) interfaces.Usecase {
+	return &usecase{
+		Usecase:    kmbUsecase,
+		repository: repository,
+	}
+}
+
+// NewMultiUsecase wraps the journey multi usecase so the dupcheck and ekyc stages are checkpointed per order
+func NewMultiUsecase(repository interfaces.Repository, kmbMultiUsecase interfaces.MultiUsecase) interfaces.MultiUsecase {
+	return &multiUsecase{
+		MultiUsecase: kmbMultiUsecase,
+		repository:   repository,
+	}
+}
+
+func (u multiUsecase) Dupcheck(ctx context.Context, reqs request.DupcheckApi, married bool, accessToken, hrisAccessToken string, configValue response.DupcheckConfig) (mapping response.SpDupcheckMap, status string, data response.UsecaseApi, trxFMF response.TrxFMF, trxDetail []entity.TrxDetail, err error) {
+
+	var result dupcheckResult
+
+	if load(ctx, u.repository, reqs.ProspectID, constant.JOURNEY_STAGE_DUPCHECK, &result) {
+		return result.Mapping, result.Status, result.Data, result.TrxFMF, result.TrxDetail, nil
+	}
+
+	mapping, status, data, trxFMF, trxDetail, err = u.MultiUsecase.Dupcheck(ctx, reqs, married, accessToken, hrisAccessToken, configValue)
+	if err != nil {
+		return
+	}
+
+	save(ctx, accessToken, u.repository, reqs.ProspectID, constant.JOURNEY_STAGE_DUPCHECK, dupcheckResult{
+		Mapping:   mapping,
+		Status:    status,
+		Data:      data,
+		TrxFMF:    trxFMF,
+		TrxDetail: trxDetail,
+	})
+
+	return
+}
+
+func (u multiUsecase) Ekyc(ctx context.Context, req request.Metrics, reqMetricsEkyc request.MetricsEkyc, accessToken string) (data response.Ekyc, trxDetail []entity.TrxDetail, trxFMF response.TrxFMF, err error) {
+
+	var result ekycResult
+
+	if load(ctx, u.repository, req.Transaction.ProspectID, constant.JOURNEY_STAGE_EKYC, &result) {
+		return result.Data, result.TrxDetail, result.TrxFMF, nil
+	}
+
+	data, trxDetail, trxFMF, err = u.MultiUsecase.Ekyc(ctx, req, reqMetricsEkyc, accessToken)
+	if err != nil {
+		return
+	}
+
+	save(ctx, accessToken, u.repository, req.Transaction.ProspectID, constant.JOURNEY_STAGE_EKYC, ekycResult{
+		Data:      data,
+		TrxDetail: trxDetail,
+		TrxFMF:    trxFMF,
+	})
+
+	return
+}
+
+func (u usecase) Scorepro(ctx context.Context, req request.Metrics, pefindoScore, customerSegment string, spDupcheck response.SpDupcheckMap, accessToken string, filtering entity.FilteringKMB) (responseScs response.IntegratorScorePro, data response.ScorePro, pefindoIDX response.PefindoIDX, err error) {
+
+	var result scoreproResult
+
+	if load(ctx, u.repository, req.Transaction.ProspectID, constant.JOURNEY_STAGE_SCOREPRO, &result) {
+		return result.ResponseScs, result.Data, result.PefindoIDX, nil
+	}
+
+	responseScs, data, pefindoIDX, err = u.Usecase.Scorepro(ctx, req, pefindoScore, customerSegment, spDupcheck, accessToken, filtering)
+	if err != nil {
+		return
+	}
+
+	save(ctx, accessToken, u.repository, req.Transaction.ProspectID, constant.JOURNEY_STAGE_SCOREPRO, scoreproResult{
+		ResponseScs: responseScs,
+		Data:        data,
+		PefindoIDX:  pefindoIDX,
+	})
+
+	return
+}
+
+// load fills result with the stored stage result, only a resumed journey reuses a result and only within the validity window
+func load(ctx context.Context, repository interfaces.Repository, prospectID, stage string, result interface{}) bool {
+
+	if resume, _ := ctx.Value(constant.CTX_KEY_JOURNEY_RESUME).(bool); !resume {
+		return false
+	}
+
+	checkpoint, err := repository.GetJourneyCheckpoint(prospectID, stage)
+	if err != nil {
+		return false
+	}
+
+	validity, _ := strconv.Atoi(os.Getenv("JOURNEY_CHECKPOINT_VALIDITY_HOURS"))
+	if validity <= 0 {
+		validity = constant.JOURNEY_CHECKPOINT_VALIDITY
+	}
+
+	if time.Since(checkpoint.CreatedAt) > time.Duration(validity)*time.Hour {
+		return false
+	}
+
+	return checkpointJson.UnmarshalFromString(checkpoint.Result, result) == nil
+}
+
+// save stores the result of a completed stage, a checkpoint that can not be saved only means the stage runs again on
+// resume so the error is logged and the journey goes on
+func save(ctx context.Context, accessToken string, repository interfaces.Repository, prospectID, stage string, result interface{}) {
+
+	payload, err := checkpointJson.MarshalToString(result)
+	if err == nil {
+		err = repository.SaveJourneyCheckpoint(entity.TrxJourneyCheckpoint{
+			ProspectID: prospectID,
+			Stage:      stage,
+			Result:     payload,
+		})
+	}
+
+	if err != nil {
+		common.CentralizeLog(ctx, accessToken, common.CentralizeLogParameter{
+			Link:       os.Getenv("DUMMY_URL_LOGS"),
+			Action:     "SAVE_JOURNEY_CHECKPOINT",
+			Type:       "JOURNEY",
+			LogFile:    constant.NEW_KMB_LOG,
+			MsgLogFile: "LOS - Journey Checkpoint",
+			LevelLog:   constant.PLATFORM_LOG_LEVEL_ERROR,
+			Request:    map[string]string{"prospect_id": prospectID, "stage": stage},
+			Response:   err.Error(),
+		})
+	}
+}
@@ -46,6 +46,8 @@ func NewServiceKMB(app *platformevent.ConsumerRouter, repository interfaces.Repo
 	}
 	app.Handle(constant.KEY_PREFIX_SUBMIT_TO_LOS, handler.KMBIndex)
 	app.Handle(constant.KEY_PREFIX_AFTER_PRESCREENING, handler.KMBAfterPrescreening)
+	app.Handle(constant.KEY_PREFIX_RESUME_JOURNEY, handler.KMBResumeJourney)
+	app.Handle(constant.KEY_PREFIX_RELEASE_QUOTA_DEVIASI, handler.KMBReleaseQuotaDeviasi)
 }
 
 // event submit to los
@@ -183,17 +185,20 @@ func (h handlers) KMBIndex(ctx context.Context, event event.Event) (err error) {
 		}
 	}
 
+	// save req journey, it is used after prescreening and to resume a failed journey
+	h.saveJourney(req.Transaction.ProspectID, reqEncrypted)
+
 	resp, err = h.metrics.MetricsLos(ctx, req, middlewares.UserInfoData.AccessToken, middlewares.HrisApiData.Token)
 	if err != nil {
+		// the order can be resumed from the failed stage
+		h.markJourneyFailed(req.Transaction.ProspectID, err)
+
 		resp = h.Json.EventServiceError(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB", reqEncrypted, err)
 
 		// callback
 		h.producer.PublishEvent(ctx, middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_CALLBACK, reqEncrypted.Transaction.ProspectID, utils.StructToMap(resp), 0)
 
 	} else {
-		// save req journey
-		_ = h.repository.SaveTrxJourney(req.Transaction.ProspectID, reqEncrypted)
-
 		resp = h.Json.EventSuccess(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB", reqEncrypted, resp)
 
 		// callback all status
@@ -203,6 +208,118 @@ func (h handlers) KMBIndex(ctx context.Context, event event.Event) (err error) {
 	return nil
 }
 
+// saveJourney keeps the latest request of the order and clears the failed marker of an earlier run,
+// a resume is refused while this run is going on
+func (h handlers) saveJourney(prospectID string, reqEncrypted request.Metrics) {
+
+	_ = h.repository.SaveTrxJourney(prospectID, reqEncrypted)
+	_ = h.repository.DeleteJourneyCheckpoint(prospectID, constant.JOURNEY_STAGE_FAILED)
+}
+
+// markJourneyFailed writes the failed marker next to the stage checkpoints, only a marked order can be resumed
+func (h handlers) markJourneyFailed(prospectID string, err error) {
+
+	_ = h.repository.SaveJourneyCheckpoint(entity.TrxJourneyCheckpoint{
+		ProspectID: prospectID,
+		Stage:      constant.JOURNEY_STAGE_FAILED,
+		Result:     err.Error(),
+	})
+}
+
+// event resume journey, the journey runs again from the stored request and the completed integrator stages reuse their checkpoint
+func (h handlers) KMBResumeJourney(ctx context.Context, event event.Event) (err error) {
+	middlewares.GetPlatformAuth()
+	body := event.GetBody()
+
+	var (
+		trxJourney   entity.TrxJourney
+		reqResume    request.ResumeJourney
+		req          request.Metrics
+		reqEncrypted request.Metrics
+		resp         interface{}
+		countMaster  int
+	)
+
+	// Save Log Orchestrator
+	defer func() {
+		headers := map[string]string{constant.HeaderXRequestID: ctx.Value(constant.HeaderXRequestID).(string)}
+		go h.repository.SaveLogOrchestrator(headers, reqResume, resp, "/api/v3/kmb/consume/journey-resume", constant.METHOD_POST, reqResume.ProspectID, ctx.Value(constant.HeaderXRequestID).(string))
+	}()
+
+	err = jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(body, &reqResume)
+	if err == nil {
+		err = h.validator.Validate(reqResume)
+	}
+
+	if err != nil {
+		resp = h.Json.EventRequestErrorBindV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume", reqResume, err)
+		return nil
+	}
+
+	countMaster, err = h.repository.ScanTrxMaster(reqResume.ProspectID)
+	if err == nil && countMaster > 0 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_JOURNEY_ALREADY_DONE)
+	}
+
+	// taking the marker away claims the resume, a journey still running or resumed by another event has none
+	if err == nil {
+		if err = h.repository.DeleteJourneyCheckpoint(reqResume.ProspectID, constant.JOURNEY_STAGE_FAILED); err != nil {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_JOURNEY_NOT_FAILED)
+		}
+	}
+
+	if err == nil {
+		trxJourney, err = h.repository.GetTrxJourney(reqResume.ProspectID)
+		if err != nil {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_JOURNEY_NOT_RESUMABLE)
+		}
+	}
+
+	if err == nil {
+		err = jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(trxJourney.Request), &reqEncrypted)
+		if err == nil {
+			err = jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal([]byte(trxJourney.Request), &req)
+		}
+	}
+
+	if err != nil {
+		resp = h.Json.EventServiceError(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume", reqResume, err)
+		h.producer.PublishEvent(ctx, middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_CALLBACK, reqResume.ProspectID, utils.StructToMap(resp), 0)
+		return nil
+	}
+
+	ctx = context.WithValue(ctx, constant.CTX_KEY_INCOMING_REQUEST_URL, fmt.Sprintf("%s/api/v3/kmb/consume/journey-resume", constant.LOS_KMB_BASE_URL))
+	ctx = context.WithValue(ctx, constant.CTX_KEY_INCOMING_REQUEST_METHOD, constant.METHOD_POST)
+	ctx = context.WithValue(ctx, constant.CTX_KEY_JOURNEY_RESUME, true)
+
+	// the stored request has been validated when it was consumed, only decrypt it
+	req.CustomerPersonal.IDNumber, _ = utils.PlatformDecryptText(req.CustomerPersonal.IDNumber)
+	req.CustomerPersonal.LegalName, _ = utils.PlatformDecryptText(req.CustomerPersonal.LegalName)
+	req.CustomerPersonal.FullName, _ = utils.PlatformDecryptText(req.CustomerPersonal.FullName)
+	req.CustomerPersonal.SurgateMotherName, _ = utils.PlatformDecryptText(req.CustomerPersonal.SurgateMotherName)
+
+	if req.CustomerSpouse != nil {
+		req.CustomerSpouse.IDNumber, _ = utils.PlatformDecryptText(req.CustomerSpouse.IDNumber)
+		req.CustomerSpouse.LegalName, _ = utils.PlatformDecryptText(req.CustomerSpouse.LegalName)
+		req.CustomerSpouse.FullName, _ = utils.PlatformDecryptText(req.CustomerSpouse.FullName)
+		req.CustomerSpouse.SurgateMotherName, _ = utils.PlatformDecryptText(req.CustomerSpouse.SurgateMotherName)
+	}
+
+	resp, err = h.metrics.MetricsLos(ctx, req, middlewares.UserInfoData.AccessToken, middlewares.HrisApiData.Token)
+	if err != nil {
+		h.markJourneyFailed(reqResume.ProspectID, err)
+
+		resp = h.Json.EventServiceError(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume", reqEncrypted, err)
+	} else {
+		resp = h.Json.EventSuccess(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume", reqEncrypted, resp)
+	}
+
+	// callback
+	h.producer.PublishEvent(ctx, middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_CALLBACK, reqEncrypted.Transaction.ProspectID, utils.StructToMap(resp), 0)
+
+	return nil
+}
+
 // event after prescreening
 func (h handlers) KMBAfterPrescreening(ctx context.Context, event event.Event) (err error) {
 	middlewares.GetPlatformAuth()
@@ -348,8 +465,11 @@ func (h handlers) KMBAfterPrescreening(ctx context.Context, event event.Event) (
 		}
 	}
 
+	_ = h.repository.DeleteJourneyCheckpoint(reqAfterPrescreening.ProspectID, constant.JOURNEY_STAGE_FAILED)
+
 	resp, err = h.metrics.MetricsLos(ctx, req, middlewares.UserInfoData.AccessToken, middlewares.HrisApiData.Token)
 	if err != nil {
+		h.markJourneyFailed(reqAfterPrescreening.ProspectID, err)
 
 		resp = h.Json.EventServiceError(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB", reqEncrypted, err)
 
@@ -369,6 +489,16 @@ func (h handlers) KMBAfterPrescreening(ctx context.Context, event event.Event) (
 					Source:     constant.SYSTEM,
 				}
 				h.cmsUsecase.GenerateFormAKKK(ctx, reqGenAkkk, middlewares.UserInfoData.AccessToken)
+
+				reason := constant.QUOTA_REASON_REJECT
+				if result.Decision == constant.DECISION_CANCEL {
+					reason = constant.QUOTA_REASON_CANCEL
+				}
+				h.releaseQuotaDeviasi(ctx, request.ReleaseQuotaDeviasi{
+					ProspectID: reqEncrypted.Transaction.ProspectID,
+					Reason:     reason,
+					ReleasedBy: constant.SYSTEM_CREATED,
+				})
 			}
 
 			h.producer.PublishEvent(ctx, middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_CALLBACK, reqEncrypted.Transaction.ProspectID, utils.StructToMap(resp), 0)
@@ -377,3 +507,48 @@ func (h handlers) KMBAfterPrescreening(ctx context.Context, event event.Event) (
 
 	return nil
 }
+
+// releaseQuotaDeviasi gives back the quota deviasi of a closed order, the journey is already done at this point so a
+// failed release is queued as its own event instead of failing the journey
+func (h handlers) releaseQuotaDeviasi(ctx context.Context, req request.ReleaseQuotaDeviasi) {
+
+	_, err := h.cmsUsecase.ReleaseQuotaDeviasi(ctx, req.ProspectID, req.Reason, req.ReleasedBy)
+	if err == nil {
+		return
+	}
+
+	common.CentralizeLog(ctx, middlewares.UserInfoData.AccessToken, common.CentralizeLogParameter{
+		Link:       os.Getenv("DUMMY_URL_LOGS"),
+		Action:     "RELEASE_QUOTA_DEVIASI",
+		Type:       "EVENT_PLATFORM_LIBRARY",
+		LogFile:    constant.NEW_KMB_LOG,
+		MsgLogFile: "LOS - Release Quota Deviasi",
+		LevelLog:   constant.PLATFORM_LOG_LEVEL_ERROR,
+		Request:    req,
+		Response:   err.Error(),
+	})
+
+	h.producer.PublishEvent(ctx, middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_RELEASE_QUOTA_DEVIASI, req.ProspectID, utils.StructToMap(req), 0)
+}
+
+// event release quota deviasi, an error is returned so the consumer retries the release
+func (h handlers) KMBReleaseQuotaDeviasi(ctx context.Context, event event.Event) (err error) {
+	middlewares.GetPlatformAuth()
+	body := event.GetBody()
+
+	var req request.ReleaseQuotaDeviasi
+
+	err = jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(body, &req)
+	if err == nil {
+		err = h.validator.Validate(req)
+	}
+
+	if err != nil {
+		h.Json.EventRequestErrorBindV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Release Quota Deviasi", req, err)
+		return nil
+	}
+
+	_, err = h.cmsUsecase.ReleaseQuotaDeviasi(ctx, req.ProspectID, req.Reason, req.ReleasedBy)
+
+	return
+}
@@ -42,6 +42,7 @@ func KMBHandler(kmbroute *echo.Group, metrics interfaces.Metrics, usecase interf
 	}
 	kmbroute.POST("/produce/journey", handler.ProduceJourney, middlewares.AccessMiddleware())
 	kmbroute.POST("/produce/journey-after-prescreening", handler.ProduceJourneyAfterPrescreening, middlewares.AccessMiddleware())
+	kmbroute.POST("/produce/journey-resume", handler.ProduceJourneyResume, middlewares.AccessMiddleware())
 	kmbroute.POST("/recalculate", handler.Recalculate, middlewares.AccessMiddleware())
 	kmbroute.POST("/lock-system", handler.LockSystem, middlewares.AccessMiddleware())
 	kmbroute.POST("/insert-staging/:prospectID", handler.InsertStagingIndex, middlewares.AccessMiddleware())
@@ -173,6 +174,37 @@ func (c *handlerKMB) ProduceJourneyAfterPrescreening(ctx echo.Context) (err erro
 	return c.Json.SuccessV2(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB - Please wait, your request is being processed", req, nil)
 }
 
+// Produce Journey Resume
+// @Description Resume a failed journey from the stored request, the completed integrator stages are not called again
+// @Tags Submit to LOS
+// @Produce json
+// @Param body body request.ResumeJourney true "Body payload"
+// @Success 200 {object} response.ApiResponse{}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/produce/journey-resume [post]
+func (c *handlerKMB) ProduceJourneyResume(ctx echo.Context) (err error) {
+
+	var (
+		req     request.ResumeJourney
+		ctxJson error
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorBindV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume", req, err)
+		return ctxJson
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		ctxJson, _ = c.Json.BadRequestErrorValidationV3(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume", req, err)
+		return ctxJson
+	}
+
+	c.producer.PublishEvent(ctx.Request().Context(), middlewares.UserInfoData.AccessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_RESUME_JOURNEY, req.ProspectID, utils.StructToMap(req), 0)
+
+	return c.Json.SuccessV2(ctx, middlewares.UserInfoData.AccessToken, constant.NEW_KMB_LOG, "LOS - Journey KMB Resume - Please wait, your request is being processed", req, nil)
+}
+
 func (c *handlerKMB) LockSystem(ctx echo.Context) (err error) {
 	var (
 		req     request.LockSystem
@@ -23,6 +23,9 @@ type Repository interface {
 	SaveLogOrchestrator(header, request, response interface{}, path, method, prospectID string, requestID string) (err error)
 	SaveTrxJourney(prospectID string, request interface{}) (err error)
 	GetTrxJourney(prospectID string) (trxJourney entity.TrxJourney, err error)
+	SaveJourneyCheckpoint(checkpoint entity.TrxJourneyCheckpoint) (err error)
+	GetJourneyCheckpoint(prospectID, stage string) (checkpoint entity.TrxJourneyCheckpoint, err error)
+	DeleteJourneyCheckpoint(prospectID, stage string) (err error)
 	GetEncryptedValue(idNumber string, legalName string, motherName string) (encrypted entity.Encrypted, err error)
 
 	ScanKmbOff(query string) (data entity.ScanInstallmentAmount, err error)
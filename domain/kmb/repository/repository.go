@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/allegro/bigcache/v3"
+	"github.com/google/uuid"
 	"github.com/jinzhu/gorm"
 )
 
@@ -615,6 +616,13 @@ func (r repoHandler) SaveTransaction(countTrx int, data request.Metrics, trxPres
 				return err
 			}
 
+			if os.Getenv("BLIND_INDEX_KEY") != "" {
+				blindIndex := utils.NewCustomerBlindIndex(data.CustomerPersonal.IDNumber, data.CustomerPersonal.LegalName, data.CustomerPersonal.MobilePhone)
+				if err := utils.SaveCustomerBlindIndex(tx, data.Transaction.ProspectID, blindIndex); err != nil {
+					return err
+				}
+			}
+
 			var monthlyVariableIncome float64
 			if data.CustomerEmployment.MonthlyVariableIncome != nil {
 				monthlyVariableIncome = *data.CustomerEmployment.MonthlyVariableIncome
@@ -1102,6 +1110,7 @@ func (r repoHandler) SaveTransaction(countTrx int, data request.Metrics, trxPres
 	return
 }
 
+// SaveTrxJourney keeps the latest consumed request of the order, a corrected request replaces the one before it
 func (r repoHandler) SaveTrxJourney(prospectID string, request interface{}) (err error) {
 
 	requestByte, _ := json.Marshal(request)
@@ -1109,6 +1118,7 @@ func (r repoHandler) SaveTrxJourney(prospectID string, request interface{}) (err
 
 	trxJourney := entity.TrxJourney{
 		ProspectID: prospectID,
+		CreatedAt:  time.Now(),
 	}
 
 	asRunes := []rune(payload)
@@ -1119,10 +1129,26 @@ func (r repoHandler) SaveTrxJourney(prospectID string, request interface{}) (err
 		trxJourney.Request = payload
 	}
 
-	if err = r.newKmbDB.Model(&entity.TrxJourney{}).Create(&trxJourney).Error; err != nil {
-		return
-	}
-	return
+	return r.newKmbDB.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Model(&entity.TrxJourney{}).Where("ProspectID = ?", prospectID).Updates(map[string]interface{}{
+			"request":    trxJourney.Request,
+			"request2":   trxJourney.Request2,
+			"created_at": trxJourney.CreatedAt,
+		})
+
+		if err = result.Error; err != nil {
+			return err
+		}
+
+		if result.RowsAffected == 0 {
+			if err = tx.Create(&trxJourney).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (r repoHandler) GetTrxJourney(prospectID string) (trxJourney entity.TrxJourney, err error) {
@@ -1138,6 +1164,59 @@ func (r repoHandler) GetTrxJourney(prospectID string) (trxJourney entity.TrxJour
 	return
 }
 
+// SaveJourneyCheckpoint keeps the latest result of a completed stage of the journey, one row per order and stage
+func (r repoHandler) SaveJourneyCheckpoint(checkpoint entity.TrxJourneyCheckpoint) (err error) {
+
+	checkpoint.CreatedAt = time.Now()
+
+	return r.newKmbDB.Transaction(func(tx *gorm.DB) error {
+
+		result := tx.Model(&entity.TrxJourneyCheckpoint{}).Where("ProspectID = ? AND stage = ?", checkpoint.ProspectID, checkpoint.Stage).Updates(map[string]interface{}{
+			"result":     checkpoint.Result,
+			"created_at": checkpoint.CreatedAt,
+		})
+
+		if err = result.Error; err != nil {
+			return err
+		}
+
+		if result.RowsAffected == 0 {
+			if err = tx.Create(&checkpoint).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r repoHandler) GetJourneyCheckpoint(prospectID, stage string) (checkpoint entity.TrxJourneyCheckpoint, err error) {
+
+	if err = r.newKmbDB.Raw("SELECT ProspectID, stage, result, created_at FROM trx_journey_checkpoint WITH (nolock) WHERE ProspectID = ? AND stage = ?", prospectID, stage).Scan(&checkpoint).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+// DeleteJourneyCheckpoint removes the checkpoint of a stage, ERROR_ROWS_AFFECTED tells it was not there (anymore)
+func (r repoHandler) DeleteJourneyCheckpoint(prospectID, stage string) (err error) {
+
+	result := r.newKmbDB.Exec("DELETE FROM trx_journey_checkpoint WHERE ProspectID = ? AND stage = ?", prospectID, stage)
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = errors.New(constant.ERROR_ROWS_AFFECTED)
+	}
+
+	return
+}
+
 func (r repoHandler) SaveLogOrchestrator(header, request, response interface{}, path, method, prospectID string, requestID string) (err error) {
 
 	headerByte, _ := json.Marshal(header)
@@ -1365,9 +1444,13 @@ func (r repoHandler) GetCurrentTrxWithRejectDSR(idNumber string) (data entity.Tr
 
 func (r repoHandler) GetBannedPMKDSR(idNumber string) (data entity.TrxBannedPMKDSR, err error) {
 
-	date := time.Now().AddDate(0, 0, -30).Format(constant.FORMAT_DATE)
+	date := time.Now().AddDate(0, 0, -constant.LOCK_BANNED_DAYS).Format(constant.FORMAT_DATE)
 
-	if err = r.newKmbDB.Raw(fmt.Sprintf(`SELECT * FROM trx_banned_pmk_dsr WITH (nolock) WHERE IDNumber = '%s' AND CAST(created_at as DATE) >= '%s'`, idNumber, date)).Scan(&data).Error; err != nil {
+	// a manual override from cms replaces the banned window
+	if err = r.newKmbDB.Raw(fmt.Sprintf(`SELECT TOP 1 tbp.* FROM trx_banned_pmk_dsr tbp WITH (nolock)
+		OUTER APPLY (SELECT TOP 1 tlo.unban_date FROM trx_lock_override tlo WITH (nolock) WHERE tlo.lock_type = '%s' AND tlo.ProspectID = tbp.ProspectID ORDER BY tlo.created_at DESC) o
+		WHERE tbp.IDNumber = '%s' AND ((o.unban_date IS NULL AND CAST(tbp.created_at as DATE) >= '%s') OR o.unban_date > CAST(GETDATE() as DATE))
+		ORDER BY tbp.created_at DESC`, constant.LOCK_TYPE_BANNED_PMK_DSR, idNumber, date)).Scan(&data).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			err = nil
 		}
@@ -1392,6 +1475,9 @@ func (r repoHandler) GetTrxReject(idNumber string, config response.LockSystemCon
 			AND tf.id_number = '%s'
 			ORDER BY tf.created_at DESC)`, config.Data.LockRejectCheck, idNumber)
 
+	// history before a manual lift from cms no longer counts towards the lock
+	qLifted := lockLiftedAt(idNumber)
+
 	qRejectJourney := fmt.Sprintf(`SELECT TOP %d CAST(DATEADD(DAY, %d, ts.created_at) as DATE) as unban_date, 
 			ts.created_at, ts.ProspectID, tcp.IDNumber, ts.decision, ts.reason
 			FROM trx_status ts with (nolock) 
@@ -1399,6 +1485,7 @@ func (r repoHandler) GetTrxReject(idNumber string, config response.LockSystemCon
 			WHERE ts.decision = 'REJ' 
 			AND tcp.IDNumber = '%s'
 			AND ts.created_at >= '%s'
+			AND ts.created_at > @lifted_at
 			AND CAST(ts.created_at as DATE) >= @date_range AND @date_range <= CAST(ts.created_at as DATE) 
 			AND CAST(ts.created_at as DATE) >= CAST(DATEADD(DAY, -%d, GETDATE()) as DATE)`, config.Data.LockRejectAttempt, config.Data.LockRejectBan+1, idNumber, config.Data.LockStartDate, config.Data.LockRejectBan)
 
@@ -1411,10 +1498,11 @@ func (r repoHandler) GetTrxReject(idNumber string, config response.LockSystemCon
 			WHERE tf.next_process = 0 
 			AND tf.id_number = '%s'
 			AND tf.created_at >= '%s'
+			AND tf.created_at > @lifted_at
 			AND CAST(tf.created_at as DATE) >= @date_range_f AND @date_range_f <= CAST(tf.created_at as DATE) 
 			AND CAST(tf.created_at as DATE) >= CAST(DATEADD(DAY, -%d, GETDATE()) as DATE)`, config.Data.LockRejectAttempt, config.Data.LockRejectBan+1, idNumber, config.Data.LockStartDate, config.Data.LockRejectBan)
 
-	if err = r.newKmbDB.Raw(fmt.Sprintf(`%s %s %s UNION ALL %s ORDER BY created_at DESC`, qRangeJourney, qRangeFiltering, qRejectJourney, qRejectFiltering)).Scan(&data).Error; err != nil {
+	if err = r.newKmbDB.Raw(fmt.Sprintf(`%s %s %s %s UNION ALL %s ORDER BY created_at DESC`, qLifted, qRangeJourney, qRangeFiltering, qRejectJourney, qRejectFiltering)).Scan(&data).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			err = nil
 		}
@@ -1426,7 +1514,8 @@ func (r repoHandler) GetTrxReject(idNumber string, config response.LockSystemCon
 
 func (r repoHandler) GetTrxCancel(idNumber string, config response.LockSystemConfig) (data []entity.TrxLockSystem, err error) {
 
-	if err = r.newKmbDB.Raw(fmt.Sprintf(`DECLARE @date_range DATE = (SELECT TOP 1 CAST(DATEADD(DAY, -%d, ts.created_at) as DATE) as date_range
+	if err = r.newKmbDB.Raw(fmt.Sprintf(`%s
+			DECLARE @date_range DATE = (SELECT TOP 1 CAST(DATEADD(DAY, -%d, ts.created_at) as DATE) as date_range
 			FROM trx_status ts with (nolock) 
 			LEFT JOIN trx_customer_personal tcp with (nolock) ON ts.ProspectID = tcp.ProspectID
 			WHERE ts.decision = 'CAN' 
@@ -1439,9 +1528,10 @@ func (r repoHandler) GetTrxCancel(idNumber string, config response.LockSystemCon
 			WHERE ts.decision = 'CAN' 
 			AND tcp.IDNumber = '%s'
 			AND ts.created_at >= '%s'
+			AND ts.created_at > @lifted_at
 			AND CAST(ts.created_at as DATE) >= @date_range AND @date_range <= CAST(ts.created_at as DATE) 
 			AND CAST(ts.created_at as DATE) >= CAST(DATEADD(DAY, -%d, GETDATE()) as DATE)
-			ORDER BY ts.created_at DESC`, config.Data.LockCancelCheck, idNumber, config.Data.LockCancelAttempt, config.Data.LockCancelBan+1, idNumber, config.Data.LockStartDate, config.Data.LockCancelBan)).Scan(&data).Error; err != nil {
+			ORDER BY ts.created_at DESC`, lockLiftedAt(idNumber), config.Data.LockCancelCheck, idNumber, config.Data.LockCancelAttempt, config.Data.LockCancelBan+1, idNumber, config.Data.LockStartDate, config.Data.LockCancelBan)).Scan(&data).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			err = nil
 		}
@@ -1451,6 +1541,13 @@ func (r repoHandler) GetTrxCancel(idNumber string, config response.LockSystemCon
 	return
 }
 
+// lockLiftedAt declares @lifted_at, the time of the latest manual lift of a nik lock
+func lockLiftedAt(idNumber string) string {
+	return fmt.Sprintf(`DECLARE @lifted_at DATETIME = ISNULL((SELECT MAX(tlo.created_at) FROM trx_lock_override tlo WITH (nolock)
+			WHERE tlo.lock_type = '%s' AND tlo.banned_type = '%s' AND tlo.action = '%s' AND tlo.IDNumber = '%s'), '1900-01-01')`,
+		constant.LOCK_TYPE_SYSTEM, constant.BANNED_TYPE_NIK, constant.LOCK_ACTION_LIFT, idNumber)
+}
+
 func (r repoHandler) SaveTrxLockSystem(trxLockSystem entity.TrxLockSystem) (existingUnbanDate time.Time, err error) {
 	var count int64
 	var existingRecord entity.TrxLockSystem
@@ -1561,9 +1658,13 @@ func (r repoHandler) GetCurrentTrxWithReject(idNumber string) (data entity.TrxRe
 
 func (r repoHandler) GetBannedChassisNumber(chassisNumber string) (data entity.TrxBannedChassisNumber, err error) {
 
-	date := time.Now().AddDate(0, 0, -30).Format(constant.FORMAT_DATE)
+	date := time.Now().AddDate(0, 0, -constant.LOCK_BANNED_DAYS).Format(constant.FORMAT_DATE)
 
-	if err = r.newKmbDB.Raw(fmt.Sprintf(`SELECT * FROM trx_banned_chassis_number WITH (nolock) WHERE chassis_number = '%s' AND CAST(created_at as DATE) >= '%s'`, chassisNumber, date)).Scan(&data).Error; err != nil {
+	// a manual override from cms replaces the banned window
+	if err = r.newKmbDB.Raw(fmt.Sprintf(`SELECT TOP 1 tbc.* FROM trx_banned_chassis_number tbc WITH (nolock)
+		OUTER APPLY (SELECT TOP 1 tlo.unban_date FROM trx_lock_override tlo WITH (nolock) WHERE tlo.lock_type = '%s' AND tlo.ProspectID = tbc.ProspectID ORDER BY tlo.created_at DESC) o
+		WHERE tbc.chassis_number = '%s' AND ((o.unban_date IS NULL AND CAST(tbc.created_at as DATE) >= '%s') OR o.unban_date > CAST(GETDATE() as DATE))
+		ORDER BY tbc.created_at DESC`, constant.LOCK_TYPE_BANNED_CHASSIS, chassisNumber, date)).Scan(&data).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			err = nil
 		}
@@ -1782,6 +1883,35 @@ func (r repoHandler) SaveRecalculate(beforeRecalculate entity.TrxRecalculate, af
 			return err
 		}
 
+		// keep every recalculation, trx_recalculate only holds the latest figures
+		dataBefore, _ := json.Marshal(beforeRecalculate)
+		dataAfter, _ := json.Marshal(afterRecalculate)
+
+		TrxRecalculateHistory := entity.TrxRecalculateHistory{
+			ID:         uuid.New().String(),
+			ProspectID: afterRecalculate.ProspectID,
+			DataBefore: string(dataBefore),
+			DataAfter:  string(dataAfter),
+			CreatedAt:  time.Now(),
+		}
+		logInfo = TrxRecalculateHistory
+
+		// complete the row the credit analyst opened on the recalculate request, it carries who asked for it
+		result = tx.Exec(`UPDATE trx_recalculate_history SET data_before = ?, data_after = ?, created_at = ?
+			WHERE id = (SELECT TOP 1 id FROM trx_recalculate_history WITH (updlock, rowlock)
+			WHERE ProspectID = ? AND DATALENGTH(data_after) = 0 ORDER BY created_at DESC)`,
+			TrxRecalculateHistory.DataBefore, TrxRecalculateHistory.DataAfter, TrxRecalculateHistory.CreatedAt, TrxRecalculateHistory.ProspectID)
+
+		if err = result.Error; err != nil {
+			return err
+		}
+
+		if result.RowsAffected == 0 {
+			if err = tx.Create(&TrxRecalculateHistory).Error; err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"los-kmb-api/domain/lock_system/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handlerLockSystem struct {
+	usecase interfaces.Usecase
+	Json    common.JSON
+}
+
+func LockSystemHandler(kmbroute *echo.Group, usecase interfaces.Usecase, json common.JSON, middlewares *middlewares.AccessMiddleware) {
+	handler := handlerLockSystem{
+		usecase: usecase,
+		Json:    json,
+	}
+	kmbroute.GET("/lock-system/reapplication-date", handler.ReapplicationDate, middlewares.AccessMiddleware())
+}
+
+// Lock System Tools godoc
+// @Description Api Earliest re-application date of a customer or asset based on the active locks and bans
+// @Tags Lock System
+// @Produce json
+// @Param id_number query string false "id_number"
+// @Param chassis_number query string false "chassis_number"
+// @Param engine_number query string false "engine_number"
+// @Success 200 {object} response.ApiResponse{data=response.ReapplicationDate}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/lock-system/reapplication-date [get]
+func (c *handlerLockSystem) ReapplicationDate(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqReapplicationDate{
+		IDNumber:      ctx.QueryParam("id_number"),
+		ChassisNumber: ctx.QueryParam("chassis_number"),
+		EngineNumber:  ctx.QueryParam("engine_number"),
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reapplication Date", req, err)
+	}
+
+	data, err := c.usecase.GetReapplicationDate(ctx.Request().Context(), req)
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reapplication Date", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Reapplication Date", req, data)
+}
@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/lock_system/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"time"
+)
+
+// Run publishes unlock events every interval until ctx is done
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "UNLOCK_SCHEDULER",
+		MsgLogFile: "LOS - Unlock Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+	}, func(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+		published, err := usecase.PublishUnlock(ctx, accessToken)
+		return map[string]interface{}{"published": published}, err
+	})
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+)
+
+type Repository interface {
+	GetExpiredLocks(lookbackDays int) (data []entity.InquiryLockSystem, err error)
+	GetActiveLocks(idNumber, chassisNumber, engineNumber string) (data []entity.InquiryLockSystem, err error)
+	ClaimUnlockNotification(data []entity.TrxUnlockNotification) (err error)
+	UpdateUnlockNotificationStatus(ids []string, status string) (err error)
+	DeleteUnlockNotification(ids []string) (err error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+)
+
+type Usecase interface {
+	PublishUnlock(ctx context.Context, accessToken string) (published int, err error)
+	GetReapplicationDate(ctx context.Context, req request.ReqReapplicationDate) (data response.ReapplicationDate, err error)
+}
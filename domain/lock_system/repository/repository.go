@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/lock_system/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	NewKmb *gorm.DB
+}
+
+func NewRepository(NewKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		NewKmb: NewKmb,
+	}
+}
+
+// the select of every lock source, %[1]s is the condition on the effective unban date
+var (
+	qLockSystem = fmt.Sprintf(`SELECT '%s' AS lock_type,
+			CASE WHEN tls.reason LIKE 'Asset %%%%' THEN '%s' ELSE '%s' END AS banned_type,
+			tls.ProspectID, SCP.dbo.DEC_B64('SEC', tls.IDNumber) AS IDNumber,
+			ISNULL(tls.chassis_number, '') AS chassis_number, ISNULL(tls.engine_number, '') AS engine_number,
+			tls.reason, tls.created_at, CAST(tls.unban_date AS DATE) AS unban_date
+		FROM trx_lock_system tls WITH (nolock)
+		WHERE CAST(tls.unban_date AS DATE) %%[1]s`, constant.LOCK_TYPE_SYSTEM, constant.BANNED_TYPE_ASSET, constant.BANNED_TYPE_NIK)
+
+	qBannedPMKDSR = fmt.Sprintf(`SELECT '%[1]s' AS lock_type, '%[2]s' AS banned_type,
+			tbp.ProspectID, SCP.dbo.DEC_B64('SEC', tbp.IDNumber) AS IDNumber,
+			'' AS chassis_number, '' AS engine_number,
+			'%[1]s' AS reason, tbp.created_at, x.unban_date
+		FROM trx_banned_pmk_dsr tbp WITH (nolock)
+		OUTER APPLY (SELECT TOP 1 tlo.unban_date FROM trx_lock_override tlo WITH (nolock) WHERE tlo.lock_type = '%[1]s' AND tlo.ProspectID = tbp.ProspectID ORDER BY tlo.created_at DESC) o
+		CROSS APPLY (SELECT ISNULL(CAST(o.unban_date AS DATE), DATEADD(DAY, %[3]d, CAST(tbp.created_at AS DATE))) AS unban_date) x
+		WHERE x.unban_date %%[1]s`, constant.LOCK_TYPE_BANNED_PMK_DSR, constant.BANNED_TYPE_NIK, constant.LOCK_BANNED_DAYS+1)
+
+	qBannedChassis = fmt.Sprintf(`SELECT '%[1]s' AS lock_type, '%[2]s' AS banned_type,
+			tbc.ProspectID, '' AS IDNumber,
+			tbc.chassis_number, '' AS engine_number,
+			'%[1]s' AS reason, tbc.created_at, x.unban_date
+		FROM trx_banned_chassis_number tbc WITH (nolock)
+		OUTER APPLY (SELECT TOP 1 tlo.unban_date FROM trx_lock_override tlo WITH (nolock) WHERE tlo.lock_type = '%[1]s' AND tlo.ProspectID = tbc.ProspectID ORDER BY tlo.created_at DESC) o
+		CROSS APPLY (SELECT ISNULL(CAST(o.unban_date AS DATE), DATEADD(DAY, %[3]d, CAST(tbc.created_at AS DATE))) AS unban_date) x
+		WHERE x.unban_date %%[1]s`, constant.LOCK_TYPE_BANNED_CHASSIS, constant.BANNED_TYPE_ASSET, constant.LOCK_BANNED_DAYS+1)
+)
+
+// GetExpiredLocks returns locks whose unban date passed within the lookback window and were not notified yet, a
+// notification left PENDING past UNLOCK_PENDING_TIMEOUT minutes by a stopped replica is returned again
+func (r repoHandler) GetExpiredLocks(lookbackDays int) (data []entity.InquiryLockSystem, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	expired := fmt.Sprintf("<= CAST(GETDATE() AS DATE) AND %s > CAST(DATEADD(DAY, -%d, GETDATE()) AS DATE)", "%s", lookbackDays)
+
+	unions := []string{
+		fmt.Sprintf(qLockSystem, fmt.Sprintf(expired, "CAST(tls.unban_date AS DATE)")),
+		fmt.Sprintf(qBannedPMKDSR, fmt.Sprintf(expired, "x.unban_date")),
+		fmt.Sprintf(qBannedChassis, fmt.Sprintf(expired, "x.unban_date")),
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT u.* FROM (%s) AS u
+		WHERE NOT EXISTS (SELECT 1 FROM trx_unlock_notification tun WITH (nolock)
+			WHERE tun.lock_type = u.lock_type AND tun.ProspectID = u.ProspectID AND CAST(tun.unban_date AS DATE) = u.unban_date
+			AND (tun.status <> ? OR tun.created_at > DATEADD(MINUTE, -%d, GETDATE())))
+		ORDER BY u.unban_date ASC`, strings.Join(unions, " UNION ALL "), constant.UNLOCK_PENDING_TIMEOUT), constant.UNLOCK_STATUS_PENDING).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// GetActiveLocks returns the locks that still block a nik, or an asset by chassis or engine number, following the kmb lock checks
+func (r repoHandler) GetActiveLocks(idNumber, chassisNumber, engineNumber string) (data []entity.InquiryLockSystem, err error) {
+
+	var (
+		unions []string
+		args   []interface{}
+		x      sql.TxOptions
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	active := "> CAST(GETDATE() AS DATE)"
+
+	if idNumber != "" {
+		unions = append(unions,
+			fmt.Sprintf(qLockSystem, active)+" AND tls.IDNumber = SCP.dbo.ENC_B64('SEC', ?) AND tls.reason NOT LIKE 'Asset %'",
+			fmt.Sprintf(qBannedPMKDSR, active)+" AND tbp.IDNumber = SCP.dbo.ENC_B64('SEC', ?)",
+		)
+		args = append(args, idNumber, idNumber)
+	}
+
+	if chassisNumber != "" || engineNumber != "" {
+		unions = append(unions, fmt.Sprintf(qLockSystem, active)+" AND (tls.chassis_number = ? OR tls.engine_number = ?) AND tls.reason LIKE 'Asset %'")
+		args = append(args, chassisNumber, engineNumber)
+	}
+
+	if chassisNumber != "" {
+		unions = append(unions, fmt.Sprintf(qBannedChassis, active)+" AND tbc.chassis_number = ?")
+		args = append(args, chassisNumber)
+	}
+
+	if len(unions) == 0 {
+		return
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT u.* FROM (%s) AS u ORDER BY u.unban_date DESC`, strings.Join(unions, " UNION ALL ")), args...).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// ClaimUnlockNotification records the notifications of one nik or asset before its event is published, a notification
+// already recorded by another replica fails the whole claim with ERROR_ROWS_AFFECTED. A stale PENDING notification is
+// taken over
+func (r repoHandler) ClaimUnlockNotification(data []entity.TrxUnlockNotification) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	for _, val := range data {
+		result := db.Exec(fmt.Sprintf(`UPDATE trx_unlock_notification SET status = ?, created_at = ?
+			WHERE id = ? AND status = ? AND created_at <= DATEADD(MINUTE, -%d, GETDATE())`, constant.UNLOCK_PENDING_TIMEOUT),
+			val.Status, val.CreatedAt, val.ID, constant.UNLOCK_STATUS_PENDING)
+
+		if err = result.Error; err != nil {
+			return
+		}
+
+		if result.RowsAffected > 0 {
+			continue
+		}
+
+		result = db.Exec(`INSERT INTO trx_unlock_notification (id, lock_type, banned_type, ProspectID, unban_date, status, created_at)
+			SELECT ?, ?, ?, ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM trx_unlock_notification WITH (updlock, holdlock) WHERE id = ?)`,
+			val.ID, val.LockType, val.BannedType, val.ProspectID, val.UnbanDate, val.Status, val.CreatedAt, val.ID)
+
+		if err = result.Error; err != nil {
+			return
+		}
+
+		if result.RowsAffected == 0 {
+			err = errors.New(constant.ERROR_ROWS_AFFECTED)
+			return
+		}
+	}
+
+	return
+}
+
+func (r repoHandler) UpdateUnlockNotificationStatus(ids []string, status string) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec("UPDATE trx_unlock_notification SET status = ? WHERE id IN (?)", status, ids).Error
+
+	return
+}
+
+// DeleteUnlockNotification gives a claimed nik or asset back to the next run
+func (r repoHandler) DeleteUnlockNotification(ids []string) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec("DELETE FROM trx_unlock_notification WHERE id IN (?) AND status = ?", ids, constant.UNLOCK_STATUS_PENDING).Error
+
+	return
+}
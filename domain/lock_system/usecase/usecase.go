@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/lock_system/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common/platformevent"
+	"los-kmb-api/shared/constant"
+	"time"
+)
+
+type (
+	usecase struct {
+		repository interfaces.Repository
+		producer   platformevent.PlatformEventInterface
+	}
+
+	// unlockGroup collects the expired locks of one nik or one asset
+	unlockGroup struct {
+		bannedType    string
+		idNumber      string
+		chassisNumber string
+		engineNumber  string
+		locks         []entity.InquiryLockSystem
+	}
+)
+
+func NewUsecase(repository interfaces.Repository, producer platformevent.PlatformEventInterface) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+		producer:   producer,
+	}
+}
+
+// PublishUnlock publishes one unlock event per nik or asset whose last lock expired, a key that is still locked by another entry is skipped.
+// The notifications are recorded before the event is published so a nik or asset is published by one replica only
+func (u usecase) PublishUnlock(ctx context.Context, accessToken string) (published int, err error) {
+
+	expired, err := u.repository.GetExpiredLocks(constant.UNLOCK_LOOKBACK_DAYS)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Expired Locks error")
+		return
+	}
+
+	var (
+		keys   []string
+		groups = map[string]*unlockGroup{}
+	)
+
+	for _, lock := range expired {
+		// an asset is keyed by chassis number, engine number only when the chassis is unknown
+		key := constant.BANNED_TYPE_NIK + ":" + lock.IDNumber
+		if lock.BannedType == constant.BANNED_TYPE_ASSET {
+			key = constant.BANNED_TYPE_ASSET + ":" + lock.ChassisNumber
+			if lock.ChassisNumber == "" {
+				key = constant.BANNED_TYPE_ASSET + "::" + lock.EngineNumber
+			}
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &unlockGroup{bannedType: lock.BannedType}
+			if lock.BannedType == constant.BANNED_TYPE_NIK {
+				group.idNumber = lock.IDNumber
+			}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+
+		if lock.BannedType == constant.BANNED_TYPE_ASSET {
+			if group.chassisNumber == "" {
+				group.chassisNumber = lock.ChassisNumber
+			}
+			if group.engineNumber == "" {
+				group.engineNumber = lock.EngineNumber
+			}
+		}
+		group.locks = append(group.locks, lock)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+
+		active, errActive := u.repository.GetActiveLocks(group.idNumber, group.chassisNumber, group.engineNumber)
+		if errActive != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Active Locks error")
+			return
+		}
+
+		status := constant.UNLOCK_STATUS_SKIPPED
+		if len(active) == 0 {
+			status = constant.UNLOCK_STATUS_PENDING
+		}
+
+		var (
+			ids           []string
+			notifications []entity.TrxUnlockNotification
+		)
+
+		for _, lock := range group.locks {
+			ids = append(ids, fmt.Sprintf("%s|%s|%s", lock.LockType, lock.ProspectID, lock.UnbanDate.Format(constant.FORMAT_DATE)))
+			notifications = append(notifications, entity.TrxUnlockNotification{
+				ID:         ids[len(ids)-1],
+				LockType:   lock.LockType,
+				BannedType: lock.BannedType,
+				ProspectID: lock.ProspectID,
+				UnbanDate:  lock.UnbanDate,
+				Status:     status,
+				CreatedAt:  time.Now(),
+			})
+		}
+
+		if err = u.repository.ClaimUnlockNotification(notifications); err != nil {
+			// recorded by another replica
+			if err.Error() == constant.ERROR_ROWS_AFFECTED {
+				err = nil
+				continue
+			}
+			err = errors.New(constant.ERROR_UPSTREAM + " - Claim Unlock Notification error")
+			return
+		}
+
+		if status == constant.UNLOCK_STATUS_SKIPPED {
+			continue
+		}
+
+		if errPublish := u.publish(ctx, accessToken, group); errPublish != nil {
+			// given back, the next run retries it
+			if err = u.repository.DeleteUnlockNotification(ids); err != nil {
+				err = errors.New(constant.ERROR_UPSTREAM + " - Delete Unlock Notification error")
+				return
+			}
+			continue
+		}
+
+		if err = u.repository.UpdateUnlockNotificationStatus(ids, constant.UNLOCK_STATUS_PUBLISHED); err != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Update Unlock Notification error")
+			return
+		}
+		published++
+	}
+
+	return
+}
+
+func (u usecase) publish(ctx context.Context, accessToken string, group *unlockGroup) error {
+
+	var (
+		prospectIDs []string
+		unbanDate   time.Time
+	)
+
+	for _, lock := range group.locks {
+		prospectIDs = append(prospectIDs, lock.ProspectID)
+		if lock.UnbanDate.After(unbanDate) {
+			unbanDate = lock.UnbanDate
+		}
+	}
+
+	return u.producer.PublishEvent(ctx, accessToken, constant.TOPIC_UNLOCK, constant.KEY_PREFIX_UNLOCK, prospectIDs[0], map[string]interface{}{
+		"event":          constant.EVENT_UNLOCK,
+		"banned_type":    group.bannedType,
+		"id_number":      group.idNumber,
+		"chassis_number": group.chassisNumber,
+		"engine_number":  group.engineNumber,
+		"unban_date":     unbanDate.Format(constant.FORMAT_DATE),
+		"prospect_ids":   prospectIDs,
+	}, 0)
+}
+
+// GetReapplicationDate returns the first date a customer or asset can apply again, today when nothing blocks it
+func (u usecase) GetReapplicationDate(ctx context.Context, req request.ReqReapplicationDate) (data response.ReapplicationDate, err error) {
+
+	if req.IDNumber == "" && req.ChassisNumber == "" && req.EngineNumber == "" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - id_number, chassis_number atau engine_number wajib diisi")
+		return
+	}
+
+	active, err := u.repository.GetActiveLocks(req.IDNumber, req.ChassisNumber, req.EngineNumber)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Active Locks error")
+		return
+	}
+
+	now := time.Now()
+	earliest := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	data.Locks = []response.ReapplicationLock{}
+
+	for _, lock := range active {
+		if lock.UnbanDate.After(earliest) {
+			earliest = lock.UnbanDate
+		}
+
+		data.Locks = append(data.Locks, response.ReapplicationLock{
+			LockType:   lock.LockType,
+			BannedType: lock.BannedType,
+			ProspectID: lock.ProspectID,
+			Reason:     lock.Reason,
+			UnbanDate:  lock.UnbanDate.Format(constant.FORMAT_DATE),
+		})
+	}
+
+	data.IsLocked = len(active) > 0
+	data.EarliestReapplicationDate = earliest.Format(constant.FORMAT_DATE)
+
+	return
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"los-kmb-api/domain/mapping_checker/interfaces"
+)
+
+// Run prints the mapping check report as json and returns the process exit code:
+// 0 when the tables are consistent, 1 when issues are found and 2 when the check fails
+func Run(ctx context.Context, usecase interfaces.Usecase, out io.Writer) int {
+	data, err := usecase.CheckMapping(ctx)
+	if err != nil {
+		fmt.Fprintf(out, "mapping check failed: %s\n", err.Error())
+		return 2
+	}
+
+	report, _ := json.MarshalIndent(data, "", "  ")
+	fmt.Fprintln(out, string(report))
+
+	if data.TotalIssue > 0 {
+		return 1
+	}
+
+	return 0
+}
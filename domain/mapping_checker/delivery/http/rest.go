@@ -0,0 +1,42 @@
+package http
+
+import (
+	"los-kmb-api/domain/mapping_checker/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handlerMappingChecker struct {
+	usecase interfaces.Usecase
+	Json    common.JSON
+}
+
+func MappingCheckerHandler(kmbroute *echo.Group, usecase interfaces.Usecase, json common.JSON, middlewares *middlewares.AccessMiddleware) {
+	handler := handlerMappingChecker{
+		usecase: usecase,
+		Json:    json,
+	}
+	kmbroute.GET("/admin/mapping-checker", handler.CheckMapping, middlewares.AccessMiddleware())
+}
+
+// Mapping Checker Tools godoc
+// @Description Api Check gaps, overlaps, unreachable rows and missing combinations on credit mapping tables
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.ApiResponse{data=response.MappingCheckReport}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/admin/mapping-checker [get]
+func (c *handlerMappingChecker) CheckMapping(ctx echo.Context) (err error) {
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	data, err := c.usecase.CheckMapping(ctx.Request().Context())
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Checker", nil, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Mapping Checker", nil, data)
+}
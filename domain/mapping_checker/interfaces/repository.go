@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+)
+
+type Repository interface {
+	GetMappingElaborateLTV() (data []entity.MappingElaborateLTV, err error)
+	GetMappingVehicleAge() (data []entity.MappingVehicleAge, err error)
+	GetMappingIncomeMaxDSR() (data []entity.MasterMappingIncomeMaxDSR, err error)
+	GetMappingDeviasiDSR() (data []entity.MasterMappingDeviasiDSR, err error)
+	GetMappingIncomePMK() (data []entity.MappingIncomePMK, err error)
+	GetMappingPBKScoreGrade() (data []entity.MappingPBKScoreGrade, err error)
+	GetMappingBranchGrade() (data []entity.MappingBranchByPBKScore, err error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"los-kmb-api/models/response"
+)
+
+type Usecase interface {
+	CheckMapping(ctx context.Context) (data response.MappingCheckReport, err error)
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"los-kmb-api/domain/mapping_checker/interfaces"
+	"los-kmb-api/models/entity"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	KpLos  *gorm.DB
+	NewKmb *gorm.DB
+}
+
+func NewRepository(kpLos, newKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		KpLos:  kpLos,
+		NewKmb: newKmb,
+	}
+}
+
+func (r repoHandler) GetMappingElaborateLTV() (data []entity.MappingElaborateLTV, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT * FROM m_mapping_elaborate_ltv WITH (nolock) WHERE deleted_at IS NULL ORDER BY id").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetMappingVehicleAge() (data []entity.MappingVehicleAge, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT vehicle_age_start, vehicle_age_end, cluster, bpkb_name_type, tenor_start, tenor_end, result_pbk, af_start, af_end, decision FROM m_mapping_vehicle_age WITH (nolock)").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// total_income_end NULL means open-ended, it is returned as -1 so the usecase can tell it apart from 0
+func (r repoHandler) GetMappingIncomeMaxDSR() (data []entity.MasterMappingIncomeMaxDSR, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.KpLos.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT total_income_start, ISNULL(total_income_end, -1) AS total_income_end, dsr_threshold FROM kmb_mapping_income_dsr WITH (nolock)").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// total_income_end NULL means open-ended, it is returned as -1 so the usecase can tell it apart from 0
+func (r repoHandler) GetMappingDeviasiDSR() (data []entity.MasterMappingDeviasiDSR, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT total_income_start, ISNULL(total_income_end, -1) AS total_income_end, dsr_threshold FROM m_mapping_deviasi_dsr WITH (nolock)").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetMappingIncomePMK() (data []entity.MappingIncomePMK, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.KpLos.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT id, branch_id, status_konsumen, income, lob FROM mapping_income_pmk WITH (nolock) WHERE lob = 'los_kmb_off'").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetMappingPBKScoreGrade() (data []entity.MappingPBKScoreGrade, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT DISTINCT grade_score FROM m_mapping_pbk_grade WITH (nolock) WHERE deleted_at IS NULL").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetMappingBranchGrade() (data []entity.MappingBranchByPBKScore, err error) {
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT DISTINCT grade_branch FROM m_mapping_branch WITH (nolock) WHERE deleted_at IS NULL").Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
@@ -0,0 +1,631 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/mapping_checker/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	usecase struct {
+		repository interfaces.Repository
+	}
+)
+
+// valueRange is one row of a mapping table projected on a single dimension
+type valueRange struct {
+	start   float64
+	end     float64
+	openEnd bool
+	row     string
+}
+
+func NewUsecase(repository interfaces.Repository) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+	}
+}
+
+func (u usecase) CheckMapping(ctx context.Context) (data response.MappingCheckReport, err error) {
+
+	var (
+		elaborateLTV   []entity.MappingElaborateLTV
+		vehicleAge     []entity.MappingVehicleAge
+		incomeMaxDSR   []entity.MasterMappingIncomeMaxDSR
+		deviasiDSR     []entity.MasterMappingDeviasiDSR
+		incomePMK      []entity.MappingIncomePMK
+		pbkScoreGrade  []entity.MappingPBKScoreGrade
+		branchGrade    []entity.MappingBranchByPBKScore
+		pbkGrades      []string
+		branchGrades   []string
+		incomeMaxRange []valueRange
+		deviasiRange   []valueRange
+	)
+
+	elaborateLTV, err = u.repository.GetMappingElaborateLTV()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Elaborate LTV Error")
+		return
+	}
+
+	vehicleAge, err = u.repository.GetMappingVehicleAge()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Vehicle Age Error")
+		return
+	}
+
+	incomeMaxDSR, err = u.repository.GetMappingIncomeMaxDSR()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Income Max DSR Error")
+		return
+	}
+
+	deviasiDSR, err = u.repository.GetMappingDeviasiDSR()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Deviasi DSR Error")
+		return
+	}
+
+	incomePMK, err = u.repository.GetMappingIncomePMK()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Income PMK Error")
+		return
+	}
+
+	pbkScoreGrade, err = u.repository.GetMappingPBKScoreGrade()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping PBK Score Grade Error")
+		return
+	}
+
+	branchGrade, err = u.repository.GetMappingBranchGrade()
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Mapping Branch Grade Error")
+		return
+	}
+
+	// elaborate ltv uses grade NO HIT when there is no pbk detail and GOOD when the branch has no grade
+	pbkGrades = []string{constant.DECISION_PBK_NO_HIT}
+	for _, v := range pbkScoreGrade {
+		pbkGrades = appendUnique(pbkGrades, v.GradeScore)
+	}
+
+	branchGrades = []string{constant.GOOD}
+	for _, v := range branchGrade {
+		branchGrades = appendUnique(branchGrades, v.GradeBranch)
+	}
+
+	for _, v := range incomeMaxDSR {
+		incomeMaxRange = append(incomeMaxRange, incomeRange(v.TotalIncomeStart, v.TotalIncomeEnd, v.DSRThreshold))
+	}
+
+	for _, v := range deviasiDSR {
+		deviasiRange = append(deviasiRange, incomeRange(v.TotalIncomeStart, v.TotalIncomeEnd, v.DSRThreshold))
+	}
+
+	data.Tables = []response.MappingCheckTable{
+		checkElaborateLTV(elaborateLTV, pbkGrades, branchGrades),
+		checkVehicleAge(vehicleAge),
+		checkIncomeDSR("kmb_mapping_income_dsr", incomeMaxRange),
+		checkIncomeDSR("m_mapping_deviasi_dsr", deviasiRange),
+		checkIncomePMK(incomePMK),
+	}
+
+	for i := range data.Tables {
+		data.TotalIssue += len(data.Tables[i].Issues)
+	}
+
+	data.CheckedAt = time.Now().Format(constant.FORMAT_DATE_TIME)
+
+	return
+}
+
+func checkElaborateLTV(rows []entity.MappingElaborateLTV, pbkGrades, branchGrades []string) (table response.MappingCheckTable) {
+
+	table = response.MappingCheckTable{
+		Table:    "m_mapping_elaborate_ltv",
+		TotalRow: len(rows),
+		Issues:   []response.MappingCheckIssue{},
+	}
+
+	var (
+		groups     []string
+		groupRows  = map[string][]entity.MappingElaborateLTV{}
+		clusters   []string
+		statuses   = []string{constant.STATUS_KONSUMEN_NEW, constant.STATUS_KONSUMEN_RO, constant.STATUS_KONSUMEN_AO}
+		pefindoAll = []string{constant.DECISION_PASS, constant.DECISION_PBK_NO_HIT, constant.DECISION_REJECT}
+	)
+
+	for _, m := range rows {
+		rowID := fmt.Sprintf("id=%d", m.ID)
+		group := elaborateLTVGroup(m)
+
+		if m.TenorStart > m.TenorEnd {
+			table.Issues = append(table.Issues, unreachable(group, "tenor", rowID, fmt.Sprintf("tenor_start %d is greater than tenor_end %d", m.TenorStart, m.TenorEnd)))
+			continue
+		}
+
+		if m.ResultPefindo == constant.DECISION_REJECT && m.TotalBakiDebetStart > m.TotalBakiDebetEnd {
+			table.Issues = append(table.Issues, unreachable(group, "total_baki_debet", rowID, fmt.Sprintf("total_baki_debet_start %d is greater than total_baki_debet_end %d", m.TotalBakiDebetStart, m.TotalBakiDebetEnd)))
+			continue
+		}
+
+		// tenor >= 36 only matches age_vehicle <=12 or >12
+		if m.TenorStart >= 36 && m.AgeVehicle != constant.AGE_VEHICLE_LTE_12 && m.AgeVehicle != constant.AGE_VEHICLE_GT_12 {
+			table.Issues = append(table.Issues, unreachable(group, "age_vehicle", rowID, fmt.Sprintf("tenor %d-%d requires age_vehicle %s or %s, got '%s'", m.TenorStart, m.TenorEnd, constant.AGE_VEHICLE_LTE_12, constant.AGE_VEHICLE_GT_12, m.AgeVehicle)))
+			continue
+		}
+
+		if _, ok := groupRows[group]; !ok {
+			groups = append(groups, group)
+		}
+		groupRows[group] = append(groupRows[group], m)
+
+		clusters = appendUnique(clusters, m.Cluster)
+	}
+
+	for _, group := range groups {
+		members := groupRows[group]
+
+		// elaborate keeps the last matching row, so a row covered by a later row is never used
+		for i, m := range members {
+			for _, later := range members[i+1:] {
+				if later.TenorStart <= m.TenorStart && m.TenorEnd <= later.TenorEnd &&
+					(m.ResultPefindo != constant.DECISION_REJECT || (later.TotalBakiDebetStart <= m.TotalBakiDebetStart && m.TotalBakiDebetEnd <= later.TotalBakiDebetEnd)) {
+					table.Issues = append(table.Issues, unreachable(group, "tenor", fmt.Sprintf("id=%d", m.ID), fmt.Sprintf("row is always overridden by id=%d", later.ID)))
+					break
+				}
+			}
+		}
+
+		if members[0].ResultPefindo != constant.DECISION_REJECT {
+			var tenors []valueRange
+			for _, m := range members {
+				tenors = append(tenors, valueRange{start: float64(m.TenorStart), end: float64(m.TenorEnd), row: fmt.Sprintf("id=%d", m.ID)})
+			}
+			table.Issues = append(table.Issues, rangeIssues(group, "tenor", tenors, 1, false)...)
+			continue
+		}
+
+		var (
+			byBakiDebet = map[string][]valueRange{}
+			byTenor     = map[string][]valueRange{}
+			bakiKeys    []string
+			tenorKeys   []string
+		)
+
+		for _, m := range members {
+			bakiKey := fmt.Sprintf("%s, total_baki_debet=%d-%d", group, m.TotalBakiDebetStart, m.TotalBakiDebetEnd)
+			tenorKey := fmt.Sprintf("%s, tenor=%d-%d", group, m.TenorStart, m.TenorEnd)
+
+			if _, ok := byBakiDebet[bakiKey]; !ok {
+				bakiKeys = append(bakiKeys, bakiKey)
+			}
+			if _, ok := byTenor[tenorKey]; !ok {
+				tenorKeys = append(tenorKeys, tenorKey)
+			}
+
+			byBakiDebet[bakiKey] = append(byBakiDebet[bakiKey], valueRange{start: float64(m.TenorStart), end: float64(m.TenorEnd), row: fmt.Sprintf("id=%d", m.ID)})
+			byTenor[tenorKey] = append(byTenor[tenorKey], valueRange{start: float64(m.TotalBakiDebetStart), end: float64(m.TotalBakiDebetEnd), row: fmt.Sprintf("id=%d", m.ID)})
+		}
+
+		for _, key := range bakiKeys {
+			table.Issues = append(table.Issues, rangeIssues(key, "tenor", byBakiDebet[key], 1, false)...)
+		}
+
+		for _, key := range tenorKeys {
+			table.Issues = append(table.Issues, rangeIssues(key, "total_baki_debet", byTenor[key], 1, false)...)
+		}
+	}
+
+	for _, cluster := range clusters {
+		for _, resultPefindo := range pefindoAll {
+			for _, status := range statuses {
+
+				// clusters prefixed with a customer status (e.g. RO PRIME PRIORITY) are only used for that status on reject
+				if prefix := clusterStatusPrefix(cluster, statuses); prefix != "" && (prefix != status || resultPefindo != constant.DECISION_REJECT) {
+					continue
+				}
+
+				var missing []string
+				for _, pbkGrade := range pbkGrades {
+					for _, branchGrade := range branchGrades {
+						found := false
+						for _, m := range rows {
+							if m.ResultPefindo == resultPefindo && m.Cluster == cluster &&
+								matchAll(m.StatusKonsumen, status) && matchAll(m.PbkScore, pbkGrade) && matchAll(m.GradeBranch, branchGrade) {
+								found = true
+								break
+							}
+						}
+						if !found {
+							missing = append(missing, fmt.Sprintf("pbk_score=%s, grade_branch=%s", pbkGrade, branchGrade))
+						}
+					}
+				}
+
+				if len(missing) > 0 {
+					table.Issues = append(table.Issues, response.MappingCheckIssue{
+						Type:        constant.MAPPING_ISSUE_MISSING_COMBINATION,
+						Group:       fmt.Sprintf("result_pefindo=%s, cluster=%s, status_konsumen=%s", resultPefindo, cluster, status),
+						Rows:        missing,
+						Description: fmt.Sprintf("no row matches %d of %d grade combinations, elaborate returns LTV 0", len(missing), len(pbkGrades)*len(branchGrades)),
+					})
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func checkVehicleAge(rows []entity.MappingVehicleAge) (table response.MappingCheckTable) {
+
+	table = response.MappingCheckTable{
+		Table:    "m_mapping_vehicle_age",
+		TotalRow: len(rows),
+		Issues:   []response.MappingCheckIssue{},
+	}
+
+	var (
+		groups      []string
+		groupRows   = map[string][]entity.MappingVehicleAge{}
+		clusters    []string
+		resultPbks  []string
+		bpkbTypes   = []int{0, 1}
+		sliceKeys   []string
+		sliceRanges = map[string][]valueRange{}
+		sliceDims   = map[string]string{}
+	)
+
+	addSlice := func(key, dimension string, v valueRange) {
+		if _, ok := sliceRanges[key]; !ok {
+			sliceKeys = append(sliceKeys, key)
+			sliceDims[key] = dimension
+		}
+		sliceRanges[key] = append(sliceRanges[key], v)
+	}
+
+	for _, m := range rows {
+		group := fmt.Sprintf("cluster=%s, bpkb_name_type=%d, result_pbk=%s", m.Cluster, m.BPKBNameType, m.ResultPbk)
+		row := vehicleAgeRow(m)
+
+		if m.VehicleAgeStart > m.VehicleAgeEnd {
+			table.Issues = append(table.Issues, unreachable(group, "vehicle_age", row, "vehicle_age_start is greater than vehicle_age_end"))
+			continue
+		}
+
+		if m.TenorStart > m.TenorEnd {
+			table.Issues = append(table.Issues, unreachable(group, "tenor", row, "tenor_start is greater than tenor_end"))
+			continue
+		}
+
+		// af is matched as af_start < af <= af_end
+		if m.AFStart >= m.AFEnd {
+			table.Issues = append(table.Issues, unreachable(group, "af", row, "af_start is not lower than af_end"))
+			continue
+		}
+
+		if _, ok := groupRows[group]; !ok {
+			groups = append(groups, group)
+		}
+		groupRows[group] = append(groupRows[group], m)
+
+		clusters = appendUnique(clusters, m.Cluster)
+		resultPbks = appendUnique(resultPbks, m.ResultPbk)
+
+		ageKey := fmt.Sprintf("vehicle_age=%d-%d", m.VehicleAgeStart, m.VehicleAgeEnd)
+		tenorKey := fmt.Sprintf("tenor=%d-%d", m.TenorStart, m.TenorEnd)
+		afKey := fmt.Sprintf("af=%s-%s", formatFloat(m.AFStart), formatFloat(m.AFEnd))
+
+		addSlice(fmt.Sprintf("%s, %s, %s", group, ageKey, afKey), "tenor", valueRange{start: float64(m.TenorStart), end: float64(m.TenorEnd), row: row})
+		addSlice(fmt.Sprintf("%s, %s, %s", group, tenorKey, afKey), "vehicle_age", valueRange{start: float64(m.VehicleAgeStart), end: float64(m.VehicleAgeEnd), row: row})
+		addSlice(fmt.Sprintf("%s, %s, %s", group, ageKey, tenorKey), "af", valueRange{start: m.AFStart, end: m.AFEnd, row: row})
+	}
+
+	// lookup takes TOP 1 without ordering, so any two rows sharing a point make the result non deterministic
+	for _, group := range groups {
+		members := groupRows[group]
+		for i, a := range members {
+			for _, b := range members[i+1:] {
+				if a.VehicleAgeStart <= b.VehicleAgeEnd && b.VehicleAgeStart <= a.VehicleAgeEnd &&
+					a.TenorStart <= b.TenorEnd && b.TenorStart <= a.TenorEnd &&
+					a.AFStart < b.AFEnd && b.AFStart < a.AFEnd {
+					table.Issues = append(table.Issues, response.MappingCheckIssue{
+						Type:        constant.MAPPING_ISSUE_OVERLAP,
+						Dimension:   "vehicle_age, tenor, af",
+						Group:       group,
+						Rows:        []string{vehicleAgeRow(a), vehicleAgeRow(b)},
+						Description: "rows match the same vehicle age, tenor and af",
+					})
+				}
+			}
+		}
+	}
+
+	for _, key := range sliceKeys {
+		halfOpen := sliceDims[key] == "af"
+		step := float64(1)
+		if halfOpen {
+			step = 0
+		}
+		for _, issue := range rangeIssues(key, sliceDims[key], sliceRanges[key], step, halfOpen) {
+			if issue.Type == constant.MAPPING_ISSUE_GAP {
+				table.Issues = append(table.Issues, issue)
+			}
+		}
+	}
+
+	for _, cluster := range clusters {
+		for _, bpkbNameType := range bpkbTypes {
+			for _, resultPbk := range resultPbks {
+				found := false
+				for _, m := range rows {
+					if strings.Contains(m.Cluster, cluster) && m.BPKBNameType == bpkbNameType && strings.Contains(m.ResultPbk, resultPbk) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					table.Issues = append(table.Issues, response.MappingCheckIssue{
+						Type:        constant.MAPPING_ISSUE_MISSING_COMBINATION,
+						Group:       fmt.Sprintf("cluster=%s, bpkb_name_type=%d, result_pbk=%s", cluster, bpkbNameType, resultPbk),
+						Description: "no row for this combination",
+					})
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func checkIncomeDSR(tableName string, rows []valueRange) (table response.MappingCheckTable) {
+
+	table = response.MappingCheckTable{
+		Table:    tableName,
+		TotalRow: len(rows),
+		Issues:   []response.MappingCheckIssue{},
+	}
+
+	var (
+		valid   []valueRange
+		minimum *valueRange
+		hasOpen bool
+		maximum float64
+	)
+
+	for i, v := range rows {
+		if !v.openEnd && v.start > v.end {
+			table.Issues = append(table.Issues, unreachable(tableName, "total_income", v.row, "total_income_start is greater than total_income_end"))
+			continue
+		}
+
+		valid = append(valid, v)
+
+		if minimum == nil || v.start < minimum.start {
+			minimum = &rows[i]
+		}
+		if v.openEnd {
+			hasOpen = true
+		} else if v.end > maximum {
+			maximum = v.end
+		}
+	}
+
+	if len(valid) == 0 {
+		table.Issues = append(table.Issues, response.MappingCheckIssue{
+			Type:        constant.MAPPING_ISSUE_MISSING_COMBINATION,
+			Dimension:   "total_income",
+			Group:       tableName,
+			Description: "table has no usable row",
+		})
+		return
+	}
+
+	if minimum.start > 0 {
+		table.Issues = append(table.Issues, response.MappingCheckIssue{
+			Type:        constant.MAPPING_ISSUE_GAP,
+			Dimension:   "total_income",
+			Group:       tableName,
+			Rows:        []string{minimum.row},
+			Description: fmt.Sprintf("total_income below %s has no row", formatFloat(minimum.start)),
+		})
+	}
+
+	if !hasOpen {
+		table.Issues = append(table.Issues, response.MappingCheckIssue{
+			Type:        constant.MAPPING_ISSUE_GAP,
+			Dimension:   "total_income",
+			Group:       tableName,
+			Description: fmt.Sprintf("total_income above %s has no row, set total_income_end NULL on the last range", formatFloat(maximum)),
+		})
+	}
+
+	table.Issues = append(table.Issues, rangeIssues(tableName, "total_income", valid, 1, false)...)
+
+	return
+}
+
+func checkIncomePMK(rows []entity.MappingIncomePMK) (table response.MappingCheckTable) {
+
+	table = response.MappingCheckTable{
+		Table:    "mapping_income_pmk",
+		TotalRow: len(rows),
+		Issues:   []response.MappingCheckIssue{},
+	}
+
+	var (
+		keys     []string
+		keyRows  = map[string][]string{}
+		statuses []string
+		defaults = map[string]bool{}
+	)
+
+	for _, m := range rows {
+		key := fmt.Sprintf("branch_id=%s, status_konsumen=%s", m.BranchID, m.StatusKonsumen)
+		if _, ok := keyRows[key]; !ok {
+			keys = append(keys, key)
+		}
+		keyRows[key] = append(keyRows[key], fmt.Sprintf("id=%s, income=%d", m.ID, m.Income))
+
+		statuses = appendUnique(statuses, m.StatusKonsumen)
+		if m.BranchID == constant.DEFAULT_BRANCH_ID {
+			defaults[m.StatusKonsumen] = true
+		}
+	}
+
+	for _, key := range keys {
+		if len(keyRows[key]) > 1 {
+			table.Issues = append(table.Issues, response.MappingCheckIssue{
+				Type:        constant.MAPPING_ISSUE_OVERLAP,
+				Dimension:   "branch_id, status_konsumen",
+				Group:       key,
+				Rows:        keyRows[key],
+				Description: "more than one minimal income for the same branch and customer status",
+			})
+		}
+	}
+
+	// branches without their own row fall back to the DEFAULT branch
+	for _, status := range statuses {
+		if !defaults[status] {
+			table.Issues = append(table.Issues, response.MappingCheckIssue{
+				Type:        constant.MAPPING_ISSUE_MISSING_COMBINATION,
+				Dimension:   "branch_id, status_konsumen",
+				Group:       fmt.Sprintf("branch_id=%s, status_konsumen=%s", constant.DEFAULT_BRANCH_ID, status),
+				Description: "no DEFAULT branch row, branches without their own row have no minimal income",
+			})
+		}
+	}
+
+	return
+}
+
+// rangeIssues sorts the ranges of one group and reports the gaps and overlaps between neighbours.
+// step is the smallest increment of the dimension, halfOpen marks ranges matched as start < x <= end.
+func rangeIssues(group, dimension string, ranges []valueRange, step float64, halfOpen bool) (issues []response.MappingCheckIssue) {
+
+	if len(ranges) < 2 {
+		return
+	}
+
+	sorted := make([]valueRange, len(ranges))
+	copy(sorted, ranges)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].start < sorted[j].start
+	})
+
+	reach := sorted[0]
+	for _, cur := range sorted[1:] {
+		if reach.openEnd || cur.start < reach.end || (!halfOpen && cur.start == reach.end) {
+			issues = append(issues, response.MappingCheckIssue{
+				Type:        constant.MAPPING_ISSUE_OVERLAP,
+				Dimension:   dimension,
+				Group:       group,
+				Rows:        []string{reach.row, cur.row},
+				Description: fmt.Sprintf("%s %s overlaps %s", dimension, formatRange(cur), formatRange(reach)),
+			})
+		} else if cur.start > reach.end+step {
+			issues = append(issues, response.MappingCheckIssue{
+				Type:        constant.MAPPING_ISSUE_GAP,
+				Dimension:   dimension,
+				Group:       group,
+				Rows:        []string{reach.row, cur.row},
+				Description: fmt.Sprintf("%s between %s and %s has no row", dimension, formatFloat(reach.end), formatFloat(cur.start)),
+			})
+		}
+
+		if !reach.openEnd && (cur.openEnd || cur.end > reach.end) {
+			reach = cur
+		}
+	}
+
+	return
+}
+
+func elaborateLTVGroup(m entity.MappingElaborateLTV) string {
+	group := fmt.Sprintf("result_pefindo=%s, cluster=%s, bpkb_name_type=%d, status_konsumen=%s, pbk_score=%s, grade_branch=%s",
+		m.ResultPefindo, m.Cluster, m.BPKBNameType, m.StatusKonsumen, m.PbkScore, m.GradeBranch)
+
+	// age_vehicle is only evaluated for tenor >= 36
+	if m.TenorStart >= 36 {
+		group += ", age_vehicle=" + m.AgeVehicle
+	}
+
+	return group
+}
+
+func vehicleAgeRow(m entity.MappingVehicleAge) string {
+	return fmt.Sprintf("vehicle_age=%d-%d, tenor=%d-%d, af=%s-%s, decision=%s",
+		m.VehicleAgeStart, m.VehicleAgeEnd, m.TenorStart, m.TenorEnd, formatFloat(m.AFStart), formatFloat(m.AFEnd), m.Decision)
+}
+
+func incomeRange(start, end, threshold float64) valueRange {
+	v := valueRange{
+		start: start,
+		end:   end,
+		row:   fmt.Sprintf("total_income=%s-%s, dsr_threshold=%s", formatFloat(start), formatFloat(end), formatFloat(threshold)),
+	}
+
+	if end < 0 {
+		v.openEnd = true
+		v.row = fmt.Sprintf("total_income=%s-NULL, dsr_threshold=%s", formatFloat(start), formatFloat(threshold))
+	}
+
+	return v
+}
+
+func unreachable(group, dimension, row, description string) response.MappingCheckIssue {
+	return response.MappingCheckIssue{
+		Type:        constant.MAPPING_ISSUE_UNREACHABLE,
+		Dimension:   dimension,
+		Group:       group,
+		Rows:        []string{row},
+		Description: description,
+	}
+}
+
+func clusterStatusPrefix(cluster string, statuses []string) string {
+	for _, status := range statuses {
+		if strings.HasPrefix(cluster, status+" ") {
+			return status
+		}
+	}
+	return ""
+}
+
+func matchAll(value, expected string) bool {
+	return value == constant.MAPPING_STATUS_KONSUMEN_ALL || value == expected
+}
+
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+func formatRange(v valueRange) string {
+	if v.openEnd {
+		return formatFloat(v.start) + "-NULL"
+	}
+	return formatFloat(v.start) + "-" + formatFloat(v.end)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,27 @@
+package channel
+
+import (
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/httpclient"
+	"strings"
+)
+
+// NewChannels builds the channels listed in names (comma separated), unknown names are ignored
+func NewChannels(names string, httpclient httpclient.HttpClient) (channels []interfaces.Channel) {
+
+	for _, name := range strings.Split(names, ",") {
+		switch strings.ToUpper(strings.TrimSpace(name)) {
+		case constant.NOTIFICATION_CHANNEL_WEBHOOK:
+			channels = append(channels, NewWebhook(httpclient))
+		case constant.NOTIFICATION_CHANNEL_INBOX:
+			channels = append(channels, NewInbox(httpclient))
+		case constant.NOTIFICATION_CHANNEL_SMTP:
+			channels = append(channels, NewSmtp())
+		case constant.NOTIFICATION_CHANNEL_LOCAL:
+			channels = append(channels, NewLocal())
+		}
+	}
+
+	return
+}
@@ -0,0 +1,56 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/httpclient"
+	"os"
+	"strconv"
+)
+
+// inbox stores the notification on the in-app inbox of the recipient
+type inbox struct {
+	httpclient httpclient.HttpClient
+}
+
+func NewInbox(httpclient httpclient.HttpClient) interfaces.Channel {
+	return &inbox{
+		httpclient: httpclient,
+	}
+}
+
+func (i inbox) Name() string {
+	return constant.NOTIFICATION_CHANNEL_INBOX
+}
+
+func (i inbox) Send(ctx context.Context, message entity.NotificationMessage) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	param, _ := json.Marshal(map[string]interface{}{
+		"user_id":     message.Recipient.UserID,
+		"category":    message.EventType,
+		"reference":   message.ProspectID,
+		"title":       message.Subject,
+		"description": message.Body,
+	})
+
+	header := map[string]string{
+		"Authorization": os.Getenv("NOTIFICATION_INBOX_AUTH"),
+	}
+
+	resp, err := i.httpclient.EngineAPI(ctx, constant.NEW_KMB_LOG, os.Getenv("NOTIFICATION_INBOX_URL"), param, header, constant.METHOD_POST, false, 0, timeout, message.ProspectID, "")
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode() != 200 && resp.StatusCode() != 201 {
+		err = fmt.Errorf("%s - Notification Inbox Error %d", constant.ERROR_UPSTREAM, resp.StatusCode())
+	}
+
+	return
+}
@@ -0,0 +1,44 @@
+package channel
+
+import (
+	"context"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"sync"
+)
+
+// Local keeps the messages in memory, used in place of the real transports on tests and local runs
+type Local struct {
+	mu       sync.Mutex
+	messages []entity.NotificationMessage
+}
+
+func NewLocal() *Local {
+	return &Local{}
+}
+
+func (l *Local) Name() string {
+	return constant.NOTIFICATION_CHANNEL_LOCAL
+}
+
+func (l *Local) Send(ctx context.Context, message entity.NotificationMessage) (err error) {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = append(l.messages, message)
+
+	return
+}
+
+// Messages returns a copy of every message sent so far
+func (l *Local) Messages() []entity.NotificationMessage {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	messages := make([]entity.NotificationMessage, len(l.messages))
+	copy(messages, l.messages)
+
+	return messages
+}
@@ -0,0 +1,60 @@
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+type smtpChannel struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSmtp() interfaces.Channel {
+	return &smtpChannel{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     os.Getenv("SMTP_PORT"),
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+func (s smtpChannel) Name() string {
+	return constant.NOTIFICATION_CHANNEL_SMTP
+}
+
+func (s smtpChannel) Send(ctx context.Context, message entity.NotificationMessage) (err error) {
+
+	if message.Recipient.Email == "" {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Email penerima kosong")
+		return
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	mail := strings.Join([]string{
+		fmt.Sprintf("From: %s", s.from),
+		fmt.Sprintf("To: %s", message.Recipient.Email),
+		fmt.Sprintf("Subject: %s", message.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		message.Body,
+	}, "\r\n")
+
+	return smtp.SendMail(s.host+":"+s.port, auth, s.from, []string{message.Recipient.Email}, []byte(mail))
+}
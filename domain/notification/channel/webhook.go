@@ -0,0 +1,57 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/httpclient"
+	"os"
+	"strconv"
+)
+
+type webhook struct {
+	httpclient httpclient.HttpClient
+}
+
+func NewWebhook(httpclient httpclient.HttpClient) interfaces.Channel {
+	return &webhook{
+		httpclient: httpclient,
+	}
+}
+
+func (w webhook) Name() string {
+	return constant.NOTIFICATION_CHANNEL_WEBHOOK
+}
+
+func (w webhook) Send(ctx context.Context, message entity.NotificationMessage) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	param, _ := json.Marshal(map[string]interface{}{
+		"event_type":  message.EventType,
+		"prospect_id": message.ProspectID,
+		"user_id":     message.Recipient.UserID,
+		"user_name":   message.Recipient.UserName,
+		"alias":       message.Recipient.Alias,
+		"subject":     message.Subject,
+		"body":        message.Body,
+	})
+
+	header := map[string]string{
+		"Authorization": os.Getenv("NOTIFICATION_WEBHOOK_AUTH"),
+	}
+
+	resp, err := w.httpclient.EngineAPI(ctx, constant.NEW_KMB_LOG, os.Getenv("NOTIFICATION_WEBHOOK_URL"), param, header, constant.METHOD_POST, false, 0, timeout, message.ProspectID, "")
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode() != 200 && resp.StatusCode() != 201 {
+		err = fmt.Errorf("%s - Notification Webhook Error %d", constant.ERROR_UPSTREAM, resp.StatusCode())
+	}
+
+	return
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+)
+
+type Repository interface {
+	GetOrderBranch(prospectID string) (branchID string, err error)
+	GetNotificationTemplate(eventType string) (data entity.MappingNotificationTemplate, err error)
+	GetNotificationRecipients(alias, branchID string) (data []entity.MappingNotificationRecipient, err error)
+	GetNotificationPreferences(userIDs []string, eventType string) (data []entity.MappingNotificationPreference, err error)
+	SaveNotification(data entity.TrxNotification) (err error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"los-kmb-api/models/entity"
+)
+
+type Usecase interface {
+	Notify(ctx context.Context, event entity.NotificationEvent) (sent int, err error)
+}
+
+// Channel delivers a rendered notification to a single recipient
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, message entity.NotificationMessage) (err error)
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	newKmb *gorm.DB
+}
+
+func NewRepository(newKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		newKmb: newKmb,
+	}
+}
+
+func (r repoHandler) GetOrderBranch(prospectID string) (branchID string, err error) {
+
+	var (
+		x    sql.TxOptions
+		data entity.TrxMaster
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.newKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT BranchID FROM trx_master WITH (nolock) WHERE ProspectID = ?", prospectID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	branchID = data.BranchID
+
+	return
+}
+
+func (r repoHandler) GetNotificationTemplate(eventType string) (data entity.MappingNotificationTemplate, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.newKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT event_type, subject, body, is_active FROM m_notification_template WITH (nolock) WHERE event_type = ? AND is_active = 1", eventType).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+// GetNotificationRecipients returns the active users of the alias on the branch, a recipient without branch receives every branch
+func (r repoHandler) GetNotificationRecipients(alias, branchID string) (data []entity.MappingNotificationRecipient, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.newKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT user_id, user_name, email, alias, BranchID, is_active FROM m_notification_recipient WITH (nolock)
+		WHERE alias = ? AND is_active = 1 AND (BranchID = ? OR ISNULL(BranchID, '') = '')`, alias, branchID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetNotificationPreferences(userIDs []string, eventType string) (data []entity.MappingNotificationPreference, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.newKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT user_id, event_type, channel, is_muted, updated_by, updated_at FROM m_notification_preference WITH (nolock) WHERE user_id IN (?) AND event_type = ?", userIDs, eventType).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) SaveNotification(data entity.TrxNotification) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.newKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Create(&data).Error
+
+	return
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type usecase struct {
+	repository interfaces.Repository
+	channels   []interfaces.Channel
+}
+
+func NewUsecase(repository interfaces.Repository, channels []interfaces.Channel) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+		channels:   channels,
+	}
+}
+
+type notificationData struct {
+	EventType  string
+	ProspectID string
+	BranchID   string
+	Alias      string
+	Recipient  entity.MappingNotificationRecipient
+	Data       map[string]interface{}
+}
+
+// Notify renders the template of the event for every recipient of the alias on the branch and sends it on each channel,
+// a channel muted by the recipient is skipped and every delivery is logged to trx_notification
+func (u usecase) Notify(ctx context.Context, event entity.NotificationEvent) (sent int, err error) {
+
+	if len(u.channels) == 0 || event.Alias == "" {
+		return
+	}
+
+	if event.BranchID == "" && event.ProspectID != "" {
+		if event.BranchID, err = u.repository.GetOrderBranch(event.ProspectID); err != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Order Branch Error")
+			return
+		}
+	}
+
+	mapping, err := u.repository.GetNotificationTemplate(event.EventType)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Notification Template Error")
+		return
+	}
+
+	subjectTemplate, err := template.New("subject").Parse(mapping.Subject)
+	if err != nil {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Template subject notifikasi tidak valid")
+		return
+	}
+
+	bodyTemplate, err := template.New("body").Parse(mapping.Body)
+	if err != nil {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Template body notifikasi tidak valid")
+		return
+	}
+
+	recipients, err := u.repository.GetNotificationRecipients(event.Alias, event.BranchID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Notification Recipients Error")
+		return
+	}
+
+	if len(recipients) == 0 {
+		return
+	}
+
+	userIDs := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		userIDs[i] = recipient.UserID
+	}
+
+	preferences, err := u.repository.GetNotificationPreferences(userIDs, event.EventType)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Notification Preferences Error")
+		return
+	}
+
+	muted := map[string]bool{}
+	for _, preference := range preferences {
+		if preference.IsMuted {
+			muted[preference.UserID+"|"+preference.Channel] = true
+		}
+	}
+
+	for _, recipient := range recipients {
+
+		if muted[recipient.UserID+"|"+constant.NOTIFICATION_CHANNEL_ALL] {
+			continue
+		}
+
+		data := notificationData{
+			EventType:  event.EventType,
+			ProspectID: event.ProspectID,
+			BranchID:   event.BranchID,
+			Alias:      event.Alias,
+			Recipient:  recipient,
+			Data:       event.Data,
+		}
+
+		var subject, body bytes.Buffer
+		if err = subjectTemplate.Execute(&subject, data); err != nil {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Render subject notifikasi gagal")
+			return
+		}
+
+		if err = bodyTemplate.Execute(&body, data); err != nil {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - Render body notifikasi gagal")
+			return
+		}
+
+		message := entity.NotificationMessage{
+			EventType:  event.EventType,
+			ProspectID: event.ProspectID,
+			Recipient:  recipient,
+			Subject:    subject.String(),
+			Body:       body.String(),
+		}
+
+		for _, channel := range u.channels {
+
+			if muted[recipient.UserID+"|"+channel.Name()] {
+				continue
+			}
+
+			record := entity.TrxNotification{
+				ID:         uuid.New().String(),
+				EventType:  event.EventType,
+				ProspectID: event.ProspectID,
+				UserID:     recipient.UserID,
+				Channel:    channel.Name(),
+				Subject:    message.Subject,
+				Body:       message.Body,
+				Status:     constant.NOTIFICATION_STATUS_SENT,
+				CreatedAt:  time.Now(),
+			}
+
+			if errSend := channel.Send(ctx, message); errSend != nil {
+				record.Status = constant.NOTIFICATION_STATUS_FAILED
+				record.Error = errSend.Error()
+			} else {
+				sent++
+			}
+
+			if errSave := u.repository.SaveNotification(record); errSave != nil {
+				err = errors.New(constant.ERROR_UPSTREAM + " - Save Notification Error")
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"los-kmb-api/domain/notification/channel"
+	"los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/domain/notification/mocks"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotify(t *testing.T) {
+
+	event := entity.NotificationEvent{
+		EventType:  constant.NOTIFICATION_APPROVAL_ROUTED,
+		ProspectID: "SAL-1140024080800004",
+		Alias:      constant.CMO_AGENT,
+		Data: map[string]interface{}{
+			"decision": "APR",
+		},
+	}
+
+	template := entity.MappingNotificationTemplate{
+		EventType: constant.NOTIFICATION_APPROVAL_ROUTED,
+		Subject:   "Order {{.ProspectID}}",
+		Body:      "Halo {{.Recipient.UserName}}, order {{.ProspectID}} cabang {{.BranchID}} {{.Data.decision}}",
+		IsActive:  true,
+	}
+
+	recipients := []entity.MappingNotificationRecipient{
+		{UserID: "U001", UserName: "BUDI", Alias: constant.CMO_AGENT, BranchID: "426", IsActive: true},
+		{UserID: "U002", UserName: "SITI", Alias: constant.CMO_AGENT, BranchID: "426", IsActive: true},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		mockRepository.On("GetOrderBranch", event.ProspectID).Return("426", nil).Once()
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(template, nil).Once()
+		mockRepository.On("GetNotificationRecipients", event.Alias, "426").Return(recipients, nil).Once()
+		mockRepository.On("GetNotificationPreferences", []string{"U001", "U002"}, event.EventType).Return([]entity.MappingNotificationPreference{}, nil).Once()
+		mockRepository.On("SaveNotification", mock.MatchedBy(func(data entity.TrxNotification) bool {
+			return data.Channel == constant.NOTIFICATION_CHANNEL_LOCAL && data.Status == constant.NOTIFICATION_STATUS_SENT
+		})).Return(nil).Twice()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		sent, err := usecase.Notify(context.Background(), event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+
+		messages := local.Messages()
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "Order SAL-1140024080800004", messages[0].Subject)
+		assert.Equal(t, "Halo BUDI, order SAL-1140024080800004 cabang 426 APR", messages[0].Body)
+		assert.Equal(t, "U002", messages[1].Recipient.UserID)
+	})
+
+	t.Run("skip muted recipient", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		preferences := []entity.MappingNotificationPreference{
+			{UserID: "U001", EventType: event.EventType, Channel: constant.NOTIFICATION_CHANNEL_LOCAL, IsMuted: true},
+			{UserID: "U002", EventType: event.EventType, Channel: constant.NOTIFICATION_CHANNEL_ALL, IsMuted: false},
+		}
+
+		withBranch := event
+		withBranch.BranchID = "426"
+
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(template, nil).Once()
+		mockRepository.On("GetNotificationRecipients", event.Alias, "426").Return(recipients, nil).Once()
+		mockRepository.On("GetNotificationPreferences", []string{"U001", "U002"}, event.EventType).Return(preferences, nil).Once()
+		mockRepository.On("SaveNotification", mock.Anything).Return(nil).Once()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		sent, err := usecase.Notify(context.Background(), withBranch)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+
+		messages := local.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "U002", messages[0].Recipient.UserID)
+	})
+
+	t.Run("skip recipient muted on all channels", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		preferences := []entity.MappingNotificationPreference{
+			{UserID: "U001", EventType: event.EventType, Channel: constant.NOTIFICATION_CHANNEL_ALL, IsMuted: true},
+			{UserID: "U002", EventType: event.EventType, Channel: constant.NOTIFICATION_CHANNEL_ALL, IsMuted: true},
+		}
+
+		mockRepository.On("GetOrderBranch", event.ProspectID).Return("426", nil).Once()
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(template, nil).Once()
+		mockRepository.On("GetNotificationRecipients", event.Alias, "426").Return(recipients, nil).Once()
+		mockRepository.On("GetNotificationPreferences", []string{"U001", "U002"}, event.EventType).Return(preferences, nil).Once()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		sent, err := usecase.Notify(context.Background(), event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Empty(t, local.Messages())
+	})
+
+	t.Run("no channel", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+
+		usecase := NewUsecase(mockRepository, nil)
+
+		sent, err := usecase.Notify(context.Background(), event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+	})
+
+	t.Run("no recipient", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		mockRepository.On("GetOrderBranch", event.ProspectID).Return("426", nil).Once()
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(template, nil).Once()
+		mockRepository.On("GetNotificationRecipients", event.Alias, "426").Return([]entity.MappingNotificationRecipient{}, nil).Once()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		sent, err := usecase.Notify(context.Background(), event)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Empty(t, local.Messages())
+	})
+
+	t.Run("error get template", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		mockRepository.On("GetOrderBranch", event.ProspectID).Return("426", nil).Once()
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(entity.MappingNotificationTemplate{}, errors.New(constant.RECORD_NOT_FOUND)).Once()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		_, err := usecase.Notify(context.Background(), event)
+
+		assert.EqualError(t, err, constant.ERROR_UPSTREAM+" - Get Notification Template Error")
+		assert.Empty(t, local.Messages())
+	})
+
+	t.Run("error invalid template", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		invalid := template
+		invalid.Body = "Halo {{.Recipient.UserName"
+
+		mockRepository.On("GetOrderBranch", event.ProspectID).Return("426", nil).Once()
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(invalid, nil).Once()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		_, err := usecase.Notify(context.Background(), event)
+
+		assert.EqualError(t, err, constant.ERROR_BAD_REQUEST+" - Template body notifikasi tidak valid")
+		assert.Empty(t, local.Messages())
+	})
+
+	t.Run("error save notification", func(t *testing.T) {
+		mockRepository := mocks.NewRepository(t)
+		local := channel.NewLocal()
+
+		mockRepository.On("GetOrderBranch", event.ProspectID).Return("426", nil).Once()
+		mockRepository.On("GetNotificationTemplate", event.EventType).Return(template, nil).Once()
+		mockRepository.On("GetNotificationRecipients", event.Alias, "426").Return(recipients[:1], nil).Once()
+		mockRepository.On("GetNotificationPreferences", []string{"U001"}, event.EventType).Return([]entity.MappingNotificationPreference{}, nil).Once()
+		mockRepository.On("SaveNotification", mock.Anything).Return(errors.New("connection reset")).Once()
+
+		usecase := NewUsecase(mockRepository, []interfaces.Channel{local})
+
+		sent, err := usecase.Notify(context.Background(), event)
+
+		assert.EqualError(t, err, constant.ERROR_UPSTREAM+" - Save Notification Error")
+		assert.Equal(t, 1, sent)
+		assert.Len(t, local.Messages(), 1)
+	})
+}
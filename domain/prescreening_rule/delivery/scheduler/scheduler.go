@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/prescreening_rule/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"time"
+)
+
+// Run evaluates the prescreening auto decision rules every interval until ctx is done
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "PRESCREENING_RULE_SCHEDULER",
+		MsgLogFile: "LOS - Prescreening Rule Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+	}, func(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+		passed, flagged, err := usecase.EvaluatePrescreening(ctx, accessToken)
+		return map[string]interface{}{"passed": passed, "flagged": flagged}, err
+	})
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+)
+
+type Repository interface {
+	GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error)
+	ClaimPrescreeningCandidates(limit int) (data []entity.PrescreeningCandidate, err error)
+	SaveAutoDecision(decision entity.TrxPrescreeningAutoDecision) (err error)
+	SaveAutoPass(decision entity.TrxPrescreeningAutoDecision, prescreening entity.TrxPrescreening, detail entity.TrxDetail, status entity.TrxStatus) (err error)
+	ClaimUnpublishedAutoPass(limit int) (data []entity.TrxPrescreeningAutoDecision, err error)
+	ResetAutoPassPublished(id string) (err error)
+}
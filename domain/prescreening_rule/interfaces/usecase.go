@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"context"
+)
+
+type Usecase interface {
+	EvaluatePrescreening(ctx context.Context, accessToken string) (passed, flagged int, err error)
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/prescreening_rule/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	losDB  *gorm.DB
+	NewKmb *gorm.DB
+}
+
+func NewRepository(kpLos, NewKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		losDB:  kpLos,
+		NewKmb: NewKmb,
+	}
+}
+
+func (r repoHandler) GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error) {
+
+	if err = r.losDB.Raw("SELECT [value] FROM app_config WITH (nolock) WHERE group_name = ? AND lob = ? AND [key] = ? AND is_active = 1", groupName, lob, key).Scan(&appConfig).Error; err != nil {
+		return
+	}
+
+	return
+}
+
+// ClaimPrescreeningCandidates takes the oldest orders waiting in prescreening that have not been evaluated by the rules yet,
+// every order gets a PENDING decision in the same transaction so an order locked or claimed by another replica is
+// skipped. photos holds the photo ids of the order separated by comma
+func (r repoHandler) ClaimPrescreeningCandidates(limit int) (data []entity.PrescreeningCandidate, err error) {
+
+	var prospectIDs []struct {
+		ProspectID string `gorm:"column:ProspectID"`
+	}
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	if err = db.Raw(fmt.Sprintf(`SELECT TOP (%d) tst.ProspectID FROM trx_status tst WITH (updlock, readpast, rowlock)
+		WHERE tst.activity = ? AND tst.source_decision = ?
+		AND NOT EXISTS (SELECT 1 FROM trx_prescreening_auto_decision tpad WHERE tpad.ProspectID = tst.ProspectID)
+		ORDER BY tst.created_at ASC`, limit), constant.ACTIVITY_UNPROCESS, constant.PRESCREENING).Scan(&prospectIDs).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	if len(prospectIDs) == 0 {
+		return
+	}
+
+	var decisionIDs []string
+
+	for _, row := range prospectIDs {
+		decision := entity.TrxPrescreeningAutoDecision{
+			ID:         utils.GenerateUUID(),
+			ProspectID: row.ProspectID,
+			Result:     constant.PRESCREENING_RULE_PENDING,
+			CreatedAt:  time.Now(),
+		}
+
+		if err = db.Create(&decision).Error; err != nil {
+			return
+		}
+
+		decisionIDs = append(decisionIDs, decision.ID)
+	}
+
+	err = db.Raw(`SELECT tpad.id AS decision_id, tpad.ProspectID,
+		ISNULL(tf.customer_status, '') AS customer_status, ISNULL(tf.customer_segment, '') AS customer_segment,
+		ISNULL(ta.OTR, 0) AS OTR, ISNULL(ta.NTF, 0) AS NTF,
+		(SELECT COUNT(1) FROM trx_surveyor ts WITH (nolock) WHERE ts.ProspectID = tpad.ProspectID) AS total_surveyor,
+		ISNULL(STUFF((SELECT ',' + tcp.photo_id FROM trx_customer_photo tcp WITH (nolock) WHERE tcp.ProspectID = tpad.ProspectID FOR XML PATH('')), 1, 1, ''), '') AS photos
+		FROM trx_prescreening_auto_decision tpad
+		LEFT JOIN trx_filtering tf WITH (nolock) ON tpad.ProspectID = tf.prospect_id
+		LEFT JOIN trx_apk ta WITH (nolock) ON tpad.ProspectID = ta.ProspectID
+		WHERE tpad.id IN (?)`, decisionIDs).Scan(&data).Error
+
+	return
+}
+
+func (r repoHandler) SaveAutoDecision(decision entity.TrxPrescreeningAutoDecision) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Model(&decision).Where("id = ?", decision.ID).Updates(map[string]interface{}{
+		"result":    decision.Result,
+		"rule_code": decision.RuleCode,
+		"note":      decision.Note,
+	}).Error
+
+	return
+}
+
+// SaveAutoPass moves the order out of prescreening the same way a manual approve does, ERROR_ROWS_AFFECTED is returned
+// when the order has been reviewed in the meantime
+func (r repoHandler) SaveAutoPass(decision entity.TrxPrescreeningAutoDecision, prescreening entity.TrxPrescreening, detail entity.TrxDetail, status entity.TrxStatus) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	prescreening.CreatedAt = now
+	detail.CreatedAt = now
+	status.CreatedAt = now
+
+	result := db.Model(&status).Where("ProspectID = ? AND activity = ? AND source_decision = ?", status.ProspectID, constant.ACTIVITY_UNPROCESS, constant.PRESCREENING).Updates(status)
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = errors.New(constant.ERROR_ROWS_AFFECTED)
+		return
+	}
+
+	if err = db.Create(&detail).Error; err != nil {
+		return
+	}
+
+	if err = db.Create(&prescreening).Error; err != nil {
+		return
+	}
+
+	err = db.Model(&decision).Where("id = ?", decision.ID).Updates(map[string]interface{}{
+		"result":    decision.Result,
+		"rule_code": decision.RuleCode,
+		"note":      decision.Note,
+	}).Error
+
+	return
+}
+
+// ClaimUnpublishedAutoPass marks a batch of passed orders whose after prescreening event has not been published yet,
+// rows claimed by another replica are skipped
+func (r repoHandler) ClaimUnpublishedAutoPass(limit int) (data []entity.TrxPrescreeningAutoDecision, err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	query := fmt.Sprintf(`WITH claim AS (
+			SELECT TOP (%d) * FROM trx_prescreening_auto_decision WITH (updlock, readpast, rowlock)
+			WHERE result = ? AND published_at IS NULL
+			ORDER BY created_at ASC
+		)
+		UPDATE claim SET published_at = GETDATE()
+		OUTPUT inserted.*`, limit)
+
+	if err = db.Raw(query, constant.PRESCREENING_RULE_PASS).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// ResetAutoPassPublished gives a claimed row back to the next run after its event failed to publish
+func (r repoHandler) ResetAutoPassPublished(id string) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec("UPDATE trx_prescreening_auto_decision SET published_at = NULL WHERE id = ?", id).Error
+
+	return
+}
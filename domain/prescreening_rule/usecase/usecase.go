@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"los-kmb-api/domain/prescreening_rule/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common/platformevent"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"strings"
+)
+
+type usecase struct {
+	repository interfaces.Repository
+	producer   platformevent.PlatformEventInterface
+}
+
+func NewUsecase(repository interfaces.Repository, producer platformevent.PlatformEventInterface) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+		producer:   producer,
+	}
+}
+
+// EvaluatePrescreening runs the auto decision rules on the orders that landed in prescreening, a passed order continues
+// to dupcheck, a flagged order and an order without matching rule stay in the manual review queue. An order left
+// PENDING by a replica that stopped mid run stays in the manual review queue as well
+func (u usecase) EvaluatePrescreening(ctx context.Context, accessToken string) (passed, flagged int, err error) {
+
+	var ruleConfig response.PrescreeningRuleConfig
+
+	configData, err := u.repository.GetConfig(constant.PRESCREENING_RULE_CONFIG_GROUP, constant.LOB_KMB_OFF, constant.PRESCREENING_RULE_CONFIG_KEY)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Prescreening Rule Config Error")
+		return
+	}
+
+	if err = json.Unmarshal([]byte(configData.Value), &ruleConfig); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error Unmarshal Get Prescreening Rule Config")
+		return
+	}
+
+	if !ruleConfig.Data.Enabled || len(ruleConfig.Data.Rules) == 0 {
+		return
+	}
+
+	// events of an earlier run that failed to publish go first
+	if err = u.publishAutoPass(ctx, accessToken); err != nil {
+		return
+	}
+
+	candidates, err := u.repository.ClaimPrescreeningCandidates(constant.PRESCREENING_RULE_BATCH_SIZE)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Claim Prescreening Candidates error")
+		return
+	}
+
+	for _, candidate := range candidates {
+
+		decision := entity.TrxPrescreeningAutoDecision{
+			ID:         candidate.DecisionID,
+			ProspectID: candidate.ProspectID,
+			Result:     constant.PRESCREENING_RULE_MANUAL,
+		}
+
+		rule, matched := matchPrescreeningRule(ruleConfig.Data.Rules, candidate)
+		if matched {
+			decision.Result = rule.Action
+			decision.RuleCode = rule.Code
+			decision.Note = rule.Description
+		}
+
+		if decision.Result != constant.PRESCREENING_RULE_PASS {
+			if err = u.repository.SaveAutoDecision(decision); err != nil {
+				err = errors.New(constant.ERROR_UPSTREAM + " - Save Prescreening Auto Decision error")
+				return
+			}
+
+			if decision.Result == constant.PRESCREENING_RULE_FLAG {
+				flagged++
+			}
+			continue
+		}
+
+		if err = u.pass(decision); err != nil {
+			// reviewed manually while the rules were running, it is no longer ours to decide
+			if err.Error() == constant.ERROR_ROWS_AFFECTED {
+				err = nil
+				continue
+			}
+			err = errors.New(constant.ERROR_UPSTREAM + " - Save Prescreening Auto Pass error")
+			return
+		}
+
+		passed++
+	}
+
+	err = u.publishAutoPass(ctx, accessToken)
+
+	return
+}
+
+// pass approves the order like the prescreening review does, the order is sent to dupcheck by publishAutoPass
+func (u usecase) pass(decision entity.TrxPrescreeningAutoDecision) (err error) {
+
+	reason := constant.PRESCREENING_RULE_REASON_PASS + decision.RuleCode
+
+	trxPrescreening := entity.TrxPrescreening{
+		ProspectID: decision.ProspectID,
+		Decision:   constant.DB_DECISION_APR,
+		Reason:     reason,
+		CreatedBy:  constant.SYSTEM,
+		DecisionBy: constant.PRESCREENING_RULE_DECISION_BY,
+	}
+
+	trxDetail := entity.TrxDetail{
+		ProspectID:     decision.ProspectID,
+		StatusProcess:  constant.STATUS_ONPROCESS,
+		Activity:       constant.ACTIVITY_PROCESS,
+		Decision:       constant.DB_DECISION_PASS,
+		RuleCode:       constant.CODE_PASS_PRESCREENING,
+		SourceDecision: constant.PRESCREENING,
+		NextStep:       constant.SOURCE_DECISION_DUPCHECK,
+		Info:           constant.REASON_SESUAI,
+		CreatedBy:      constant.SYSTEM,
+		Reason:         reason,
+	}
+
+	trxStatus := entity.TrxStatus{
+		ProspectID:     decision.ProspectID,
+		StatusProcess:  constant.STATUS_ONPROCESS,
+		Activity:       constant.ACTIVITY_UNPROCESS,
+		Decision:       constant.DB_DECISION_CREDIT_PROCESS,
+		SourceDecision: constant.SOURCE_DECISION_DUPCHECK,
+	}
+
+	err = u.repository.SaveAutoPass(decision, trxPrescreening, trxDetail, trxStatus)
+
+	return
+}
+
+// publishAutoPass sends the passed orders to dupcheck, a row is marked published before its event goes out and is
+// given back to the next run when the publish fails
+func (u usecase) publishAutoPass(ctx context.Context, accessToken string) (err error) {
+
+	decisions, err := u.repository.ClaimUnpublishedAutoPass(constant.PRESCREENING_RULE_BATCH_SIZE)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Claim Prescreening Auto Pass error")
+		return
+	}
+
+	for _, decision := range decisions {
+
+		reqAfterPrescreening := request.AfterPrescreening{
+			ProspectID: decision.ProspectID,
+		}
+
+		if errPublish := u.producer.PublishEvent(ctx, accessToken, constant.TOPIC_SUBMISSION_LOS, constant.KEY_PREFIX_AFTER_PRESCREENING, decision.ProspectID, utils.StructToMap(reqAfterPrescreening), 0); errPublish != nil {
+			if err = u.repository.ResetAutoPassPublished(decision.ID); err != nil {
+				err = errors.New(constant.ERROR_UPSTREAM + " - Reset Prescreening Auto Pass error")
+				return
+			}
+			err = errors.New(constant.ERROR_UPSTREAM + " - Publish After Prescreening error")
+		}
+	}
+
+	return
+}
+
+// matchPrescreeningRule returns the first rule whose conditions all hold for the order
+func matchPrescreeningRule(rules []response.PrescreeningRule, candidate entity.PrescreeningCandidate) (rule response.PrescreeningRule, matched bool) {
+
+	for _, rule := range rules {
+
+		if rule.Action != constant.PRESCREENING_RULE_PASS && rule.Action != constant.PRESCREENING_RULE_FLAG {
+			continue
+		}
+
+		if len(rule.Conditions) == 0 {
+			continue
+		}
+
+		matched = true
+		for _, condition := range rule.Conditions {
+			if checkPrescreeningCondition(condition, candidate) == condition.Negate {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return rule, true
+		}
+	}
+
+	return
+}
+
+// checkPrescreeningCondition evaluates a single condition, an unknown type never holds so a misconfigured rule keeps the order manual
+func checkPrescreeningCondition(condition response.PrescreeningRuleCondition, candidate entity.PrescreeningCandidate) bool {
+
+	switch condition.Type {
+	case constant.PRESCREENING_RULE_DOCUMENT_COMPLETE:
+		photos := strings.Split(candidate.Photos, ",")
+		for _, photo := range condition.Photos {
+			if !utils.Contains(photos, photo) {
+				return false
+			}
+		}
+		return len(condition.Photos) > 0
+
+	case constant.PRESCREENING_RULE_SURVEYOR_PRESENT:
+		return candidate.TotalSurveyor > 0
+
+	case constant.PRESCREENING_RULE_CUSTOMER_SEGMENT:
+		if len(condition.CustomerStatus) == 0 && len(condition.CustomerSegment) == 0 {
+			return false
+		}
+		if len(condition.CustomerStatus) > 0 && !utils.Contains(condition.CustomerStatus, candidate.CustomerStatus) {
+			return false
+		}
+		if len(condition.CustomerSegment) > 0 && !utils.Contains(condition.CustomerSegment, candidate.CustomerSegment) {
+			return false
+		}
+		return true
+
+	case constant.PRESCREENING_RULE_AMOUNT:
+		var amount float64
+		switch condition.Field {
+		case constant.PRESCREENING_RULE_AMOUNT_OTR:
+			amount = candidate.OTR
+		case constant.PRESCREENING_RULE_AMOUNT_NTF:
+			amount = candidate.NTF
+		default:
+			return false
+		}
+		if condition.Min == nil && condition.Max == nil {
+			return false
+		}
+		if condition.Min != nil && amount < *condition.Min {
+			return false
+		}
+		if condition.Max != nil && amount > *condition.Max {
+			return false
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPrescreeningCondition(t *testing.T) {
+
+	amount := func(value float64) *float64 {
+		return &value
+	}
+
+	candidate := entity.PrescreeningCandidate{
+		ProspectID:      "SAL-1140024080800004",
+		CustomerStatus:  constant.STATUS_KONSUMEN_RO,
+		CustomerSegment: "REGULAR",
+		OTR:             25000000,
+		NTF:             18000000,
+		TotalSurveyor:   1,
+		Photos:          "KTP,SELFIE,STNK",
+	}
+
+	testcases := []struct {
+		name      string
+		condition response.PrescreeningRuleCondition
+		candidate entity.PrescreeningCandidate
+		expected  bool
+	}{
+		{name: "document complete", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_DOCUMENT_COMPLETE, Photos: []string{"KTP", "SELFIE"}}, candidate: candidate, expected: true},
+		{name: "document missing", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_DOCUMENT_COMPLETE, Photos: []string{"KTP", "KK"}}, candidate: candidate, expected: false},
+		{name: "document without photos configured", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_DOCUMENT_COMPLETE}, candidate: candidate, expected: false},
+		{name: "surveyor present", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_SURVEYOR_PRESENT}, candidate: candidate, expected: true},
+		{name: "surveyor absent", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_SURVEYOR_PRESENT}, candidate: entity.PrescreeningCandidate{}, expected: false},
+		{name: "customer status", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_CUSTOMER_SEGMENT, CustomerStatus: []string{constant.STATUS_KONSUMEN_RO, constant.STATUS_KONSUMEN_AO}}, candidate: candidate, expected: true},
+		{name: "customer status and segment", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_CUSTOMER_SEGMENT, CustomerStatus: []string{constant.STATUS_KONSUMEN_RO}, CustomerSegment: []string{"PRIME"}}, candidate: candidate, expected: false},
+		{name: "customer without status and segment configured", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_CUSTOMER_SEGMENT}, candidate: candidate, expected: false},
+		{name: "otr within range", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_AMOUNT, Field: constant.PRESCREENING_RULE_AMOUNT_OTR, Min: amount(10000000), Max: amount(25000000)}, candidate: candidate, expected: true},
+		{name: "ntf above max", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_AMOUNT, Field: constant.PRESCREENING_RULE_AMOUNT_NTF, Max: amount(15000000)}, candidate: candidate, expected: false},
+		{name: "ntf below min", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_AMOUNT, Field: constant.PRESCREENING_RULE_AMOUNT_NTF, Min: amount(20000000)}, candidate: candidate, expected: false},
+		{name: "amount without range", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_AMOUNT, Field: constant.PRESCREENING_RULE_AMOUNT_OTR}, candidate: candidate, expected: false},
+		{name: "amount of unknown field", condition: response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_AMOUNT, Field: "DP", Min: amount(0)}, candidate: candidate, expected: false},
+		{name: "unknown type", condition: response.PrescreeningRuleCondition{Type: "SCORE"}, candidate: candidate, expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, checkPrescreeningCondition(tc.condition, tc.candidate))
+		})
+	}
+}
+
+func TestMatchPrescreeningRule(t *testing.T) {
+
+	complete := response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_DOCUMENT_COMPLETE, Photos: []string{"KTP", "SELFIE"}}
+	surveyor := response.PrescreeningRuleCondition{Type: constant.PRESCREENING_RULE_SURVEYOR_PRESENT}
+	incomplete := complete
+	incomplete.Negate = true
+
+	flag := response.PrescreeningRule{Code: "FLAG_DOC", Action: constant.PRESCREENING_RULE_FLAG, Conditions: []response.PrescreeningRuleCondition{incomplete}}
+	pass := response.PrescreeningRule{Code: "PASS_RO", Action: constant.PRESCREENING_RULE_PASS, Conditions: []response.PrescreeningRuleCondition{complete, surveyor}}
+
+	ready := entity.PrescreeningCandidate{TotalSurveyor: 1, Photos: "KTP,SELFIE"}
+	noSurveyor := entity.PrescreeningCandidate{Photos: "KTP,SELFIE"}
+	noPhoto := entity.PrescreeningCandidate{TotalSurveyor: 1, Photos: "KTP"}
+
+	testcases := []struct {
+		name      string
+		rules     []response.PrescreeningRule
+		candidate entity.PrescreeningCandidate
+		code      string
+		matched   bool
+	}{
+		{name: "all conditions hold", rules: []response.PrescreeningRule{flag, pass}, candidate: ready, code: "PASS_RO", matched: true},
+		{name: "negated condition flags", rules: []response.PrescreeningRule{flag, pass}, candidate: noPhoto, code: "FLAG_DOC", matched: true},
+		{name: "first matching rule wins", rules: []response.PrescreeningRule{pass, {Code: "PASS_ANY", Action: constant.PRESCREENING_RULE_PASS, Conditions: []response.PrescreeningRuleCondition{surveyor}}}, candidate: ready, code: "PASS_RO", matched: true},
+		{name: "one condition fails", rules: []response.PrescreeningRule{flag, pass}, candidate: noSurveyor, matched: false},
+		{name: "rule without conditions is skipped", rules: []response.PrescreeningRule{{Code: "EMPTY", Action: constant.PRESCREENING_RULE_PASS}}, candidate: ready, matched: false},
+		{name: "rule with unknown action is skipped", rules: []response.PrescreeningRule{{Code: "REJECT", Action: "REJECT", Conditions: []response.PrescreeningRuleCondition{surveyor}}}, candidate: ready, matched: false},
+		{name: "no rules", candidate: ready, matched: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			rule, matched := matchPrescreeningRule(tc.rules, tc.candidate)
+
+			assert.Equal(t, tc.matched, matched)
+			assert.Equal(t, tc.code, rule.Code)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package eventhandlers
 import (
 	"context"
 	"encoding/base64"
+	cmsInterfaces "los-kmb-api/domain/cms/interfaces"
 	"los-kmb-api/domain/principle/interfaces"
 	"los-kmb-api/middlewares"
 	"los-kmb-api/models/entity"
@@ -24,15 +25,17 @@ type handlers struct {
 	validator  *common.Validator
 	producer   platformevent.PlatformEventInterface
 	Json       common.JSON
+	cmsUsecase cmsInterfaces.Usecase
 }
 
-func NewServicePrinciple(app *platformevent.ConsumerRouter, repository interfaces.Repository, usecase interfaces.Usecase, validator *common.Validator, producer platformevent.PlatformEventInterface, json common.JSON) {
+func NewServicePrinciple(app *platformevent.ConsumerRouter, repository interfaces.Repository, usecase interfaces.Usecase, validator *common.Validator, producer platformevent.PlatformEventInterface, json common.JSON, cmsUsecase cmsInterfaces.Usecase) {
 	handler := handlers{
 		usecase:    usecase,
 		repository: repository,
 		validator:  validator,
 		producer:   producer,
 		Json:       json,
+		cmsUsecase: cmsUsecase,
 	}
 	app.Handle("new_kmb_status_update", handler.PrincipleUpdateStatus)
 }
@@ -69,6 +72,12 @@ func (h handlers) PrincipleUpdateStatus(ctx context.Context, event event.Event)
 		},
 	})
 
+	// an order cancelled in sally gives back the quota deviasi it booked, a failed release is returned at the end so the
+	// consumer retries it, the other updates are safe to run again
+	if req.OrderStatus == constant.PRINCIPLE_STATUS_CANCEL_SALLY {
+		_, err = h.cmsUsecase.ReleaseQuotaDeviasi(ctx, req.ProspectID, constant.QUOTA_REASON_CANCEL, constant.SYSTEM)
+	}
+
 	principleData, _ = h.repository.GetPrincipleStepOne(req.ProspectID)
 	if principleData != (entity.TrxPrincipleStepOne{}) {
 		if req.OrderStatus == constant.PRINCIPLE_STATUS_CANCEL_SALLY {
@@ -83,16 +92,17 @@ func (h handlers) PrincipleUpdateStatus(ctx context.Context, event event.Event)
 		}
 	}
 
-	return nil
+	return
 }
 
-func NewService2Wilen(app *platformevent.ConsumerRouter, repository interfaces.Repository, usecase interfaces.Usecase, validator *common.Validator, producer platformevent.PlatformEventInterface, json common.JSON) {
+func NewService2Wilen(app *platformevent.ConsumerRouter, repository interfaces.Repository, usecase interfaces.Usecase, validator *common.Validator, producer platformevent.PlatformEventInterface, json common.JSON, cmsUsecase cmsInterfaces.Usecase) {
 	handler := handlers{
 		usecase:    usecase,
 		repository: repository,
 		validator:  validator,
 		producer:   producer,
 		Json:       json,
+		cmsUsecase: cmsUsecase,
 	}
 	app.Handle(constant.KEY_PREFIX_CANCEL_ORDER_2WILEN, handler.CancelOrder2Wilen)
 }
@@ -128,6 +138,11 @@ func (h handlers) CancelOrder2Wilen(ctx context.Context, event event.Event) (err
 		},
 	})
 
+	// a failed release is returned at the end so the consumer retries it
+	if req.OrderStatus == constant.PRINCIPLE_STATUS_CANCEL_SALLY || req.OrderStatus == constant.STATUS_KPM_CANCEL_2WILEN {
+		_, err = h.cmsUsecase.ReleaseQuotaDeviasi(ctx, req.ProspectID, constant.QUOTA_REASON_CANCEL, constant.SYSTEM)
+	}
+
 	trxKPM, _ = h.repository.GetTrxKPM(req.ProspectID)
 	if trxKPM != (entity.TrxKPM{}) {
 		if req.OrderStatus == constant.PRINCIPLE_STATUS_CANCEL_SALLY && trxKPM.Decision != constant.STATUS_SALLY_CANCEL_2WILEN {
@@ -137,5 +152,5 @@ func (h handlers) CancelOrder2Wilen(ctx context.Context, event event.Event) (err
 		}
 	}
 
-	return nil
+	return
 }
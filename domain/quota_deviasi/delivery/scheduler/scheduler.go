@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/quota_deviasi/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"time"
+)
+
+// Run checks the quota deviasi period every interval and resets the quota at the period boundary until ctx is done
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "QUOTA_DEVIASI_RESET_SCHEDULER",
+		MsgLogFile: "LOS - Quota Deviasi Reset Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+	}, func(ctx context.Context, _ string) (map[string]interface{}, error) {
+		branches, err := usecase.ResetQuotaDeviasi(ctx)
+		return map[string]interface{}{"branches": branches}, err
+	})
+}
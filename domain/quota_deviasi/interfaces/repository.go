@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+	"time"
+)
+
+type Repository interface {
+	GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error)
+	ResetQuotaDeviasiPeriod(periodStart time.Time, resetBy string) (branches int, err error)
+}
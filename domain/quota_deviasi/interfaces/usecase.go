@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"context"
+)
+
+type Usecase interface {
+	ResetQuotaDeviasi(ctx context.Context) (branches int, err error)
+}
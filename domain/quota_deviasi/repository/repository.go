@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"los-kmb-api/domain/quota_deviasi/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	losDB  *gorm.DB
+	NewKmb *gorm.DB
+}
+
+func NewRepository(kpLos, NewKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		losDB:  kpLos,
+		NewKmb: NewKmb,
+	}
+}
+
+func (r repoHandler) GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error) {
+
+	if err = r.losDB.Raw("SELECT [value] FROM app_config WITH (nolock) WHERE group_name = ? AND lob = ? AND [key] = ? AND is_active = 1", groupName, lob, key).Scan(&appConfig).Error; err != nil {
+		return
+	}
+
+	return
+}
+
+// ResetQuotaDeviasiPeriod archives and clears the booking of every active branch still in a period before periodStart,
+// a branch without period yet only starts its period so the running booking is kept
+func (r repoHandler) ResetQuotaDeviasiPeriod(periodStart time.Time, resetBy string) (branches int, err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	if err = db.Exec(`UPDATE m_branch_deviasi SET period_start = ? WHERE period_start IS NULL`, periodStart).Error; err != nil {
+		return
+	}
+
+	if err = db.Exec(`INSERT INTO trx_quota_deviasi_period (id, BranchID, period_start, period_end, quota_amount, quota_account, booking_amount, booking_account, balance_amount, balance_account, reset_type, created_by, created_at)
+		SELECT NEWID(), BranchID, period_start, ?, quota_amount, quota_account, booking_amount, booking_account, balance_amount, balance_account, ?, ?, GETDATE()
+		FROM m_branch_deviasi WITH (updlock) WHERE is_active = 1 AND period_start < ?`,
+		periodStart, constant.QUOTA_RESET_SCHEDULER, resetBy, periodStart).Error; err != nil {
+		return
+	}
+
+	// an inactive branch has no quota to reset, it only moves to the new period
+	result := db.Exec(`UPDATE m_branch_deviasi
+		SET booking_amount = CASE WHEN is_active = 1 THEN 0 ELSE booking_amount END,
+			booking_account = CASE WHEN is_active = 1 THEN 0 ELSE booking_account END,
+			balance_amount = CASE WHEN is_active = 1 THEN quota_amount ELSE balance_amount END,
+			balance_account = CASE WHEN is_active = 1 THEN quota_account ELSE balance_account END,
+			period_start = ?, updated_at = GETDATE(), updated_by = ?
+		WHERE period_start < ?`, periodStart, resetBy, periodStart)
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	branches = int(result.RowsAffected)
+
+	return
+}
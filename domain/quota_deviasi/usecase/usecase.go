@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"los-kmb-api/domain/quota_deviasi/interfaces"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"time"
+)
+
+type usecase struct {
+	repository interfaces.Repository
+}
+
+func NewUsecase(repository interfaces.Repository) interfaces.Usecase {
+	return &usecase{
+		repository: repository,
+	}
+}
+
+// ResetQuotaDeviasi starts a new quota period once the configured period boundary has passed, running it again in the same period changes nothing
+func (u usecase) ResetQuotaDeviasi(ctx context.Context) (branches int, err error) {
+
+	var resetConfig response.QuotaDeviasiResetConfig
+
+	configData, err := u.repository.GetConfig(constant.QUOTA_RESET_CONFIG_GROUP, constant.LOB_KMB_OFF, constant.QUOTA_RESET_CONFIG_KEY)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Quota Deviasi Reset Config Error")
+		return
+	}
+
+	if err = json.Unmarshal([]byte(configData.Value), &resetConfig); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error Unmarshal Get Quota Deviasi Reset Config")
+		return
+	}
+
+	if !resetConfig.Data.IsActive {
+		return
+	}
+
+	periodStart, ok := utils.QuotaPeriodStart(time.Now(), resetConfig.Data.Period)
+	if !ok {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - Periode reset kuota deviasi tidak valid")
+		return
+	}
+
+	branches, err = u.repository.ResetQuotaDeviasiPeriod(periodStart, constant.SYSTEM)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Reset Quota Deviasi Period error")
+		return
+	}
+
+	return
+}
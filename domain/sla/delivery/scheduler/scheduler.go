@@ -0,0 +1,23 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/sla/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"time"
+)
+
+// Run refreshes the sla aging and publishes escalations every interval until ctx is done
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "SLA_SCHEDULER",
+		MsgLogFile: "LOS - SLA Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+	}, func(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+		escalated, err := usecase.MonitorSla(ctx, accessToken)
+		return map[string]interface{}{"escalated": escalated}, err
+	})
+}
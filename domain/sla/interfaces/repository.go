@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+	"time"
+)
+
+type Repository interface {
+	GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error)
+	GetOpenOrders(stages []string) (data []entity.OpenOrderStage, err error)
+	SaveSlaAging(data entity.TrxSlaAging) (err error)
+	ClaimSlaEscalation(data entity.TrxSlaAging, escalatedAt time.Time) (err error)
+	ResetSlaEscalation(data entity.TrxSlaAging, escalatedAt time.Time) (err error)
+}
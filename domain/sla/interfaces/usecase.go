@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"context"
+)
+
+type Usecase interface {
+	MonitorSla(ctx context.Context, accessToken string) (escalated int, err error)
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"los-kmb-api/domain/sla/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	losDB  *gorm.DB
+	NewKmb *gorm.DB
+}
+
+func NewRepository(kpLos, NewKmb *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		losDB:  kpLos,
+		NewKmb: NewKmb,
+	}
+}
+
+func (r repoHandler) GetConfig(groupName string, lob string, key string) (appConfig entity.AppConfig, err error) {
+
+	if err = r.losDB.Raw("SELECT [value] FROM app_config WITH (nolock) WHERE group_name = ? AND lob = ? AND [key] = ? AND is_active = 1", groupName, lob, key).Scan(&appConfig).Error; err != nil {
+		return
+	}
+
+	return
+}
+
+// GetOpenOrders returns the orders waiting for a decision in one of the stages, with the escalation of the current stage entry if any
+func (r repoHandler) GetOpenOrders(stages []string) (data []entity.OpenOrderStage, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw(`SELECT tst.ProspectID, tm.BranchID, tst.source_decision AS stage, tst.created_at AS stage_entered_at, tsa.escalated_at
+		FROM trx_status tst WITH (nolock)
+		INNER JOIN trx_master tm WITH (nolock) ON tst.ProspectID = tm.ProspectID
+		LEFT JOIN trx_sla_aging tsa WITH (nolock) ON tsa.ProspectID = tst.ProspectID AND tsa.stage = tst.source_decision AND tsa.stage_entered_at = tst.created_at
+		WHERE tst.status_process = ? AND tst.activity = ? AND tst.source_decision IN (?)`,
+		constant.STATUS_ONPROCESS, constant.ACTIVITY_UNPROCESS, stages).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// SaveSlaAging keeps one row per order in a single merge so replicas do not race on the insert, a new stage entry
+// replaces the previous one and clears its escalation
+func (r repoHandler) SaveSlaAging(data entity.TrxSlaAging) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec(`MERGE trx_sla_aging WITH (holdlock) AS t
+		USING (SELECT ? AS ProspectID, ? AS stage, ? AS stage_entered_at, ? AS aging_minutes, ? AS sla_minutes, ? AS is_breached, ? AS updated_at) AS s
+		ON t.ProspectID = s.ProspectID
+		WHEN MATCHED THEN UPDATE SET
+			escalated_at = CASE WHEN t.stage = s.stage AND t.stage_entered_at = s.stage_entered_at THEN t.escalated_at ELSE NULL END,
+			stage = s.stage, stage_entered_at = s.stage_entered_at, aging_minutes = s.aging_minutes,
+			sla_minutes = s.sla_minutes, is_breached = s.is_breached, updated_at = s.updated_at
+		WHEN NOT MATCHED THEN
+			INSERT (ProspectID, stage, stage_entered_at, aging_minutes, sla_minutes, is_breached, updated_at)
+			VALUES (s.ProspectID, s.stage, s.stage_entered_at, s.aging_minutes, s.sla_minutes, s.is_breached, s.updated_at);`,
+		data.ProspectID, data.Stage, data.StageEnteredAt, data.AgingMinutes, data.SlaMinutes, data.IsBreached, data.UpdatedAt).Error
+
+	return
+}
+
+// ClaimSlaEscalation marks the stage entry of the order as escalated before its event is published, ERROR_ROWS_AFFECTED
+// is returned when another replica escalated it already
+func (r repoHandler) ClaimSlaEscalation(data entity.TrxSlaAging, escalatedAt time.Time) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	result := db.Exec("UPDATE trx_sla_aging SET escalated_at = ? WHERE ProspectID = ? AND stage = ? AND stage_entered_at = ? AND escalated_at IS NULL",
+		escalatedAt, data.ProspectID, data.Stage, data.StageEnteredAt)
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = errors.New(constant.ERROR_ROWS_AFFECTED)
+	}
+
+	return
+}
+
+// ResetSlaEscalation gives the escalation back to the next run after its event failed to publish
+func (r repoHandler) ResetSlaEscalation(data entity.TrxSlaAging, escalatedAt time.Time) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec("UPDATE trx_sla_aging SET escalated_at = NULL WHERE ProspectID = ? AND stage = ? AND stage_entered_at = ? AND escalated_at = ?",
+		data.ProspectID, data.Stage, data.StageEnteredAt, escalatedAt).Error
+
+	return
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	notification "los-kmb-api/domain/notification/interfaces"
+	"los-kmb-api/domain/sla/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/common/platformevent"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"time"
+)
+
+type usecase struct {
+	repository   interfaces.Repository
+	producer     platformevent.PlatformEventInterface
+	notification notification.Usecase
+}
+
+func NewUsecase(repository interfaces.Repository, producer platformevent.PlatformEventInterface, notification notification.Usecase) interfaces.Usecase {
+	return &usecase{
+		repository:   repository,
+		producer:     producer,
+		notification: notification,
+	}
+}
+
+// MonitorSla refreshes the aging of every open order and escalates once per stage entry when the sla is breached, the
+// escalation is claimed before it is published so only one replica escalates a stage entry
+func (u usecase) MonitorSla(ctx context.Context, accessToken string) (escalated int, err error) {
+
+	var slaConfig response.SlaConfig
+
+	configData, err := u.repository.GetConfig(constant.SLA_CONFIG_GROUP, constant.LOB_KMB_OFF, constant.SLA_CONFIG_KEY)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get SLA Config Error")
+		return
+	}
+
+	if err = json.Unmarshal([]byte(configData.Value), &slaConfig); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Error Unmarshal Get SLA Config")
+		return
+	}
+
+	var stages []string
+	for stage := range slaConfig.Data.Stages {
+		stages = append(stages, stage)
+	}
+
+	if len(stages) == 0 {
+		return
+	}
+
+	orders, err := u.repository.GetOpenOrders(stages)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Open Orders error")
+		return
+	}
+
+	now := time.Now()
+
+	for _, order := range orders {
+
+		aging, tracked := utils.SlaAging(order.Stage, order.StageEnteredAt, now, slaConfig.Data)
+		if !tracked {
+			continue
+		}
+
+		record := entity.TrxSlaAging{
+			ProspectID:     order.ProspectID,
+			Stage:          order.Stage,
+			StageEnteredAt: order.StageEnteredAt,
+			AgingMinutes:   aging.AgingMinutes,
+			SlaMinutes:     aging.SlaMinutes,
+			IsBreached:     aging.IsBreached,
+			UpdatedAt:      now,
+		}
+
+		if err = u.repository.SaveSlaAging(record); err != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Save SLA Aging error")
+			return
+		}
+
+		if !aging.IsBreached || order.EscalatedAt != nil {
+			continue
+		}
+
+		if err = u.repository.ClaimSlaEscalation(record, now); err != nil {
+			// escalated by another replica
+			if err.Error() == constant.ERROR_ROWS_AFFECTED {
+				err = nil
+				continue
+			}
+			err = errors.New(constant.ERROR_UPSTREAM + " - Claim SLA Escalation error")
+			return
+		}
+
+		if errPublish := u.escalate(ctx, accessToken, order, aging); errPublish != nil {
+			// given back, the next run retries it
+			if err = u.repository.ResetSlaEscalation(record, now); err != nil {
+				err = errors.New(constant.ERROR_UPSTREAM + " - Reset SLA Escalation error")
+				return
+			}
+			continue
+		}
+
+		escalated++
+
+		u.notifyBreach(ctx, accessToken, order, aging)
+	}
+
+	return
+}
+
+func (u usecase) escalate(ctx context.Context, accessToken string, order entity.OpenOrderStage, aging entity.SlaAging) error {
+
+	return u.producer.PublishEvent(ctx, accessToken, constant.TOPIC_SLA_ESCALATION, constant.KEY_PREFIX_SLA_ESCALATION, order.ProspectID, map[string]interface{}{
+		"event":            constant.EVENT_SLA_ESCALATION,
+		"prospect_id":      order.ProspectID,
+		"branch_id":        order.BranchID,
+		"stage":            aging.Stage,
+		"stage_entered_at": aging.StageEnteredAt,
+		"aging_minutes":    aging.AgingMinutes,
+		"sla_minutes":      aging.SlaMinutes,
+	}, 0)
+}
+
+// notifyBreach tells the users of the stage waiting on the order, a failed notification does not retry the escalation
+func (u usecase) notifyBreach(ctx context.Context, accessToken string, order entity.OpenOrderStage, aging entity.SlaAging) {
+
+	if u.notification == nil {
+		return
+	}
+
+	event := entity.NotificationEvent{
+		EventType:  constant.NOTIFICATION_SLA_BREACHED,
+		ProspectID: order.ProspectID,
+		BranchID:   order.BranchID,
+		Alias:      aging.Stage,
+		Data: map[string]interface{}{
+			"stage_entered_at": aging.StageEnteredAt,
+			"aging_minutes":    aging.AgingMinutes,
+			"sla_minutes":      aging.SlaMinutes,
+		},
+	}
+
+	if _, err := u.notification.Notify(ctx, event); err != nil {
+		common.CentralizeLog(ctx, accessToken, common.CentralizeLogParameter{
+			Link:       os.Getenv("DUMMY_URL_LOGS"),
+			Action:     "NOTIFICATION",
+			Type:       event.EventType,
+			LogFile:    constant.NEW_KMB_LOG,
+			MsgLogFile: "LOS - Notify SLA Breach",
+			LevelLog:   constant.PLATFORM_LOG_LEVEL_ERROR,
+			Request:    event,
+			Response:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"los-kmb-api/domain/staging_job/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type handlerStagingJob struct {
+	usecase interfaces.Usecase
+	Json    common.JSON
+}
+
+func StagingJobHandler(kmbroute *echo.Group, usecase interfaces.Usecase, json common.JSON, middlewares *middlewares.AccessMiddleware) {
+	handler := handlerStagingJob{
+		usecase: usecase,
+		Json:    json,
+	}
+	kmbroute.POST("/admin/staging-job", handler.CreateStagingJob, middlewares.AccessMiddleware())
+	kmbroute.GET("/admin/staging-job/:job_id", handler.GetStagingJob, middlewares.AccessMiddleware())
+	kmbroute.GET("/admin/staging-job/:job_id/items", handler.GetStagingJobItems, middlewares.AccessMiddleware())
+	kmbroute.POST("/admin/staging-job/:job_id/retry", handler.RetryStagingJob, middlewares.AccessMiddleware())
+	kmbroute.GET("/admin/staging-job/:job_id/reconciliation", handler.GetStagingReconciliation, middlewares.AccessMiddleware())
+}
+
+// Staging Job Tools godoc
+// @Description Api Create a job that pushes a list of orders, or the approved orders matching the filter, to staging
+// @Tags Admin
+// @Produce json
+// @Param body body request.ReqStagingJob true "Body payload"
+// @Success 200 {object} response.ApiResponse{data=response.StagingJob}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/admin/staging-job [post]
+func (c *handlerStagingJob) CreateStagingJob(ctx echo.Context) (err error) {
+
+	var (
+		accessToken = middlewares.UserInfoData.AccessToken
+		req         request.ReqStagingJob
+	)
+
+	if err := ctx.Bind(&req); err != nil {
+		return c.Json.InternalServerErrorCustomV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Staging Job", err)
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Staging Job", req, err)
+	}
+
+	data, err := c.usecase.CreateStagingJob(ctx.Request().Context(), req)
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Staging Job", req, err)
+	}
+
+	go c.usecase.RunStagingJob(context.Background(), data.JobID)
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Create Staging Job", req, data)
+}
+
+// Staging Job Tools godoc
+// @Description Api Get the progress of a staging job
+// @Tags Admin
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} response.ApiResponse{data=response.StagingJob}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/admin/staging-job/{job_id} [get]
+func (c *handlerStagingJob) GetStagingJob(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	jobID := ctx.Param("job_id")
+
+	data, err := c.usecase.GetStagingJob(ctx.Request().Context(), jobID)
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Get Staging Job", jobID, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Get Staging Job", jobID, data)
+}
+
+// Staging Job Tools godoc
+// @Description Api Get the orders of a staging job with their status and error
+// @Tags Admin
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Param status query string false "PENDING, SUCCESS, SKIPPED or FAILED"
+// @Param page query string false "Page"
+// @Success 200 {object} response.ApiResponse{data=response.StagingJobItemRow}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/admin/staging-job/{job_id}/items [get]
+func (c *handlerStagingJob) GetStagingJobItems(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	req := request.ReqStagingJobItems{
+		JobID:  ctx.Param("job_id"),
+		Status: ctx.QueryParam("status"),
+	}
+
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	pagination := request.RequestPagination{
+		Page:  page,
+		Limit: 10,
+	}
+
+	if err := ctx.Validate(&req); err != nil {
+		return c.Json.BadRequestErrorValidationV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Get Staging Job Items", req, err)
+	}
+
+	data, rowTotal, err := c.usecase.GetStagingJobItems(ctx.Request().Context(), req, pagination)
+
+	if err != nil && err.Error() == constant.RECORD_NOT_FOUND {
+		return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Get Staging Job Items", req, response.StagingJobItemRow{Items: data})
+	}
+
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Get Staging Job Items", req, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Get Staging Job Items", req, response.StagingJobItemRow{
+		Items:          data,
+		RecordFiltered: len(data),
+		RecordTotal:    rowTotal,
+	})
+}
+
+// Staging Job Tools godoc
+// @Description Api Run the failed orders of a finished or stalled staging job again
+// @Tags Admin
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} response.ApiResponse{data=response.StagingJob}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/admin/staging-job/{job_id}/retry [post]
+func (c *handlerStagingJob) RetryStagingJob(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	jobID := ctx.Param("job_id")
+
+	data, err := c.usecase.RetryStagingJob(ctx.Request().Context(), jobID)
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Retry Staging Job", jobID, err)
+	}
+
+	go c.usecase.RunStagingJob(context.Background(), jobID)
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Retry Staging Job", jobID, data)
+}
+
+// Staging Job Tools godoc
+// @Description Api Compare the orders of a staging job between new_kmb and the staging db
+// @Tags Admin
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} response.ApiResponse{data=response.StagingReconciliation}
+// @Failure 400 {object} response.ApiResponse{error=response.ErrorValidation}
+// @Failure 500 {object} response.ApiResponse{}
+// @Router /api/v3/kmb/admin/staging-job/{job_id}/reconciliation [get]
+func (c *handlerStagingJob) GetStagingReconciliation(ctx echo.Context) (err error) {
+
+	var accessToken = middlewares.UserInfoData.AccessToken
+
+	jobID := ctx.Param("job_id")
+
+	data, err := c.usecase.GetStagingReconciliation(ctx.Request().Context(), jobID)
+	if err != nil {
+		return c.Json.ServerSideErrorV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Staging Reconciliation", jobID, err)
+	}
+
+	return c.Json.SuccessV2(ctx, accessToken, constant.NEW_KMB_LOG, "LOS - Staging Reconciliation", jobID, data)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"time"
+)
+
+type Repository interface {
+	GetStagingCandidates(req request.ReqStagingJob, limit int) (prospectIDs []string, err error)
+	SaveStagingJob(job entity.TrxStagingJob, items []entity.TrxStagingJobItem) (err error)
+	GetStagingJob(jobID string) (job entity.TrxStagingJob, err error)
+	GetStagingJobCounts(jobID string) (data []entity.StagingJobCount, err error)
+	GetStagingJobItems(jobID, status string, pagination interface{}) (data []entity.TrxStagingJobItem, rowTotal int, err error)
+	UpdateStagingJobItem(item entity.TrxStagingJobItem) (err error)
+	RestartStagingJob(jobID string, staleBefore time.Time) (err error)
+	FinishStagingJob(jobID string) (err error)
+	IsStaged(prospectID string) (staged bool, err error)
+	GetStagingTableCounts(tables, prospectIDs []string) (data []entity.StagingTableCount, err error)
+	GetStagingBranches(prospectIDs []string) (data []entity.StagingBranch, err error)
+	GetMasterBranches(prospectIDs []string) (data []entity.StagingBranch, err error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+)
+
+type Usecase interface {
+	CreateStagingJob(ctx context.Context, req request.ReqStagingJob) (data response.StagingJob, err error)
+	RetryStagingJob(ctx context.Context, jobID string) (data response.StagingJob, err error)
+	RunStagingJob(ctx context.Context, jobID string)
+	GetStagingJob(ctx context.Context, jobID string) (data response.StagingJob, err error)
+	GetStagingJobItems(ctx context.Context, req request.ReqStagingJobItems, pagination interface{}) (data []entity.TrxStagingJobItem, rowTotal int, err error)
+	GetStagingReconciliation(ctx context.Context, jobID string) (data response.StagingReconciliation, err error)
+}
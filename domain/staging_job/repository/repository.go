@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"los-kmb-api/domain/staging_job/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	jsoniter "github.com/json-iterator/go"
+)
+
+type repoHandler struct {
+	NewKmb  *gorm.DB
+	Staging *gorm.DB
+}
+
+func NewRepository(newKmb, staging *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		NewKmb:  newKmb,
+		Staging: staging,
+	}
+}
+
+// GetStagingCandidates returns the approved orders created within the date range, oldest first
+func (r repoHandler) GetStagingCandidates(req request.ReqStagingJob, limit int) (prospectIDs []string, err error) {
+
+	var (
+		x     sql.TxOptions
+		data  []entity.StagingBranch
+		query string
+		args  = []interface{}{constant.DB_DECISION_APR, constant.STATUS_FINAL, req.DateFrom, req.DateTo}
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if req.BranchID != "" {
+		query = " AND tm.BranchID = ?"
+		args = append(args, req.BranchID)
+	}
+
+	if err = db.Raw(fmt.Sprintf(`SELECT TOP %d tm.ProspectID, tm.BranchID
+		FROM trx_master tm WITH (nolock)
+		INNER JOIN trx_status tst WITH (nolock) ON tm.ProspectID = tst.ProspectID
+		WHERE tst.decision = ? AND tst.status_process = ?
+		AND CAST(tm.created_at AS date) BETWEEN ? AND ?%s
+		ORDER BY tm.created_at`, limit, query), args...).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	for _, row := range data {
+		prospectIDs = append(prospectIDs, row.ProspectID)
+	}
+
+	return
+}
+
+func (r repoHandler) SaveStagingJob(job entity.TrxStagingJob, items []entity.TrxStagingJobItem) (err error) {
+
+	return r.NewKmb.Transaction(func(tx *gorm.DB) error {
+
+		if err := tx.Create(&job).Error; err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r repoHandler) GetStagingJob(jobID string) (job entity.TrxStagingJob, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT TOP 1 * FROM trx_staging_job WITH (nolock) WHERE id = ?", jobID).Scan(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = errors.New(constant.RECORD_NOT_FOUND)
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetStagingJobCounts(jobID string) (data []entity.StagingJobCount, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT status, COUNT(*) AS total FROM trx_staging_job_item WITH (nolock) WHERE job_id = ? GROUP BY status", jobID).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// GetStagingJobItems returns the items of a job, pagination nil returns every item
+func (r repoHandler) GetStagingJobItems(jobID, status string, pagination interface{}) (data []entity.TrxStagingJobItem, rowTotal int, err error) {
+
+	var (
+		x              sql.TxOptions
+		filter         string
+		filterPaginate string
+		args           = []interface{}{jobID}
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if status != "" {
+		filter = " AND status = ?"
+		args = append(args, status)
+	}
+
+	if pagination != nil {
+		page, _ := json.Marshal(pagination)
+		var paginationFilter request.RequestPagination
+		jsoniter.ConfigCompatibleWithStandardLibrary.Unmarshal(page, &paginationFilter)
+		if paginationFilter.Page == 0 {
+			paginationFilter.Page = 1
+		}
+
+		offset := paginationFilter.Limit * (paginationFilter.Page - 1)
+
+		var row entity.TotalRow
+
+		if err = db.Raw(fmt.Sprintf("SELECT COUNT(*) AS totalRow FROM trx_staging_job_item WITH (nolock) WHERE job_id = ?%s", filter), args...).Scan(&row).Error; err != nil {
+			return
+		}
+
+		rowTotal = row.Total
+
+		filterPaginate = fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, paginationFilter.Limit)
+	}
+
+	if err = db.Raw(fmt.Sprintf("SELECT * FROM trx_staging_job_item WITH (nolock) WHERE job_id = ?%s ORDER BY ProspectID %s", filter, filterPaginate), args...).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	if len(data) == 0 && pagination != nil {
+		return data, 0, errors.New(constant.RECORD_NOT_FOUND)
+	}
+
+	return
+}
+
+// UpdateStagingJobItem also touches the job, updated_at of a running job tells whether its worker is still alive
+func (r repoHandler) UpdateStagingJobItem(item entity.TrxStagingJobItem) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	if err = db.Exec("UPDATE trx_staging_job_item SET status = ?, error = ?, updated_at = GETDATE() WHERE id = ?", item.Status, item.Error, item.ID).Error; err != nil {
+		return
+	}
+
+	err = db.Exec("UPDATE trx_staging_job SET updated_at = GETDATE() WHERE id = ?", item.JobID).Error
+
+	return
+}
+
+// RestartStagingJob puts a finished job, or a running one whose worker went silent before staleBefore, back to running
+// and queues its failed items again
+func (r repoHandler) RestartStagingJob(jobID string, staleBefore time.Time) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	result := db.Exec(`UPDATE trx_staging_job SET status = ?, finished_at = NULL, updated_at = GETDATE()
+		WHERE id = ? AND (status = ? OR updated_at < ?)`, constant.STAGING_JOB_STATUS_RUNNING, jobID, constant.STAGING_JOB_STATUS_DONE, staleBefore)
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		err = errors.New(constant.ERROR_ROWS_AFFECTED)
+		return
+	}
+
+	err = db.Exec("UPDATE trx_staging_job_item SET status = ?, error = '', updated_at = GETDATE() WHERE job_id = ? AND status = ?",
+		constant.STAGING_ITEM_PENDING, jobID, constant.STAGING_ITEM_FAILED).Error
+
+	return
+}
+
+func (r repoHandler) FinishStagingJob(jobID string) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.NewKmb.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	err = db.Exec("UPDATE trx_staging_job SET status = ?, finished_at = GETDATE(), updated_at = GETDATE() WHERE id = ?", constant.STAGING_JOB_STATUS_DONE, jobID).Error
+
+	return
+}
+
+// IsStaged checks STG_MAIN, SaveToStaging writes every staging table in one transaction so the main row stands for the order
+func (r repoHandler) IsStaged(prospectID string) (staged bool, err error) {
+
+	var (
+		x   sql.TxOptions
+		row entity.TotalRow
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.Staging.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT COUNT(*) AS totalRow FROM STG_MAIN WITH (nolock) WHERE ProspectID = ?", prospectID).Scan(&row).Error; err != nil {
+		return
+	}
+
+	staged = row.Total > 0
+
+	return
+}
+
+// GetStagingTableCounts counts the rows per staging table of each order, tables only holds the fixed staging table names
+func (r repoHandler) GetStagingTableCounts(tables, prospectIDs []string) (data []entity.StagingTableCount, err error) {
+
+	var (
+		x       sql.TxOptions
+		queries []string
+		args    []interface{}
+	)
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.Staging.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	for _, table := range tables {
+		queries = append(queries, fmt.Sprintf("SELECT ProspectID, '%s' AS table_name, COUNT(*) AS total FROM %s WITH (nolock) WHERE ProspectID IN (?) GROUP BY ProspectID", table, table))
+		args = append(args, prospectIDs)
+	}
+
+	if err = db.Raw(strings.Join(queries, " UNION ALL "), args...).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetStagingBranches(prospectIDs []string) (data []entity.StagingBranch, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.Staging.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT ProspectID, BranchID FROM STG_MAIN WITH (nolock) WHERE ProspectID IN (?)", prospectIDs).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+func (r repoHandler) GetMasterBranches(prospectIDs []string) (data []entity.StagingBranch, err error) {
+
+	var x sql.TxOptions
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_30S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	db := r.NewKmb.BeginTx(ctx, &x)
+	defer db.Commit()
+
+	if err = db.Raw("SELECT ProspectID, BranchID FROM trx_master WITH (nolock) WHERE ProspectID IN (?)", prospectIDs).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
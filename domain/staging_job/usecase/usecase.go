@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	kmbInterfaces "los-kmb-api/domain/kmb/interfaces"
+	"los-kmb-api/domain/staging_job/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/models/request"
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/common"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/utils"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// stagingTables are the tables SaveToStaging writes for every order, STG_CUST_FAM only exists for a married customer
+// so it is left out of the reconciliation
+var stagingTables = []string{"STG_MAIN", "STG_GEN_APP", "STG_GEN_ASD", "STG_GEN_COM", "STG_GEN_FIN", "STG_GEN_INS_H",
+	"STG_GEN_INS_D", "STG_GEN_LFI", "STG_CUST_H", "STG_CUST_D"}
+
+// reconChunkSize keeps the IN lists of the reconciliation queries below the sql server parameter limit
+const reconChunkSize = 150
+
+type usecase struct {
+	repository    interfaces.Repository
+	kmbRepository kmbInterfaces.Repository
+}
+
+func NewUsecase(repository interfaces.Repository, kmbRepository kmbInterfaces.Repository) interfaces.Usecase {
+	return &usecase{
+		repository:    repository,
+		kmbRepository: kmbRepository,
+	}
+}
+
+// CreateStagingJob records a job with one pending item per order, the orders come from the list or else from the filter
+func (u usecase) CreateStagingJob(ctx context.Context, req request.ReqStagingJob) (data response.StagingJob, err error) {
+
+	prospectIDs := req.ProspectIDs
+
+	if len(prospectIDs) == 0 {
+		prospectIDs, err = u.repository.GetStagingCandidates(req, constant.STAGING_JOB_MAX_ITEMS)
+		if err != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Candidates Error")
+			return
+		}
+	}
+
+	var (
+		items []entity.TrxStagingJobItem
+		seen  = map[string]bool{}
+		now   = time.Now()
+	)
+
+	job := entity.TrxStagingJob{
+		ID:        utils.GenerateUUID(),
+		Status:    constant.STAGING_JOB_STATUS_RUNNING,
+		CreatedBy: req.CreatedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	for _, prospectID := range prospectIDs {
+		if seen[prospectID] {
+			continue
+		}
+		seen[prospectID] = true
+
+		items = append(items, entity.TrxStagingJobItem{
+			ID:         utils.GenerateUUID(),
+			JobID:      job.ID,
+			ProspectID: prospectID,
+			Status:     constant.STAGING_ITEM_PENDING,
+			UpdatedAt:  now,
+		})
+	}
+
+	if len(items) == 0 {
+		err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_STAGING_JOB_EMPTY)
+		return
+	}
+
+	job.TotalItem = len(items)
+
+	if err = u.repository.SaveStagingJob(job, items); err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Save Staging Job Error")
+		return
+	}
+
+	data = response.StagingJob{
+		JobID:     job.ID,
+		Status:    job.Status,
+		TotalItem: job.TotalItem,
+		Pending:   job.TotalItem,
+		CreatedBy: job.CreatedBy,
+		CreatedAt: job.CreatedAt,
+	}
+
+	return
+}
+
+// RetryStagingJob queues the failed items of a finished job again, a running job is only taken over once its worker
+// has been silent for STAGING_JOB_STALE_MINUTES
+func (u usecase) RetryStagingJob(ctx context.Context, jobID string) (data response.StagingJob, err error) {
+
+	if _, err = u.GetStagingJob(ctx, jobID); err != nil {
+		return
+	}
+
+	stale, _ := strconv.Atoi(os.Getenv("STAGING_JOB_STALE_MINUTES"))
+	if stale <= 0 {
+		stale = constant.STAGING_JOB_STALE_MINUTES
+	}
+
+	if err = u.repository.RestartStagingJob(jobID, time.Now().Add(-time.Duration(stale)*time.Minute)); err != nil {
+		if err.Error() == constant.ERROR_ROWS_AFFECTED {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.ERROR_STAGING_JOB_RUNNING)
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Restart Staging Job Error")
+		return
+	}
+
+	return u.GetStagingJob(ctx, jobID)
+}
+
+// RunStagingJob pushes the pending items of a job to staging with at most STAGING_JOB_CONCURRENCY orders at a time,
+// an order already in STG_MAIN is skipped so a job can be run again without duplicating staging rows
+func (u usecase) RunStagingJob(ctx context.Context, jobID string) {
+
+	var wg sync.WaitGroup
+
+	// the job runs detached from the request, a panic is logged and the job still finishes once the started items are done
+	defer func() {
+		if r := recover(); r != nil {
+			logStagingJobError(ctx, jobID, fmt.Errorf("panic: %v", r))
+			wg.Wait()
+			_ = u.repository.FinishStagingJob(jobID)
+		}
+	}()
+
+	items, _, err := u.repository.GetStagingJobItems(jobID, constant.STAGING_ITEM_PENDING, nil)
+	if err != nil {
+		logStagingJobError(ctx, jobID, err)
+		return
+	}
+
+	concurrency, _ := strconv.Atoi(os.Getenv("STAGING_JOB_CONCURRENCY"))
+	if concurrency <= 0 {
+		concurrency = constant.STAGING_JOB_CONCURRENCY
+	}
+
+	semaphore := make(chan struct{}, concurrency)
+
+	for _, item := range items {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(item entity.TrxStagingJobItem) {
+			defer func() {
+				// a panic fails the item instead of the whole service
+				if r := recover(); r != nil {
+					item.Status, item.Error = constant.STAGING_ITEM_FAILED, truncateError(fmt.Errorf("panic: %v", r))
+					_ = u.repository.UpdateStagingJobItem(item)
+				}
+				<-semaphore
+				wg.Done()
+			}()
+
+			item.Status, item.Error = u.stageOrder(item.ProspectID)
+
+			_ = u.repository.UpdateStagingJobItem(item)
+		}(item)
+	}
+
+	wg.Wait()
+
+	_ = u.repository.FinishStagingJob(jobID)
+}
+
+func logStagingJobError(ctx context.Context, jobID string, err error) {
+
+	common.CentralizeLog(ctx, middlewares.UserInfoData.AccessToken, common.CentralizeLogParameter{
+		Link:       os.Getenv("DUMMY_URL_LOGS"),
+		Action:     "STAGING_JOB",
+		Type:       "JOB",
+		LogFile:    constant.NEW_KMB_LOG,
+		MsgLogFile: "LOS - Staging Job",
+		LevelLog:   constant.PLATFORM_LOG_LEVEL_ERROR,
+		Request:    map[string]string{"job_id": jobID},
+		Response:   err.Error(),
+	})
+}
+
+func (u usecase) stageOrder(prospectID string) (status, message string) {
+
+	staged, err := u.repository.IsStaged(prospectID)
+	if err != nil {
+		return constant.STAGING_ITEM_FAILED, truncateError(err)
+	}
+
+	if staged {
+		return constant.STAGING_ITEM_SKIPPED, ""
+	}
+
+	if err = u.kmbRepository.SaveToStaging(prospectID); err != nil {
+		return constant.STAGING_ITEM_FAILED, truncateError(err)
+	}
+
+	return constant.STAGING_ITEM_SUCCESS, ""
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > 500 {
+		message = message[:500]
+	}
+	return message
+}
+
+func (u usecase) GetStagingJob(ctx context.Context, jobID string) (data response.StagingJob, err error) {
+
+	job, err := u.repository.GetStagingJob(jobID)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			err = errors.New(constant.ERROR_BAD_REQUEST + " - " + constant.RECORD_NOT_FOUND)
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Job Error")
+		return
+	}
+
+	counts, err := u.repository.GetStagingJobCounts(jobID)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Job Counts Error")
+		return
+	}
+
+	data = response.StagingJob{
+		JobID:      job.ID,
+		Status:     job.Status,
+		TotalItem:  job.TotalItem,
+		CreatedBy:  job.CreatedBy,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+	}
+
+	for _, count := range counts {
+		switch count.Status {
+		case constant.STAGING_ITEM_PENDING:
+			data.Pending = count.Total
+		case constant.STAGING_ITEM_SUCCESS:
+			data.Success = count.Total
+		case constant.STAGING_ITEM_SKIPPED:
+			data.Skipped = count.Total
+		case constant.STAGING_ITEM_FAILED:
+			data.Failed = count.Total
+		}
+	}
+
+	return
+}
+
+func (u usecase) GetStagingJobItems(ctx context.Context, req request.ReqStagingJobItems, pagination interface{}) (data []entity.TrxStagingJobItem, rowTotal int, err error) {
+
+	data, rowTotal, err = u.repository.GetStagingJobItems(req.JobID, req.Status, pagination)
+	if err != nil {
+		if err.Error() == constant.RECORD_NOT_FOUND {
+			return
+		}
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Job Items Error")
+		return
+	}
+
+	return
+}
+
+// GetStagingReconciliation compares every order of a job between new_kmb and the staging db, an order is MATCH when
+// all staging tables hold its rows and STG_MAIN carries the branch of trx_master
+func (u usecase) GetStagingReconciliation(ctx context.Context, jobID string) (data response.StagingReconciliation, err error) {
+
+	if _, err = u.GetStagingJob(ctx, jobID); err != nil {
+		return
+	}
+
+	items, _, err := u.repository.GetStagingJobItems(jobID, "", nil)
+	if err != nil {
+		err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Job Items Error")
+		return
+	}
+
+	var (
+		masterBranch  = map[string]string{}
+		stagingBranch = map[string]string{}
+		tableCount    = map[string]map[string]int{}
+	)
+
+	for start := 0; start < len(items); start += reconChunkSize {
+		end := start + reconChunkSize
+		if end > len(items) {
+			end = len(items)
+		}
+
+		var prospectIDs []string
+		for _, item := range items[start:end] {
+			prospectIDs = append(prospectIDs, item.ProspectID)
+		}
+
+		masters, errMaster := u.repository.GetMasterBranches(prospectIDs)
+		if errMaster != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Master Branches Error")
+			return
+		}
+
+		for _, row := range masters {
+			masterBranch[row.ProspectID] = row.BranchID
+		}
+
+		stagings, errStaging := u.repository.GetStagingBranches(prospectIDs)
+		if errStaging != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Branches Error")
+			return
+		}
+
+		for _, row := range stagings {
+			stagingBranch[row.ProspectID] = row.BranchID
+		}
+
+		counts, errCount := u.repository.GetStagingTableCounts(stagingTables, prospectIDs)
+		if errCount != nil {
+			err = errors.New(constant.ERROR_UPSTREAM + " - Get Staging Table Counts Error")
+			return
+		}
+
+		for _, row := range counts {
+			if tableCount[row.ProspectID] == nil {
+				tableCount[row.ProspectID] = map[string]int{}
+			}
+			tableCount[row.ProspectID][row.Table] = row.Total
+		}
+	}
+
+	data = response.StagingReconciliation{
+		JobID: jobID,
+		Total: len(items),
+		Items: []response.StagingReconciliationItem{},
+	}
+
+	for _, item := range items {
+
+		_, inNewKmb := masterBranch[item.ProspectID]
+
+		result := classifyStagingReconciliation(item.ProspectID, inNewKmb, masterBranch[item.ProspectID], stagingBranch[item.ProspectID], tableCount[item.ProspectID])
+
+		switch result.Result {
+		case constant.STAGING_RECON_MISSING_NEW_KMB:
+			data.MissingNewKmb++
+		case constant.STAGING_RECON_MISSING_STAGING:
+			data.MissingStaging++
+		case constant.STAGING_RECON_PARTIAL:
+			data.Partial++
+		case constant.STAGING_RECON_MISMATCH:
+			data.Mismatch++
+		default:
+			data.Match++
+		}
+
+		data.Items = append(data.Items, result)
+	}
+
+	return
+}
+
+// classifyStagingReconciliation compares one prospect between new kmb and staging, a prospect missing from new kmb
+// wins over missing staging rows, and the branch is only compared once every staging table has a row
+func classifyStagingReconciliation(prospectID string, inNewKmb bool, branchID, stagingBranchID string, tableCount map[string]int) (result response.StagingReconciliationItem) {
+
+	result = response.StagingReconciliationItem{
+		ProspectID:      prospectID,
+		BranchID:        branchID,
+		StagingBranchID: stagingBranchID,
+		MissingTables:   []string{},
+	}
+
+	for _, table := range stagingTables {
+		if tableCount[table] == 0 {
+			result.MissingTables = append(result.MissingTables, table)
+		}
+	}
+
+	switch {
+	case !inNewKmb:
+		result.Result = constant.STAGING_RECON_MISSING_NEW_KMB
+	case len(result.MissingTables) == len(stagingTables):
+		result.Result = constant.STAGING_RECON_MISSING_STAGING
+	case len(result.MissingTables) > 0:
+		result.Result = constant.STAGING_RECON_PARTIAL
+	case result.BranchID != result.StagingBranchID:
+		result.Result = constant.STAGING_RECON_MISMATCH
+	default:
+		result.Result = constant.STAGING_RECON_MATCH
+	}
+
+	return
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"los-kmb-api/shared/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyStagingReconciliation(t *testing.T) {
+
+	allTables := map[string]int{}
+	for _, table := range stagingTables {
+		allTables[table] = 1
+	}
+
+	partialTables := map[string]int{}
+	for _, table := range stagingTables[1:] {
+		partialTables[table] = 2
+	}
+
+	testcases := []struct {
+		name            string
+		inNewKmb        bool
+		branchID        string
+		stagingBranchID string
+		tableCount      map[string]int
+		result          string
+		missingTables   []string
+	}{
+		{name: "match", inNewKmb: true, branchID: "426", stagingBranchID: "426", tableCount: allTables, result: constant.STAGING_RECON_MATCH, missingTables: []string{}},
+		{name: "branch mismatch", inNewKmb: true, branchID: "426", stagingBranchID: "400", tableCount: allTables, result: constant.STAGING_RECON_MISMATCH, missingTables: []string{}},
+		{name: "partial staging", inNewKmb: true, branchID: "426", stagingBranchID: "426", tableCount: partialTables, result: constant.STAGING_RECON_PARTIAL, missingTables: stagingTables[:1]},
+		{name: "partial staging wins over branch mismatch", inNewKmb: true, branchID: "426", stagingBranchID: "400", tableCount: partialTables, result: constant.STAGING_RECON_PARTIAL, missingTables: stagingTables[:1]},
+		{name: "missing staging", inNewKmb: true, branchID: "426", tableCount: nil, result: constant.STAGING_RECON_MISSING_STAGING, missingTables: stagingTables},
+		{name: "missing new kmb", inNewKmb: false, stagingBranchID: "426", tableCount: allTables, result: constant.STAGING_RECON_MISSING_NEW_KMB, missingTables: []string{}},
+		{name: "missing new kmb wins over missing staging", inNewKmb: false, tableCount: nil, result: constant.STAGING_RECON_MISSING_NEW_KMB, missingTables: stagingTables},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			result := classifyStagingReconciliation("SAL-1140024080800004", tc.inNewKmb, tc.branchID, tc.stagingBranchID, tc.tableCount)
+
+			assert.Equal(t, "SAL-1140024080800004", result.ProspectID)
+			assert.Equal(t, tc.branchID, result.BranchID)
+			assert.Equal(t, tc.stagingBranchID, result.StagingBranchID)
+			assert.Equal(t, tc.result, result.Result)
+			assert.Equal(t, tc.missingTables, result.MissingTables)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"context"
+	"los-kmb-api/domain/worker/interfaces"
+	"los-kmb-api/middlewares"
+	"los-kmb-api/shared/common"
+	"time"
+)
+
+// Run executes the due trx_worker rows every interval until ctx is done, an idle tick is not worth a log line
+func Run(ctx context.Context, usecase interfaces.Usecase, interval time.Duration) {
+
+	common.RunScheduler(ctx, common.SchedulerParameter{
+		Action:     "WORKER_SCHEDULER",
+		MsgLogFile: "LOS - Worker Scheduler",
+		Interval:   interval,
+		Auth:       middlewares.PlatformToken,
+		SkipIdle:   true,
+	}, func(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+		executed, err := usecase.ExecuteWorkers(ctx, accessToken)
+		return map[string]interface{}{"executed": executed}, err
+	})
+}
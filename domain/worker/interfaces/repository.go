@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"los-kmb-api/models/entity"
+	"time"
+)
+
+type Repository interface {
+	ClaimWorkers(owner string, limit int) (data []entity.TrxWorker, err error)
+	FinishWorker(worker entity.TrxWorker, owner, activity, statusCode, lastError string, nextRetryAt *time.Time) (err error)
+}
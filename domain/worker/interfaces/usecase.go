@@ -0,0 +1,9 @@
+package interfaces
+
+import (
+	"context"
+)
+
+type Usecase interface {
+	ExecuteWorkers(ctx context.Context, accessToken string) (executed int, err error)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"los-kmb-api/domain/worker/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type repoHandler struct {
+	losDB *gorm.DB
+}
+
+func NewRepository(kpLos *gorm.DB) interfaces.Repository {
+	return &repoHandler{
+		losDB: kpLos,
+	}
+}
+
+// ClaimWorkers locks a batch of due worker rows for the owner, rows locked by another replica are skipped
+// and a row left running past the lock timeout is claimed again
+func (r repoHandler) ClaimWorkers(owner string, limit int) (data []entity.TrxWorker, err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.losDB.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	query := fmt.Sprintf(`WITH claim AS (
+			SELECT TOP (%d) * FROM trx_worker WITH (updlock, readpast, rowlock)
+			WHERE (activity = ? AND (next_retry_at IS NULL OR next_retry_at <= GETDATE()))
+				OR (activity = ? AND locked_at <= DATEADD(MINUTE, -%d, GETDATE()))
+			ORDER BY created_at ASC
+		)
+		UPDATE claim SET activity = ?, locked_by = ?, locked_at = GETDATE()
+		OUTPUT inserted.*`, limit, constant.WORKER_LOCK_TIMEOUT)
+
+	if err = db.Raw(query, constant.ACTIVITY_UNPROCESS, constant.WORKER_ACTIVITY_RUNNING, constant.WORKER_ACTIVITY_RUNNING, owner).Scan(&data).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			err = nil
+		}
+		return
+	}
+
+	return
+}
+
+// FinishWorker stores the result of a run of the owner, a processed row starts the next row of its sequence
+func (r repoHandler) FinishWorker(worker entity.TrxWorker, owner, activity, statusCode, lastError string, nextRetryAt *time.Time) (err error) {
+
+	timeout, _ := strconv.Atoi(os.Getenv("DEFAULT_TIMEOUT_10S"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+	txOptions := &sql.TxOptions{}
+
+	db := r.losDB.BeginTx(ctx, txOptions)
+	defer db.Commit()
+
+	defer func() {
+		if r := recover(); r != nil || err != nil {
+			db.Rollback()
+		}
+	}()
+
+	result := db.Exec(`UPDATE trx_worker SET activity = ?, count_retry = ?, status_code = ?, last_error = NULLIF(?, ''), next_retry_at = ?, locked_by = NULL, locked_at = NULL
+		WHERE ProspectID = ? AND category = ? AND action = ? AND activity = ? AND locked_by = ?`,
+		activity, worker.CountRetry, statusCode, lastError, nextRetryAt,
+		worker.ProspectID, worker.Category, worker.Action, constant.WORKER_ACTIVITY_RUNNING, owner)
+
+	if err = result.Error; err != nil {
+		return
+	}
+
+	// the lock expired and another replica owns the row now
+	if result.RowsAffected == 0 || activity != constant.ACTIVITY_PROCESS {
+		return
+	}
+
+	err = db.Exec(`UPDATE next SET activity = ? FROM trx_worker AS next
+		JOIN trx_worker AS done ON (next.ProspectID = done.ProspectID AND next.category = done.category AND next.sequence = done.sequence + 1)
+		WHERE done.ProspectID = ? AND done.category = ? AND done.action = ? AND next.activity = ?`,
+		constant.ACTIVITY_UNPROCESS, worker.ProspectID, worker.Category, worker.Action, constant.ACTIVITY_IDLE).Error
+
+	return
+}
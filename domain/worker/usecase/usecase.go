@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"los-kmb-api/domain/worker/interfaces"
+	"los-kmb-api/models/entity"
+	"los-kmb-api/shared/constant"
+	"los-kmb-api/shared/httpclient"
+	"os"
+	"strconv"
+	"time"
+)
+
+type usecase struct {
+	repository interfaces.Repository
+	httpclient httpclient.HttpClient
+	owner      string
+}
+
+func NewUsecase(repository interfaces.Repository, httpclient httpclient.HttpClient) interfaces.Usecase {
+	hostname, _ := os.Hostname()
+
+	return &usecase{
+		repository: repository,
+		httpclient: httpclient,
+		owner:      fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// ExecuteWorkers runs a batch of due worker rows, a failed row is retried with backoff until its max retry and then left as dead
+func (u usecase) ExecuteWorkers(ctx context.Context, accessToken string) (executed int, err error) {
+
+	workers, err := u.repository.ClaimWorkers(u.owner, constant.WORKER_BATCH_SIZE)
+	if err != nil {
+		return
+	}
+
+	for _, worker := range workers {
+		statusCode, errExecute := u.execute(ctx, worker, accessToken)
+
+		activity := constant.ACTIVITY_PROCESS
+		lastError := ""
+		var nextRetryAt *time.Time
+
+		if errExecute != nil {
+			worker.CountRetry++
+			lastError = errExecute.Error()
+
+			if worker.CountRetry >= worker.MaxRetry {
+				activity = constant.WORKER_ACTIVITY_DEAD
+			} else {
+				activity = constant.ACTIVITY_UNPROCESS
+				retryAt := time.Now().Add(workerBackoff(worker.CountRetry))
+				nextRetryAt = &retryAt
+			}
+		}
+
+		if errFinish := u.repository.FinishWorker(worker, u.owner, activity, statusCode, lastError, nextRetryAt); errFinish != nil {
+			err = errFinish
+			continue
+		}
+
+		executed++
+	}
+
+	return
+}
+
+func (u usecase) execute(ctx context.Context, worker entity.TrxWorker, accessToken string) (statusCode string, err error) {
+
+	header := map[string]string{}
+	if worker.Header != "" {
+		if err = json.Unmarshal([]byte(worker.Header), &header); err != nil {
+			err = fmt.Errorf("%s - header worker tidak valid", constant.ERROR_BAD_REQUEST)
+			return
+		}
+	}
+
+	var payload []byte
+	if worker.Payload != "" {
+		payload = []byte(worker.Payload)
+	}
+
+	timeout := worker.ResponseTimeout
+	if timeout <= 0 {
+		timeout = 30
+	}
+
+	resp, err := u.httpclient.EngineAPI(ctx, constant.NEW_KMB_LOG, worker.EndPointTarget, payload, header, worker.EndPointMethod, false, 0, timeout, worker.ProspectID, accessToken)
+
+	if resp != nil {
+		statusCode = strconv.Itoa(resp.StatusCode())
+
+		// a success with a body that is not json is still a success
+		if resp.StatusCode() == 200 || resp.StatusCode() == 201 {
+			return statusCode, nil
+		}
+	}
+
+	if err == nil {
+		err = fmt.Errorf("%s - status code %s", constant.ERROR_UPSTREAM, statusCode)
+	}
+
+	return
+}
+
+// workerBackoff doubles the wait for every failed attempt up to the max
+func workerBackoff(countRetry int) time.Duration {
+
+	backoff := constant.WORKER_BACKOFF_BASE
+	for i := 1; i < countRetry && backoff < constant.WORKER_BACKOFF_MAX; i++ {
+		backoff *= 2
+	}
+
+	if backoff > constant.WORKER_BACKOFF_MAX {
+		backoff = constant.WORKER_BACKOFF_MAX
+	}
+
+	return time.Duration(backoff) * time.Second
+}
@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerBackoff(t *testing.T) {
+
+	testcases := []struct {
+		name       string
+		countRetry int
+		expected   time.Duration
+	}{
+		{name: "no retry yet", countRetry: 0, expected: 30 * time.Second},
+		{name: "first retry", countRetry: 1, expected: 30 * time.Second},
+		{name: "second retry", countRetry: 2, expected: time.Minute},
+		{name: "fifth retry", countRetry: 5, expected: 8 * time.Minute},
+		{name: "capped at max", countRetry: 8, expected: time.Hour},
+		{name: "far past max", countRetry: 100, expected: time.Hour},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, workerBackoff(tc.countRetry))
+		})
+	}
+}
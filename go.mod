@@ -8,6 +8,7 @@ require (
 	github.com/KB-FMF/platform-library v1.0.22
 	github.com/allegro/bigcache/v3 v3.1.0
 	github.com/confluentinc/confluent-kafka-go v1.9.2
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-playground/validator/v10 v10.22.0
 	github.com/go-resty/resty/v2 v2.14.0
 	github.com/google/uuid v1.6.0
@@ -101,6 +101,14 @@ func GetPlatformAuth() (userInfo UserInfo, err error) {
 	return
 }
 
+// PlatformToken refreshes the platform token when it is about to expire and returns it, for the callers without a request token
+func PlatformToken() string {
+
+	GetPlatformAuth()
+
+	return UserInfoData.AccessToken
+}
+
 func GetTokenHris() (hrisApiInfo HrisApiInfo, err error) {
 
 	client := resty.New()
@@ -756,6 +756,162 @@ func (c *TrxBannedChassisNumber) TableName() string {
 	return "trx_banned_chassis_number"
 }
 
+type TrxLockOverride struct {
+	ID              string    `gorm:"type:varchar(50);column:id;primary_key:true" json:"id"`
+	LockType        string    `gorm:"type:varchar(20);column:lock_type" json:"lock_type"`
+	BannedType      string    `gorm:"type:varchar(10);column:banned_type" json:"banned_type"`
+	ProspectID      string    `gorm:"type:varchar(20);column:ProspectID" json:"prospect_id"`
+	IDNumber        string    `gorm:"type:varchar(40);column:IDNumber" json:"-"`
+	ChassisNumber   *string   `gorm:"type:varchar(50);column:chassis_number" json:"chassis_number"`
+	EngineNumber    *string   `gorm:"type:varchar(50);column:engine_number" json:"engine_number"`
+	Action          string    `gorm:"type:varchar(10);column:action" json:"action"`
+	UnbanDateBefore time.Time `gorm:"column:unban_date_before" json:"unban_date_before"`
+	UnbanDate       time.Time `gorm:"column:unban_date" json:"unban_date"`
+	Reason          string    `gorm:"type:varchar(250);column:reason" json:"reason"`
+	CreatedBy       string    `gorm:"type:varchar(20);column:created_by" json:"created_by"`
+	CreatedByName   string    `gorm:"type:varchar(200);column:created_by_name" json:"created_by_name"`
+	CreatedAt       time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (c *TrxLockOverride) TableName() string {
+	return "trx_lock_override"
+}
+
+type TrxUnlockNotification struct {
+	ID         string    `gorm:"type:varchar(100);column:id;primary_key:true"`
+	LockType   string    `gorm:"type:varchar(20);column:lock_type"`
+	BannedType string    `gorm:"type:varchar(10);column:banned_type"`
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID"`
+	UnbanDate  time.Time `gorm:"column:unban_date"`
+	Status     string    `gorm:"type:varchar(10);column:status"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (c *TrxUnlockNotification) TableName() string {
+	return "trx_unlock_notification"
+}
+
+type TrxExportJob struct {
+	ID           string     `gorm:"type:varchar(50);column:id;primary_key:true" json:"id"`
+	InquiryType  string     `gorm:"type:varchar(20);column:inquiry_type" json:"inquiry_type"`
+	FileFormat   string     `gorm:"type:varchar(5);column:file_format" json:"file_format"`
+	Filter       string     `gorm:"type:varchar(max);column:filter" json:"filter"`
+	Status       string     `gorm:"type:varchar(10);column:status" json:"status"`
+	TotalRows    int        `gorm:"column:total_rows" json:"total_rows"`
+	FileName     string     `gorm:"type:varchar(100);column:file_name" json:"file_name"`
+	FileURL      *string    `gorm:"type:varchar(500);column:file_url" json:"file_url"`
+	ErrorMessage *string    `gorm:"type:varchar(500);column:error_message" json:"error_message"`
+	CreatedBy    string     `gorm:"type:varchar(20);column:created_by" json:"created_by"`
+	CreatedAt    time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	FinishedAt   *time.Time `gorm:"column:finished_at" json:"finished_at"`
+}
+
+func (c *TrxExportJob) TableName() string {
+	return "trx_export_job"
+}
+
+// TrxUploadPreview keeps the validated rows of an uploaded file until the preview is confirmed
+type TrxUploadPreview struct {
+	ID         string     `gorm:"type:varchar(50);column:id;primary_key:true" json:"id"`
+	UploadType string     `gorm:"type:varchar(20);column:upload_type" json:"upload_type"`
+	IsValid    bool       `gorm:"column:is_valid" json:"is_valid"`
+	TotalRows  int        `gorm:"column:total_rows" json:"total_rows"`
+	ErrorCount int        `gorm:"column:error_count" json:"error_count"`
+	Payload    string     `gorm:"type:varchar(max);column:payload" json:"payload"`
+	Status     string     `gorm:"type:varchar(10);column:status" json:"status"`
+	CreatedBy  string     `gorm:"type:varchar(200);column:created_by" json:"created_by"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	AppliedBy  *string    `gorm:"type:varchar(200);column:applied_by" json:"applied_by"`
+	AppliedAt  *time.Time `gorm:"column:applied_at" json:"applied_at"`
+}
+
+func (c *TrxUploadPreview) TableName() string {
+	return "trx_upload_preview"
+}
+
+type TrxSlaAging struct {
+	ProspectID     string     `gorm:"type:varchar(20);column:ProspectID;primary_key:true"`
+	Stage          string     `gorm:"type:varchar(3);column:stage"`
+	StageEnteredAt time.Time  `gorm:"column:stage_entered_at"`
+	AgingMinutes   int        `gorm:"column:aging_minutes"`
+	SlaMinutes     int        `gorm:"column:sla_minutes"`
+	IsBreached     bool       `gorm:"column:is_breached"`
+	EscalatedAt    *time.Time `gorm:"column:escalated_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at"`
+}
+
+func (c *TrxSlaAging) TableName() string {
+	return "trx_sla_aging"
+}
+
+type TrxOrderClaim struct {
+	ProspectID     string    `gorm:"type:varchar(20);column:ProspectID;primary_key:true" json:"prospect_id"`
+	ClaimedBy      string    `gorm:"type:varchar(100);column:claimed_by" json:"claimed_by"`
+	ClaimedByName  string    `gorm:"type:varchar(250);column:claimed_by_name" json:"claimed_by_name"`
+	ClaimedAt      time.Time `gorm:"column:claimed_at" json:"claimed_at"`
+	LastActivityAt time.Time `gorm:"column:last_activity_at" json:"last_activity_at"`
+}
+
+func (c *TrxOrderClaim) TableName() string {
+	return "trx_order_claim"
+}
+
+type TrxOrderClaimHistory struct {
+	ID         string    `gorm:"type:varchar(50);column:id;primary_key:true"`
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID"`
+	Action     string    `gorm:"type:varchar(15);column:action"`
+	HolderBy   string    `gorm:"type:varchar(100);column:holder_by"`
+	UserID     string    `gorm:"type:varchar(100);column:user_id"`
+	UserName   string    `gorm:"type:varchar(250);column:user_name"`
+	RoleAlias  string    `gorm:"type:varchar(10);column:role_alias"`
+	Reason     string    `gorm:"type:varchar(250);column:reason"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (c *TrxOrderClaimHistory) TableName() string {
+	return "trx_order_claim_history"
+}
+
+type OrderClaim struct {
+	ClaimedBy     string `json:"claimed_by"`
+	ClaimedByName string `json:"claimed_by_name"`
+	ExpiredAt     string `json:"expired_at"`
+}
+
+// OpenOrderStage is an order waiting in a stage, the stage starts at the last trx_status change
+type OpenOrderStage struct {
+	ProspectID     string     `gorm:"column:ProspectID"`
+	BranchID       string     `gorm:"column:BranchID"`
+	Stage          string     `gorm:"column:stage"`
+	StageEnteredAt time.Time  `gorm:"column:stage_entered_at"`
+	EscalatedAt    *time.Time `gorm:"column:escalated_at"`
+}
+
+type SlaAging struct {
+	Stage          string `json:"stage"`
+	StageEnteredAt string `json:"stage_entered_at"`
+	AgingMinutes   int    `json:"aging_minutes"`
+	SlaMinutes     int    `json:"sla_minutes"`
+	IsBreached     bool   `json:"is_breached"`
+}
+
+type InquiryLockSystem struct {
+	LockType       string    `gorm:"column:lock_type" json:"lock_type"`
+	BannedType     string    `gorm:"column:banned_type" json:"banned_type"`
+	ProspectID     string    `gorm:"column:ProspectID" json:"prospect_id"`
+	IDNumber       string    `gorm:"column:IDNumber" json:"id_number"`
+	ChassisNumber  string    `gorm:"column:chassis_number" json:"chassis_number"`
+	EngineNumber   string    `gorm:"column:engine_number" json:"engine_number"`
+	Reason         string    `gorm:"column:reason" json:"reason"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
+	UnbanDate      time.Time `gorm:"column:unban_date" json:"unban_date"`
+	IsActive       bool      `gorm:"column:is_active" json:"is_active"`
+	OverrideAction string    `gorm:"column:override_action" json:"override_action"`
+	OverrideReason string    `gorm:"column:override_reason" json:"override_reason"`
+	OverrideBy     string    `gorm:"column:override_by" json:"override_by"`
+}
+
 type AssetAgreementData struct {
 	ProspectID              string     `gorm:"column:ProspectID" json:"prospect_id"`
 	ChassisNumber           string     `gorm:"column:chassis_number" json:"chassis_number"`
@@ -1103,6 +1259,163 @@ func (c *TrxWorker) TableName() string {
 	return "trx_worker"
 }
 
+type DashboardOrderStage struct {
+	Stage         string `gorm:"column:source_decision" json:"stage"`
+	StatusProcess string `gorm:"column:status_process" json:"status_process"`
+	Decision      string `gorm:"column:decision" json:"decision"`
+	Total         int    `gorm:"column:total" json:"total"`
+}
+
+type DashboardAliasDecision struct {
+	Alias    string `gorm:"column:source_decision"`
+	Decision string `gorm:"column:decision"`
+	Total    int    `gorm:"column:total"`
+}
+
+type DashboardRejection struct {
+	RuleCode string `gorm:"column:rule_code" json:"rule_code"`
+	Reason   string `gorm:"column:reason" json:"reason"`
+	Total    int    `gorm:"column:total" json:"total"`
+}
+
+type DashboardQuotaDeviasi struct {
+	QuotaAmount    float64 `gorm:"column:quota_amount"`
+	QuotaAccount   int     `gorm:"column:quota_account"`
+	BookingAmount  float64 `gorm:"column:booking_amount"`
+	BookingAccount int     `gorm:"column:booking_account"`
+}
+
+type DashboardTimeToDecision struct {
+	TotalDecided   int     `gorm:"column:total_decided"`
+	AverageMinutes float64 `gorm:"column:average_minutes"`
+}
+
+type DashboardStats struct {
+	Orders         []DashboardOrderStage
+	AliasDecisions []DashboardAliasDecision
+	Rejections     []DashboardRejection
+	QuotaDeviasi   DashboardQuotaDeviasi
+	TimeToDecision DashboardTimeToDecision
+}
+
+type CustomerBlindIndexSource struct {
+	ProspectID  string `gorm:"column:ProspectID"`
+	IDNumber    string `gorm:"column:IDNumber"`
+	LegalName   string `gorm:"column:LegalName"`
+	MobilePhone string `gorm:"column:MobilePhone"`
+}
+
+type TrxNEDraft struct {
+	ID            string    `gorm:"type:varchar(50);column:id;primary_key:true"`
+	CreatedBy     string    `gorm:"type:varchar(100);column:created_by"`
+	CreatedByName string    `gorm:"type:varchar(250);column:created_by_name"`
+	BranchID      string    `gorm:"type:varchar(10);column:BranchID"`
+	ProspectID    string    `gorm:"type:varchar(20);column:ProspectID"`
+	Payload       string    `gorm:"type:text;column:payload"`
+	Status        string    `gorm:"type:varchar(10);column:status"`
+	CreatedAt     time.Time `gorm:"column:created_at"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+func (c *TrxNEDraft) TableName() string {
+	return "trx_ne_draft"
+}
+
+type InquiryNEDraft struct {
+	ID         string    `gorm:"column:id" json:"id"`
+	BranchID   string    `gorm:"column:BranchID" json:"branch_id"`
+	ProspectID string    `gorm:"column:ProspectID" json:"prospect_id"`
+	Status     string    `gorm:"column:status" json:"status"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+type NotificationEvent struct {
+	EventType  string                 `json:"event_type"`
+	ProspectID string                 `json:"prospect_id"`
+	BranchID   string                 `json:"branch_id"`
+	Alias      string                 `json:"alias"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+type NotificationMessage struct {
+	EventType  string
+	ProspectID string
+	Recipient  MappingNotificationRecipient
+	Subject    string
+	Body       string
+}
+
+type MappingNotificationRecipient struct {
+	UserID   string `gorm:"type:varchar(20);column:user_id"`
+	UserName string `gorm:"type:varchar(250);column:user_name"`
+	Email    string `gorm:"type:varchar(100);column:email"`
+	Alias    string `gorm:"type:varchar(3);column:alias"`
+	BranchID string `gorm:"type:varchar(10);column:BranchID"`
+	IsActive bool   `gorm:"column:is_active"`
+}
+
+func (c *MappingNotificationRecipient) TableName() string {
+	return "m_notification_recipient"
+}
+
+type MappingNotificationTemplate struct {
+	EventType string `gorm:"type:varchar(30);column:event_type"`
+	Subject   string `gorm:"type:varchar(200);column:subject"`
+	Body      string `gorm:"type:text;column:body"`
+	IsActive  bool   `gorm:"column:is_active"`
+}
+
+func (c *MappingNotificationTemplate) TableName() string {
+	return "m_notification_template"
+}
+
+type MappingNotificationPreference struct {
+	UserID    string    `gorm:"type:varchar(20);column:user_id" json:"user_id"`
+	EventType string    `gorm:"type:varchar(30);column:event_type" json:"event_type"`
+	Channel   string    `gorm:"type:varchar(10);column:channel" json:"channel"`
+	IsMuted   bool      `gorm:"column:is_muted" json:"is_muted"`
+	UpdatedBy string    `gorm:"type:varchar(100);column:updated_by" json:"updated_by"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (c *MappingNotificationPreference) TableName() string {
+	return "m_notification_preference"
+}
+
+type TrxNotification struct {
+	ID         string    `gorm:"type:varchar(50);column:id;primary_key:true"`
+	EventType  string    `gorm:"type:varchar(30);column:event_type"`
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID"`
+	UserID     string    `gorm:"type:varchar(20);column:user_id"`
+	Channel    string    `gorm:"type:varchar(10);column:channel"`
+	Subject    string    `gorm:"type:varchar(200);column:subject"`
+	Body       string    `gorm:"type:text;column:body"`
+	Status     string    `gorm:"type:varchar(10);column:status"`
+	Error      string    `gorm:"type:text;column:error"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (c *TrxNotification) TableName() string {
+	return "trx_notification"
+}
+
+type InquiryWorker struct {
+	ProspectID     string `gorm:"column:ProspectID" json:"prospect_id"`
+	Category       string `gorm:"column:category" json:"category"`
+	Action         string `gorm:"column:action" json:"action"`
+	Activity       string `gorm:"column:activity" json:"activity"`
+	EndPointTarget string `gorm:"column:endpoint_target" json:"endpoint_target"`
+	EndPointMethod string `gorm:"column:endpoint_method" json:"endpoint_method"`
+	MaxRetry       int    `gorm:"column:max_retry" json:"max_retry"`
+	CountRetry     int    `gorm:"column:count_retry" json:"count_retry"`
+	StatusCode     string `gorm:"column:status_code" json:"status_code"`
+	LastError      string `gorm:"column:last_error" json:"last_error"`
+	NextRetryAt    string `gorm:"column:next_retry_at" json:"next_retry_at"`
+	LockedBy       string `gorm:"column:locked_by" json:"locked_by"`
+	CreatedAt      string `gorm:"column:created_at" json:"created_at"`
+}
+
 type LogOrchestrator struct {
 	ID           string    `gorm:"type:varchar(50);column:id;primary_key:true"`
 	ProspectID   string    `gorm:"type:varchar(20);column:ProspectID"`
@@ -1130,6 +1443,17 @@ func (c *TrxJourney) TableName() string {
 	return "trx_journey"
 }
 
+type TrxJourneyCheckpoint struct {
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID"`
+	Stage      string    `gorm:"type:varchar(20);column:stage"`
+	Result     string    `gorm:"type:text;column:result"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (c *TrxJourneyCheckpoint) TableName() string {
+	return "trx_journey_checkpoint"
+}
+
 type TrxPrescreening struct {
 	ProspectID string    `gorm:"column:ProspectID"`
 	Decision   string    `gorm:"column:decision"`
@@ -1424,6 +1748,8 @@ type InquiryPrescreening struct {
 	DecisionBy        string `gorm:"column:DecisionBy"`
 	DecisionName      string `gorm:"column:DecisionName"`
 	DecisionAt        string `gorm:"column:DecisionAt"`
+	AutoDecision      string `gorm:"column:auto_decision"`
+	AutoRuleCode      string `gorm:"column:auto_rule_code"`
 
 	ProspectID     string `gorm:"column:ProspectID"`
 	BranchName     string `gorm:"column:BranchName"`
@@ -1551,6 +1877,26 @@ func (c *CancelReason) TableName() string {
 	return "m_cancel_reason"
 }
 
+type MasterData struct {
+	ID          string     `gorm:"column:id" json:"id"`
+	Group       string     `gorm:"column:group_id" json:"group,omitempty"`
+	Code        string     `gorm:"column:code" json:"code,omitempty"`
+	Description string     `gorm:"column:description" json:"description"`
+	SortOrder   int        `gorm:"column:sort_order" json:"sort_order"`
+	IsActive    bool       `gorm:"column:is_active" json:"is_active"`
+	UpdatedBy   string     `gorm:"column:updated_by" json:"updated_by"`
+	UpdatedAt   *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+type MasterDataChangeLog struct {
+	ID         string    `gorm:"column:id" json:"id"`
+	Action     string    `gorm:"column:action" json:"action"`
+	DataBefore string    `gorm:"column:data_before" json:"data_before"`
+	DataAfter  string    `gorm:"column:data_after" json:"data_after"`
+	UserName   string    `gorm:"column:user_name" json:"user_name"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
 type InquiryDataNE struct {
 	ProspectID      string `gorm:"type:varchar(20);column:ProspectID" json:"prospect_id"`
 	BranchID        string `gorm:"type:varchar(3);column:BranchID" json:"branch_id"`
@@ -1607,6 +1953,8 @@ type DataPrescreening struct {
 	DecisionBy        string `gorm:"column:DecisionBy" json:"decision_by"`
 	DecisionName      string `gorm:"column:DecisionName" json:"decision_by_name"`
 	DecisionAt        string `gorm:"column:DecisionAt" json:"decision_at"`
+	AutoDecision      string `gorm:"column:auto_decision" json:"auto_decision"`
+	AutoRuleCode      string `gorm:"column:auto_rule_code" json:"auto_rule_code"`
 }
 
 type DataGeneral struct {
@@ -1881,6 +2229,12 @@ type ListDatatableCa struct {
 	DraftPernyataan4   interface{} `gorm:"column:draft_pernyataan_4"`
 	DraftPernyataan5   interface{} `gorm:"column:draft_pernyataan_5"`
 	DraftPernyataan6   interface{} `gorm:"column:draft_pernyataan_6"`
+	Stage              string      `gorm:"column:stage"`
+	StageStatus        string      `gorm:"column:stage_status"`
+	StageEnteredAt     time.Time   `gorm:"column:stage_entered_at"`
+	ClaimedBy          string      `gorm:"column:claimed_by"`
+	ClaimedByName      string      `gorm:"column:claimed_by_name"`
+	ClaimActivityAt    time.Time   `gorm:"column:claim_activity_at"`
 	DeviasiID          string      `gorm:"column:deviasi_id"`
 	DeviasiDescription string      `gorm:"column:deviasi_description"`
 	DeviasiDecision    string      `gorm:"column:deviasi_decision"`
@@ -1902,6 +2256,8 @@ type RespDatatableCA struct {
 	ShowAction     bool               `json:"show_action"`
 	Draft          TrxDraftCaDecision `json:"draft"`
 	Deviasi        Deviasi            `json:"deviasi"`
+	Aging          *SlaAging          `json:"aging"`
+	Claim          *OrderClaim        `json:"claim"`
 }
 
 type InquiryDataCa struct {
@@ -1953,6 +2309,13 @@ func (c *TrxCaDecision) TableName() string {
 	return "trx_ca_decision"
 }
 
+type ApprovalState struct {
+	StatusProcess  string `gorm:"column:status_process"`
+	Decision       string `gorm:"column:decision"`
+	SourceDecision string `gorm:"column:source_decision"`
+	FinalApproval  string `gorm:"column:final_approval"`
+}
+
 type MappingLimitApprovalScheme struct {
 	ID               string    `gorm:"type:varchar(60);column:id"`
 	Alias            string    `gorm:"type:varchar(3);column:alias"`
@@ -2202,6 +2565,9 @@ type ListDatatableApproval struct {
 	DeviasiDescription string    `gorm:"deviasi_description"`
 	DeviasiDecision    string    `gorm:"deviasi_decision"`
 	DeviasiReason      string    `gorm:"deviasi_reason"`
+	Stage              string    `gorm:"column:stage"`
+	StageStatus        string    `gorm:"column:stage_status"`
+	StageEnteredAt     time.Time `gorm:"column:stage_entered_at"`
 }
 
 type RespDatatableApproval struct {
@@ -2217,6 +2583,7 @@ type RespDatatableApproval struct {
 	ActionFormAkk  bool        `json:"action_form_akk"`
 	UrlFormAkkk    string      `json:"url_form_akkk"`
 	Deviasi        Deviasi     `json:"deviasi"`
+	Aging          *SlaAging   `json:"aging"`
 }
 
 type InquiryDataApproval struct {
@@ -2305,6 +2672,118 @@ func (c *TrxRecalculate) TableName() string {
 	return "trx_recalculate"
 }
 
+type TrxRecalculateHistory struct {
+	ID         string    `gorm:"type:varchar(50);column:id;primary_key:true"`
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID"`
+	DataBefore string    `gorm:"type:text;column:data_before"`
+	DataAfter  string    `gorm:"type:text;column:data_after"`
+	CreatedBy  string    `gorm:"type:varchar(100);column:created_by"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (c *TrxRecalculateHistory) TableName() string {
+	return "trx_recalculate_history"
+}
+
+type TrxPIIAccessLog struct {
+	ID         string    `gorm:"type:varchar(50);column:id;primary_key:true"`
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID"`
+	UserID     string    `gorm:"type:varchar(50);column:user_id"`
+	Role       string    `gorm:"type:varchar(20);column:role"`
+	Purpose    string    `gorm:"type:varchar(30);column:purpose"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+func (c *TrxPIIAccessLog) TableName() string {
+	return "trx_pii_access_log"
+}
+
+type TrxPrescreeningAutoDecision struct {
+	ID          string     `gorm:"type:varchar(50);column:id;primary_key:true"`
+	ProspectID  string     `gorm:"type:varchar(20);column:ProspectID"`
+	Result      string     `gorm:"type:varchar(10);column:result"`
+	RuleCode    string     `gorm:"type:varchar(20);column:rule_code"`
+	Note        string     `gorm:"type:varchar(255);column:note"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	PublishedAt *time.Time `gorm:"column:published_at"`
+}
+
+func (c *TrxPrescreeningAutoDecision) TableName() string {
+	return "trx_prescreening_auto_decision"
+}
+
+// PrescreeningCandidate holds the facts of an order waiting in prescreening that the auto decision rules look at
+type PrescreeningCandidate struct {
+	DecisionID      string  `gorm:"column:decision_id"`
+	ProspectID      string  `gorm:"column:ProspectID"`
+	CustomerStatus  string  `gorm:"column:customer_status"`
+	CustomerSegment string  `gorm:"column:customer_segment"`
+	OTR             float64 `gorm:"column:OTR"`
+	NTF             float64 `gorm:"column:NTF"`
+	TotalSurveyor   int     `gorm:"column:total_surveyor"`
+	Photos          string  `gorm:"column:photos"`
+}
+
+type TrxStagingJob struct {
+	ID         string     `gorm:"type:varchar(50);column:id;primary_key:true" json:"job_id"`
+	Status     string     `gorm:"type:varchar(10);column:status" json:"status"`
+	TotalItem  int        `gorm:"column:total_item" json:"total_item"`
+	CreatedBy  string     `gorm:"type:varchar(100);column:created_by" json:"created_by"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`
+}
+
+func (c *TrxStagingJob) TableName() string {
+	return "trx_staging_job"
+}
+
+type TrxStagingJobItem struct {
+	ID         string    `gorm:"type:varchar(50);column:id;primary_key:true" json:"-"`
+	JobID      string    `gorm:"type:varchar(50);column:job_id" json:"-"`
+	ProspectID string    `gorm:"type:varchar(20);column:ProspectID" json:"prospect_id"`
+	Status     string    `gorm:"type:varchar(10);column:status" json:"status"`
+	Error      string    `gorm:"type:varchar(500);column:error" json:"error"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (c *TrxStagingJobItem) TableName() string {
+	return "trx_staging_job_item"
+}
+
+type StagingJobCount struct {
+	Status string `gorm:"column:status"`
+	Total  int    `gorm:"column:total"`
+}
+
+type StagingTableCount struct {
+	ProspectID string `gorm:"column:ProspectID"`
+	Table      string `gorm:"column:table_name"`
+	Total      int    `gorm:"column:total"`
+}
+
+type StagingBranch struct {
+	ProspectID string `gorm:"column:ProspectID"`
+	BranchID   string `gorm:"column:BranchID"`
+}
+
+type RecalculateLimit struct {
+	OTR                          float64 `gorm:"column:OTR"`
+	MaxLTV                       float64 `gorm:"column:max_ltv"`
+	CustomerStatus               string  `gorm:"column:customer_status"`
+	CustomerSegment              string  `gorm:"column:customer_segment"`
+	Cluster                      string  `gorm:"column:cluster"`
+	MonthlyFixedIncome           float64 `gorm:"column:MonthlyFixedIncome"`
+	MonthlyVariableIncome        float64 `gorm:"column:MonthlyVariableIncome"`
+	SpouseIncome                 float64 `gorm:"column:SpouseIncome"`
+	InstallmentAmountFMF         float64 `gorm:"column:InstallmentAmountFMF"`
+	InstallmentAmountSpouseFMF   float64 `gorm:"column:InstallmentAmountSpouseFMF"`
+	InstallmentAmountOther       float64 `gorm:"column:InstallmentAmountOther"`
+	InstallmentAmountOtherSpouse float64 `gorm:"column:InstallmentAmountOtherSpouse"`
+	InstallmentTopup             float64 `gorm:"column:InstallmentTopup"`
+	DSRPBK                       float64 `gorm:"column:DSRPBK"`
+}
+
 type GetRecalculate struct {
 	ProspectID          string  `gorm:"type:varchar(20);column:ProspectID;primary_key:true"`
 	ProductOfferingID   string  `gorm:"type:varchar(30);column:ProductOfferingID"`
@@ -2745,6 +3224,24 @@ func (c *HistoryConfigChanges) TableName() string {
 	return "history_config_changes"
 }
 
+// TrxHistoryConfigOutbox holds a history_config_changes row written in the new_kmb transaction of the change until it
+// is copied to los
+type TrxHistoryConfigOutbox struct {
+	ID         string     `gorm:"type:varchar(50);column:id;primary_key:true"`
+	ConfigID   string     `gorm:"type:varchar(50);column:config_id"`
+	ObjectName string     `gorm:"type:varchar(50);column:object_name"`
+	Action     string     `gorm:"type:varchar(10);column:action"`
+	DataBefore string     `gorm:"type:text;column:data_before"`
+	DataAfter  string     `gorm:"type:text;column:data_after"`
+	CreatedBy  string     `gorm:"type:varchar(20);column:created_by"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	SyncedAt   *time.Time `gorm:"column:synced_at"`
+}
+
+func (c *TrxHistoryConfigOutbox) TableName() string {
+	return "trx_history_config_outbox"
+}
+
 type ConfinsBranch struct {
 	BranchID   string `gorm:"type:varchar(10);column:BranchID" json:"branch_id"`
 	BranchName string `gorm:"type:varchar(200);column:BranchName" json:"branch_name"`
@@ -2756,12 +3253,38 @@ func (c *ConfinsBranch) TableName() string {
 
 type MappingClusterChangeLog struct {
 	ID         string `json:"id"`
+	Action     string `json:"action"`
 	DataBefore string `json:"data_before"`
 	DataAfter  string `json:"data_after"`
 	UserName   string `json:"user_name"`
 	CreatedAt  string `json:"created_at"`
 }
 
+type MappingClusterVersion struct {
+	ID           string    `gorm:"type:varchar(50);column:id"`
+	Version      int       `gorm:"column:version"`
+	Source       string    `gorm:"type:varchar(10);column:source"`
+	RollbackFrom *int      `gorm:"column:rollback_from"`
+	TotalRows    int       `gorm:"column:total_rows"`
+	Data         string    `gorm:"type:text;column:data"`
+	HistoryID    string    `gorm:"type:varchar(50);column:history_id"`
+	CreatedBy    string    `gorm:"type:varchar(20);column:created_by"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+}
+
+func (c *MappingClusterVersion) TableName() string {
+	return "kmb_mapping_cluster_version"
+}
+
+type InquiryMappingClusterVersion struct {
+	Version      int    `gorm:"column:version" json:"version"`
+	Source       string `gorm:"column:source" json:"source"`
+	RollbackFrom *int   `gorm:"column:rollback_from" json:"rollback_from"`
+	TotalRows    int    `gorm:"column:total_rows" json:"total_rows"`
+	UserName     string `gorm:"column:user_name" json:"user_name"`
+	CreatedAt    string `gorm:"column:created_at" json:"created_at"`
+}
+
 type MasterMappingFpdCluster struct {
 	Cluster     string    `gorm:"column:cluster"`
 	FpdStartHte float64   `gorm:"column:fpd_start_hte"`
@@ -2908,6 +3431,70 @@ func (c *MappingBranchDeviasi) TableName() string {
 	return "m_branch_deviasi"
 }
 
+// TrxQuotaDeviasiLedger records every booking and release of the quota deviasi, the booking of a period is the sum of its rows
+type TrxQuotaDeviasiLedger struct {
+	ID          string     `gorm:"type:varchar(50);column:id;primary_key:true" json:"id"`
+	ProspectID  string     `gorm:"type:varchar(20);column:ProspectID" json:"prospect_id"`
+	BranchID    string     `gorm:"type:varchar(10);column:BranchID" json:"branch_id"`
+	PeriodStart *time.Time `gorm:"column:period_start" json:"period_start"`
+	Action      string     `gorm:"type:varchar(10);column:action" json:"action"`
+	Amount      float64    `gorm:"column:amount" json:"amount"`
+	Account     int        `gorm:"column:account" json:"account"`
+	Reason      string     `gorm:"type:varchar(20);column:reason" json:"reason"`
+	CreatedBy   string     `gorm:"type:varchar(100);column:created_by" json:"created_by"`
+	CreatedAt   time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (c *TrxQuotaDeviasiLedger) TableName() string {
+	return "trx_quota_deviasi_ledger"
+}
+
+// TrxQuotaDeviasiPeriod keeps the totals of a branch at the moment its period was closed by a reset
+type TrxQuotaDeviasiPeriod struct {
+	ID             string     `gorm:"type:varchar(50);column:id;primary_key:true" json:"id"`
+	BranchID       string     `gorm:"type:varchar(10);column:BranchID" json:"branch_id"`
+	PeriodStart    *time.Time `gorm:"column:period_start" json:"period_start"`
+	PeriodEnd      time.Time  `gorm:"column:period_end" json:"period_end"`
+	QuotaAmount    float64    `gorm:"column:quota_amount" json:"quota_amount"`
+	QuotaAccount   int        `gorm:"column:quota_account" json:"quota_account"`
+	BookingAmount  float64    `gorm:"column:booking_amount" json:"booking_amount"`
+	BookingAccount int        `gorm:"column:booking_account" json:"booking_account"`
+	BalanceAmount  float64    `gorm:"column:balance_amount" json:"balance_amount"`
+	BalanceAccount int        `gorm:"column:balance_account" json:"balance_account"`
+	ResetType      string     `gorm:"type:varchar(10);column:reset_type" json:"reset_type"`
+	CreatedBy      string     `gorm:"type:varchar(255);column:created_by" json:"created_by"`
+	CreatedAt      time.Time  `gorm:"column:created_at" json:"created_at"`
+}
+
+func (c *TrxQuotaDeviasiPeriod) TableName() string {
+	return "trx_quota_deviasi_period"
+}
+
+// QuotaDeviasiBooking is the last booking of an order with the number of bookings and releases it has in the ledger
+type QuotaDeviasiBooking struct {
+	BranchID     string  `gorm:"column:BranchID"`
+	Amount       float64 `gorm:"column:amount"`
+	SamePeriod   bool    `gorm:"column:same_period"`
+	BookingCount int     `gorm:"column:booking_count"`
+	ReleaseCount int     `gorm:"column:release_count"`
+}
+
+type InquiryQuotaDeviasiConsumption struct {
+	BranchID       string  `gorm:"column:BranchID" json:"branch_id"`
+	BranchName     string  `gorm:"column:branch_name" json:"branch_name"`
+	PeriodStart    string  `gorm:"column:period_start" json:"period_start"`
+	PeriodEnd      string  `gorm:"column:period_end" json:"period_end"`
+	QuotaAmount    float64 `gorm:"column:quota_amount" json:"quota_amount"`
+	QuotaAccount   int     `gorm:"column:quota_account" json:"quota_account"`
+	BookingAmount  float64 `gorm:"column:booking_amount" json:"booking_amount"`
+	BookingAccount int     `gorm:"column:booking_account" json:"booking_account"`
+	ReleaseAmount  float64 `gorm:"column:release_amount" json:"release_amount"`
+	ReleaseAccount int     `gorm:"column:release_account" json:"release_account"`
+	UsedAmount     float64 `gorm:"column:used_amount" json:"used_amount"`
+	UsedAccount    int     `gorm:"column:used_account" json:"used_account"`
+	ResetType      string  `gorm:"column:reset_type" json:"reset_type"`
+}
+
 type MasterMappingDeviasiDSR struct {
 	TotalIncomeStart float64 `gorm:"column:total_income_start"`
 	TotalIncomeEnd   float64 `gorm:"column:total_income_end"`
@@ -2926,6 +3513,9 @@ type MappingVehicleAge struct {
 	TenorStart      int         `gorm:"column:tenor_start"`
 	TenorEnd        int         `gorm:"column:tenor_end"`
 	Decision        string      `gorm:"type:varchar(20);column:decision"`
+	ResultPbk       string      `gorm:"column:result_pbk"`
+	AFStart         float64     `gorm:"column:af_start"`
+	AFEnd           float64     `gorm:"column:af_end"`
 	CreatedAt       time.Time   `gorm:"type:datetime2(2);column:created_at"`
 	Info            interface{} `gorm:"column:info"`
 }
@@ -1,6 +1,8 @@
 package request
 
 import (
+	"encoding/json"
+	"io"
 	"los-kmb-api/models/entity"
 	"time"
 )
@@ -332,6 +334,34 @@ type Dsr struct {
 	Spouse                         *DsrSpouse `json:"spouse"`
 }
 
+type DsrCalculate struct {
+	ProspectID                   string        `json:"prospect_id" validate:"required,max=20" example:"SAL042600001"`
+	CustomerStatus               string        `json:"customer_status" validate:"required" example:"NEW"`
+	CustomerSegment              string        `json:"customer_segment" example:"REGULAR"`
+	Cluster                      string        `json:"cluster" example:"Cluster A"`
+	MonthlyFixedIncome           float64       `json:"monthly_fixed_income" validate:"required,gt=0"`
+	MonthlyVariableIncome        float64       `json:"monthly_variable_income" validate:"min=0"`
+	SpouseIncome                 float64       `json:"spouse_income" validate:"min=0"`
+	NewInstallment               float64       `json:"new_installment" validate:"required,gt=0"`
+	InstallmentAmountFMF         float64       `json:"installment_amount_fmf" validate:"min=0"`
+	InstallmentAmountSpouseFMF   float64       `json:"installment_amount_spouse_fmf" validate:"min=0"`
+	InstallmentAmountOther       float64       `json:"installment_amount_other" validate:"min=0"`
+	InstallmentAmountOtherSpouse float64       `json:"installment_amount_other_spouse" validate:"min=0"`
+	InstallmentTopup             float64       `json:"installment_topup" validate:"min=0"`
+	InstallmentPBK               float64       `json:"installment_pbk" validate:"min=0"`
+	ConfigMaxDSR                 float64       `json:"config_max_dsr" validate:"min=0"`
+	BranchID                     string        `json:"branch_id" example:"426"`
+	NTF                          float64       `json:"ntf" validate:"min=0"`
+	AgreementChassisNumber       *DsrAgreement `json:"agreement_chassis_number"`
+}
+
+// DsrAgreement is the agreement financing the same chassis, its installment is replaced by the new installment
+type DsrAgreement struct {
+	InstallmentAmount float64 `json:"installment_amount" validate:"min=0"`
+	IsActive          bool    `json:"is_active"`
+	IsRegistered      bool    `json:"is_registered"`
+}
+
 type DsrSpouse struct {
 	IDNumber   string `json:"id_number"`
 	LegalName  string `json:"legal_name"`
@@ -354,6 +384,16 @@ type AfterPrescreening struct {
 	ProspectID string `json:"prospect_id" validate:"required,max=20" example:"SAL042600001"`
 }
 
+type ResumeJourney struct {
+	ProspectID string `json:"prospect_id" validate:"required,max=20" example:"SAL042600001"`
+}
+
+type ReleaseQuotaDeviasi struct {
+	ProspectID string `json:"prospect_id" validate:"required,max=20" example:"SAL042600001"`
+	Reason     string `json:"reason" validate:"required" example:"REJECT"`
+	ReleasedBy string `json:"released_by" validate:"required" example:"SYSTEM"`
+}
+
 type MetricsEkyc struct {
 	CustomerStatus  string
 	CustomerSegment string
@@ -731,6 +771,13 @@ type RequestGenerateFormAKKK struct {
 	Source     string `json:"source" example:"SYSTEM"`
 }
 
+type ReqMediaUpload struct {
+	FileName    string
+	ContentType string
+	Content     io.Reader
+	Type        string
+}
+
 type Filtering struct {
 	ProspectID    string           `json:"prospect_id" validate:"prospect_id" example:"SAL042600001"`
 	BranchID      string           `json:"branch_id" validate:"required,branch_id" example:"426"`
@@ -760,6 +807,12 @@ type ElaborateLTV struct {
 	ManufacturingYear string `json:"manufacturing_year" validate:"required,len=4,number"`
 }
 
+type ElaborateLTVQuote struct {
+	ProspectID         string   `json:"prospect_id" validate:"prospect_id"`
+	Tenors             []int    `json:"tenors" validate:"omitempty,max=60,dive,min=1,max=60"`
+	ManufacturingYears []string `json:"manufacturing_years" validate:"omitempty,max=30,dive,len=4,number"`
+}
+
 type SallyFilteringCallback struct {
 	ProspectID      string      `json:"prospect_id"`
 	Decision        string      `json:"decision"`
@@ -892,6 +945,34 @@ type ReqApprovalReason struct {
 	Type string `json:"type" validate:"required"`
 }
 
+type ReqListMasterData struct {
+	Type string `json:"type" query:"type" validate:"required,oneof=cancel_reason approval_reason prescreening_reason industry_type" example:"cancel_reason"`
+}
+
+type ReqMasterData struct {
+	Type        string `json:"type" validate:"required,oneof=cancel_reason approval_reason prescreening_reason industry_type" example:"approval_reason"`
+	ID          string `json:"id" validate:"required,max=20,noHTML" example:"AR01"`
+	Group       string `json:"group" validate:"required_if=Type approval_reason,max=20,noHTML" example:"REJ"`
+	Code        string `json:"code" validate:"required_if=Type prescreening_reason,max=20,noHTML" example:"2701"`
+	Description string `json:"description" validate:"required,max=250,noHTML" example:"Kapasitas tidak mencukupi"`
+	SortOrder   int    `json:"sort_order" validate:"min=0,max=9999" example:"1"`
+	UpdatedBy   string `json:"updated_by" validate:"required,max=20" example:"93510"`
+}
+
+type ReqDeleteMasterData struct {
+	Type      string `json:"type" validate:"required,oneof=cancel_reason approval_reason prescreening_reason industry_type" example:"approval_reason"`
+	ID        string `json:"id" validate:"required,max=20" example:"AR01"`
+	Group     string `json:"group" validate:"required_if=Type approval_reason,max=20" example:"REJ"`
+	UpdatedBy string `json:"updated_by" validate:"required,max=20" example:"93510"`
+}
+
+type ReqReorderMasterData struct {
+	Type      string   `json:"type" validate:"required,oneof=cancel_reason approval_reason prescreening_reason industry_type" example:"approval_reason"`
+	Group     string   `json:"group" validate:"required_if=Type approval_reason,max=20" example:"REJ"`
+	IDs       []string `json:"ids" validate:"required,min=1,dive,required,max=20"`
+	UpdatedBy string   `json:"updated_by" validate:"required,max=20" example:"93510"`
+}
+
 type ReqReviewPrescreening struct {
 	ProspectID     string `json:"prospect_id" validate:"required,max=20" example:"TEST-DEV"`
 	Decision       string `json:"decision" validate:"required,decision,max=7" example:"APPROVE,REJECT"`
@@ -901,13 +982,15 @@ type ReqReviewPrescreening struct {
 }
 
 type ReqInquiryCa struct {
-	SearchBy     string `json:"search_by"`
-	SearchValue  string `json:"search_value"`
-	BranchFilter string `json:"branch_filter"`
-	StatusFilter string `json:"status_filter"`
-	BranchID     string `json:"branch_id" validate:"required,max=3"`
-	MultiBranch  string `json:"multi_branch" validate:"required,max=1"`
-	UserID       string `json:"user_id" validate:"required,max=20"`
+	SearchBy     string     `json:"search_by"`
+	SearchValue  string     `json:"search_value"`
+	BranchFilter string     `json:"branch_filter"`
+	StatusFilter string     `json:"status_filter"`
+	BranchID     string     `json:"branch_id" validate:"required,max=3"`
+	MultiBranch  string     `json:"multi_branch" validate:"required,max=1"`
+	UserID       string     `json:"user_id" validate:"required,max=20"`
+	AgingFilter  string     `json:"aging_filter" validate:"omitempty,oneof=BREACHED ON_TRACK"`
+	Session      PIISession `json:"-"`
 }
 
 type ReqAdditionalData struct {
@@ -968,11 +1051,77 @@ type ReqSubmitApproval struct {
 	DecisionBy     string  `json:"decision_by_name" validate:"required,max=250"`
 }
 
+type ReqBulkSubmitDecision struct {
+	ProspectIDs []string `json:"prospect_ids" validate:"required,min=1,max=50,unique,dive,required,max=20" example:"TEST-DEV-1,TEST-DEV-2"`
+	Decision    string   `json:"decision" validate:"required,decision,max=7" example:"APPROVE,REJECT"`
+	SlikResult  string   `json:"slik_result" validate:"required,max=30"`
+	Note        string   `json:"note" validate:"max=525,xss_validation"`
+	CreatedBy   string   `json:"decision_by" validate:"required,max=100"`
+	DecisionBy  string   `json:"decision_by_name" validate:"required,max=250"`
+}
+
+type ReqBulkSubmitApproval struct {
+	ProspectIDs    []string `json:"prospect_ids" validate:"required,min=1,max=50,unique,dive,required,max=20" example:"TEST-DEV-1,TEST-DEV-2"`
+	Decision       string   `json:"decision" validate:"required,oneof=APPROVE REJECT" example:"APPROVE,REJECT"`
+	RuleCode       string   `json:"code" validate:"required,max=4"`
+	Alias          string   `json:"alias" validate:"required,max=3"`
+	Reason         string   `json:"reason" validate:"required,max=100"`
+	NeedEscalation bool     `json:"need_escalation"`
+	Note           string   `json:"note" validate:"max=525"`
+	CreatedBy      string   `json:"decision_by" validate:"required,max=100"`
+	DecisionBy     string   `json:"decision_by_name" validate:"required,max=250"`
+}
+
 type ReqSearchInquiry struct {
-	UserID      string `json:"user_id" validate:"required,max=20"`
-	BranchID    string `json:"branch_id" validate:"required,max=3"`
-	MultiBranch string `json:"multi_branch" validate:"required,max=1"`
-	Search      string `json:"search" validate:"required"`
+	UserID      string     `json:"user_id" validate:"required,max=20"`
+	BranchID    string     `json:"branch_id" validate:"required,max=3"`
+	MultiBranch string     `json:"multi_branch" validate:"required,max=1"`
+	Search      string     `json:"search" validate:"required"`
+	Session     PIISession `json:"-"`
+}
+
+type ReqStagingJob struct {
+	ProspectIDs []string `json:"prospect_ids" validate:"omitempty,max=1000,dive,required,max=20"`
+	BranchID    string   `json:"branch_id" validate:"omitempty,max=3"`
+	DateFrom    string   `json:"date_from" validate:"required_without=ProspectIDs,omitempty,datetime=2006-01-02"`
+	DateTo      string   `json:"date_to" validate:"required_without=ProspectIDs,omitempty,datetime=2006-01-02"`
+	CreatedBy   string   `json:"created_by" validate:"required,max=100"`
+}
+
+type ReqStagingJobItems struct {
+	JobID  string `json:"job_id" validate:"required,max=50"`
+	Status string `json:"status" validate:"omitempty,oneof=PENDING SUCCESS SKIPPED FAILED"`
+}
+
+type ReqViewAkkk struct {
+	ProspectID string     `json:"prospect_id" validate:"required,max=20"`
+	Session    PIISession `json:"-"`
+}
+
+// PIISession is the caller taken from the cms token, never from the request, it decides whether personal data is shown unmasked
+type PIISession struct {
+	UserID string
+	Role   string
+}
+
+type ReqClaimOrder struct {
+	ProspectID    string `json:"prospect_id" validate:"required,max=20" example:"TEST-DEV"`
+	ClaimedBy     string `json:"claimed_by" validate:"required,max=100"`
+	ClaimedByName string `json:"claimed_by_name" validate:"required,max=250"`
+}
+
+type ReqReleaseOrder struct {
+	ProspectID     string `json:"prospect_id" validate:"required,max=20" example:"TEST-DEV"`
+	ReleasedBy     string `json:"released_by" validate:"required,max=100"`
+	ReleasedByName string `json:"released_by_name" validate:"required,max=250"`
+}
+
+type ReqForceReleaseOrder struct {
+	ProspectID     string `json:"prospect_id" validate:"required,max=20" example:"TEST-DEV"`
+	ReleasedBy     string `json:"released_by" validate:"required,max=100"`
+	ReleasedByName string `json:"released_by_name" validate:"required,max=250"`
+	Reason         string `json:"reason" validate:"required,max=250"`
+	RoleAlias      string `json:"-"`
 }
 
 type ReqCancelOrder struct {
@@ -996,14 +1145,16 @@ type ReqRecalculateOrder struct {
 }
 
 type ReqInquiryApproval struct {
-	SearchBy     string `json:"search_by"`
-	SearchValue  string `json:"search_value"`
-	BranchFilter string `json:"branch_filter"`
-	StatusFilter string `json:"status_filter"`
-	BranchID     string `json:"branch_id" validate:"required,max=3"`
-	MultiBranch  string `json:"multi_branch" validate:"required,max=1"`
-	UserID       string `json:"user_id" validate:"required,max=20"`
-	Alias        string `json:"alias" validate:"required,max=3"`
+	SearchBy     string     `json:"search_by"`
+	SearchValue  string     `json:"search_value"`
+	BranchFilter string     `json:"branch_filter"`
+	StatusFilter string     `json:"status_filter"`
+	BranchID     string     `json:"branch_id" validate:"required,max=3"`
+	MultiBranch  string     `json:"multi_branch" validate:"required,max=1"`
+	UserID       string     `json:"user_id" validate:"required,max=20"`
+	Alias        string     `json:"alias" validate:"required,max=3"`
+	AgingFilter  string     `json:"aging_filter" validate:"omitempty,oneof=BREACHED ON_TRACK"`
+	Session      PIISession `json:"-"`
 }
 
 type ReqListQuotaDeviasi struct {
@@ -1012,6 +1163,65 @@ type ReqListQuotaDeviasi struct {
 	IsActive string `json:"is_active" example:"1 / 0"`
 }
 
+type ReqListQuotaDeviasiConsumption struct {
+	BranchID string `json:"branch_id" example:"400"`
+}
+
+type ReqListWorker struct {
+	ProspectID string `json:"prospect_id" example:"SAL-1140024080800004"`
+	Category   string `json:"category" example:"FORM_AKKK_NKMB"`
+	Activity   string `json:"activity" example:"DEAD"`
+}
+
+type ReqDashboardStats struct {
+	UserID       string `query:"user_id" validate:"required"`
+	BranchID     string `query:"branch_id" validate:"required"`
+	MultiBranch  string `query:"multi_branch"`
+	BranchFilter string `query:"branch_filter"`
+	Region       string `query:"region"`
+	StartDate    string `query:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate      string `query:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+type ReqSaveNEDraft struct {
+	CreatedBy     string          `json:"created_by" validate:"required,max=100" example:"93510"`
+	CreatedByName string          `json:"created_by_name" validate:"required,max=250" example:"SETO MULYA"`
+	Data          json.RawMessage `json:"data" validate:"required" swaggertype:"object"`
+}
+
+type ReqUpdateNEDraft struct {
+	ID        string          `json:"id" validate:"required,max=50"`
+	CreatedBy string          `json:"created_by" validate:"required,max=100" example:"93510"`
+	Data      json.RawMessage `json:"data" validate:"required" swaggertype:"object"`
+}
+
+type ReqNEDraft struct {
+	ID        string `json:"id" param:"draft_id" validate:"required,max=50"`
+	CreatedBy string `json:"created_by" query:"created_by" validate:"required,max=100" example:"93510"`
+}
+
+type ReqListNEDraft struct {
+	CreatedBy string `json:"created_by" validate:"required,max=100" example:"93510"`
+}
+
+type ReqListNotificationPreference struct {
+	UserID string `query:"user_id" validate:"required,max=20"`
+}
+
+type ReqNotificationPreference struct {
+	UserID    string `json:"user_id" validate:"required,max=20" example:"81234"`
+	EventType string `json:"event_type" validate:"required,oneof=APPROVAL_ROUTED ORDER_RETURNED SLA_BREACHED FINAL_DECISION" example:"APPROVAL_ROUTED"`
+	Channel   string `json:"channel" validate:"required,oneof=ALL WEBHOOK SMTP INBOX" example:"ALL"`
+	IsMuted   bool   `json:"is_muted" example:"true"`
+	UpdatedBy string `json:"updated_by" validate:"required,max=100" example:"81234"`
+}
+
+type ReqRequeueWorker struct {
+	ProspectID string `json:"prospect_id" validate:"required,max=20" example:"SAL-1140024080800004"`
+	Category   string `json:"category" validate:"required,max=30" example:"FORM_AKKK_NKMB"`
+	Action     string `json:"action" validate:"required,max=50" example:"GENERATE_FORM_AKKK"`
+}
+
 type ReqListQuotaDeviasiBranch struct {
 	BranchID   string `json:"branch_id" example:"400"`
 	BranchName string `json:"customer_status" example:"BEKASI"`
@@ -1029,6 +1239,41 @@ type ReqUploadSettingQuotaDeviasi struct {
 	UpdatedByName string `form:"updated_by_name" validate:"required,max=200"`
 }
 
+type ReqConfirmUploadQuotaDeviasi struct {
+	PreviewID     string `json:"preview_id" validate:"required,max=50"`
+	UpdatedByName string `json:"updated_by_name" validate:"required,max=200"`
+}
+
+type ReqListLockSystem struct {
+	IDNumber      string `json:"id_number" validate:"omitempty,max=40" example:"3275066006789999"`
+	ChassisNumber string `json:"chassis_number" validate:"omitempty,max=50" example:"MH1JBK115FK123456"`
+	EngineNumber  string `json:"engine_number" validate:"omitempty,max=50" example:"JBK1E1234567"`
+	IsActive      string `json:"is_active" validate:"omitempty,oneof=0 1" example:"1 / 0"`
+}
+
+type ReqReapplicationDate struct {
+	IDNumber      string `json:"id_number" validate:"omitempty,max=40" example:"3275066006789999"`
+	ChassisNumber string `json:"chassis_number" validate:"omitempty,max=50" example:"MH1JBK115FK123456"`
+	EngineNumber  string `json:"engine_number" validate:"omitempty,max=50" example:"JBK1E1234567"`
+}
+
+type ReqUpdateLockSystem struct {
+	LockType      string `json:"lock_type" validate:"required,oneof=LOCK_SYSTEM BANNED_PMK_DSR BANNED_CHASSIS" example:"LOCK_SYSTEM"`
+	ProspectID    string `json:"prospect_id" validate:"required,max=20" example:"SAL-1140024080800004"`
+	Action        string `json:"action" validate:"required,oneof=LIFT EXTEND SHORTEN" example:"EXTEND"`
+	UnbanDate     string `json:"unban_date" validate:"required_unless=Action LIFT,omitempty,dateformat" example:"2024-12-31"`
+	Reason        string `json:"reason" validate:"required,max=250" example:"Konsumen mengajukan keberatan"`
+	UpdatedBy     string `json:"updated_by" validate:"required,max=20" example:"USR001"`
+	UpdatedByName string `json:"updated_by_name" validate:"required,max=200" example:"MUHAMMAD RONALD"`
+}
+
+type ReqCreateExportJob struct {
+	InquiryType string                 `json:"inquiry_type" validate:"required,oneof=PRESCREENING CA APPROVAL LIST_ORDER SEARCH" example:"CA"`
+	FileFormat  string                 `json:"file_format" validate:"required,oneof=xlsx csv" example:"xlsx"`
+	Filter      map[string]interface{} `json:"filter" validate:"required"`
+	CreatedBy   string                 `json:"created_by" validate:"required,max=20" example:"USR001"`
+}
+
 type ReqResetQuotaDeviasiBranch struct {
 	BranchID      string `json:"branch_id" validate:"required" example:"400"`
 	UpdatedByName string `json:"updated_by_name" validate:"required,max=200" example:"MUHAMMAD RONALD"`
@@ -1061,6 +1306,21 @@ type ReqUploadMappingCluster struct {
 	UserID string `form:"user_id" validate:"required,max=20"`
 }
 
+type ReqConfirmUploadMappingCluster struct {
+	PreviewID string `json:"preview_id" validate:"required,max=50"`
+	UserID    string `json:"user_id" validate:"required,max=20"`
+}
+
+type ReqDiffMappingCluster struct {
+	FromVersion int `query:"from" validate:"required,min=1"`
+	ToVersion   int `query:"to" validate:"required,min=1"`
+}
+
+type ReqRollbackMappingCluster struct {
+	Version int    `json:"version" validate:"required,min=1"`
+	UserID  string `json:"user_id" validate:"required,max=20"`
+}
+
 type ReqListMappingClusterBranch struct {
 	BranchID   string `json:"branch_id" example:"400"`
 	BranchName string `json:"customer_status" example:"BEKASI"`
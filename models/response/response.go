@@ -186,6 +186,28 @@ type ElaborateLTV struct {
 	Reason      string `json:"reason"`
 }
 
+type ElaborateLTVQuote struct {
+	ProspectID string                 `json:"prospect_id"`
+	Tenors     []int                  `json:"tenors"`
+	MaxTenor   int                    `json:"max_tenor"`
+	Grid       []ElaborateLTVQuoteRow `json:"grid"`
+}
+
+type ElaborateLTVQuoteRow struct {
+	ManufacturingYear string                  `json:"manufacturing_year"`
+	VehicleAge        int                     `json:"vehicle_age"`
+	MaxTenor          int                     `json:"max_tenor"`
+	LTV               []ElaborateLTVQuoteCell `json:"ltv"`
+}
+
+type ElaborateLTVQuoteCell struct {
+	Tenor       int    `json:"tenor"`
+	AgeVehicle  string `json:"age_vehicle"`
+	LTV         int    `json:"ltv"`
+	AdjustTenor bool   `json:"adjust_tenor"`
+	MaxTenor    int    `json:"max_tenor"`
+}
+
 type Recalculate struct {
 	ProspectID string `json:"prospect_id"`
 }
@@ -439,6 +461,23 @@ type Dsr struct {
 	Reason  string      `json:"reason"`
 }
 
+type DsrCalculate struct {
+	ProspectID          string     `json:"prospect_id"`
+	TotalIncome         float64    `json:"total_income"`
+	TotalInstallmentFMF float64    `json:"total_installment_fmf"`
+	InstallmentPBK      float64    `json:"installment_pbk"`
+	DSRFMF              float64    `json:"dsr_fmf"`
+	DSRPBK              float64    `json:"dsr_pbk"`
+	TotalDSR            float64    `json:"total_dsr"`
+	MaxDSRFMF           float64    `json:"max_dsr_fmf"`
+	MaxTotalDSR         float64    `json:"max_total_dsr"`
+	MaxTotalDSRSource   string     `json:"max_total_dsr_source"`
+	DeviasiDSR          float64    `json:"deviasi_dsr"`
+	IsDeviasi           bool       `json:"is_deviasi"`
+	InternalDSR         UsecaseApi `json:"internal_dsr"`
+	Result              UsecaseApi `json:"result"`
+}
+
 type DsrDetails struct {
 	Customer    interface{} `json:"customer"`
 	Spouse      interface{} `json:"spouse"`
@@ -512,6 +551,98 @@ type LockSystemConfig struct {
 	Data DataLockSystemConfig `json:"data"`
 }
 
+type SlaConfig struct {
+	Data DataSlaConfig `json:"data"`
+}
+
+// DataSlaConfig holds the business hours and the sla in minutes of every stage, a stage without sla is not tracked
+type DataSlaConfig struct {
+	BusinessHourStart string         `json:"business_hour_start"`
+	BusinessHourEnd   string         `json:"business_hour_end"`
+	WorkDays          []int          `json:"work_days"`
+	Holidays          []string       `json:"holidays"`
+	Stages            map[string]int `json:"stages"`
+}
+
+type StagingJob struct {
+	JobID      string     `json:"job_id"`
+	Status     string     `json:"status"`
+	TotalItem  int        `json:"total_item"`
+	Pending    int        `json:"pending"`
+	Success    int        `json:"success"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+type StagingJobItemRow struct {
+	Items          interface{} `json:"items"`
+	RecordFiltered int         `json:"recordsFiltered"`
+	RecordTotal    int         `json:"recordsTotal"`
+}
+
+// StagingReconciliation compares the orders of a staging job in new_kmb against the staging db
+type StagingReconciliation struct {
+	JobID          string                      `json:"job_id"`
+	Total          int                         `json:"total"`
+	Match          int                         `json:"match"`
+	MissingStaging int                         `json:"missing_staging"`
+	MissingNewKmb  int                         `json:"missing_new_kmb"`
+	Partial        int                         `json:"partial"`
+	Mismatch       int                         `json:"mismatch"`
+	Items          []StagingReconciliationItem `json:"items"`
+}
+
+type StagingReconciliationItem struct {
+	ProspectID      string   `json:"prospect_id"`
+	Result          string   `json:"result"`
+	BranchID        string   `json:"branch_id"`
+	StagingBranchID string   `json:"staging_branch_id"`
+	MissingTables   []string `json:"missing_tables"`
+}
+
+type PrescreeningRuleConfig struct {
+	Data DataPrescreeningRuleConfig `json:"data"`
+}
+
+// DataPrescreeningRuleConfig holds the auto decision rules, they are evaluated in order and the first matching rule decides
+type DataPrescreeningRuleConfig struct {
+	Enabled bool               `json:"enabled"`
+	Rules   []PrescreeningRule `json:"rules"`
+}
+
+// PrescreeningRule passes or flags the order when all of its conditions hold
+type PrescreeningRule struct {
+	Code        string                      `json:"code"`
+	Action      string                      `json:"action"`
+	Description string                      `json:"description"`
+	Conditions  []PrescreeningRuleCondition `json:"conditions"`
+}
+
+// PrescreeningRuleCondition is a single check of a rule, negate turns it around e.g. to flag incomplete documents
+type PrescreeningRuleCondition struct {
+	Type            string   `json:"type"`
+	Negate          bool     `json:"negate"`
+	Photos          []string `json:"photos"`
+	CustomerStatus  []string `json:"customer_status"`
+	CustomerSegment []string `json:"customer_segment"`
+	Field           string   `json:"field"`
+	Min             *float64 `json:"min"`
+	Max             *float64 `json:"max"`
+}
+
+type QuotaDeviasiResetConfig struct {
+	Data DataQuotaDeviasiResetConfig `json:"data"`
+}
+
+// DataQuotaDeviasiResetConfig sets how often the booking of every active branch goes back to zero
+type DataQuotaDeviasiResetConfig struct {
+	Period   string `json:"period"`
+	IsActive bool   `json:"is_active"`
+}
+
 type DataLockSystemConfig struct {
 	LockRejectAttempt int    `json:"lock_reject_attempt"`
 	LockRejectBan     int    `json:"lock_reject_ban"`
@@ -981,6 +1112,56 @@ type RecalculateResponse struct {
 	Status     string  `json:"status"`
 }
 
+type RecalculateHistory struct {
+	ProspectID string                   `json:"prospect_id"`
+	Original   RecalculateFigures       `json:"original"`
+	Current    RecalculateFigures       `json:"current"`
+	Histories  []RecalculateHistoryItem `json:"histories"`
+}
+
+type RecalculateFigures struct {
+	Tenor             int     `json:"tenor"`
+	DPAmount          float64 `json:"dp_amount"`
+	PercentDP         float64 `json:"percent_dp"`
+	LoanAmount        float64 `json:"loan_amount"`
+	NTF               float64 `json:"ntf"`
+	NTFAkumulasi      float64 `json:"ntf_akumulasi"`
+	AF                float64 `json:"af"`
+	InstallmentAmount float64 `json:"installment_amount"`
+	DSRFMF            float64 `json:"dsr_fmf"`
+	TotalDSR          float64 `json:"total_dsr"`
+}
+
+type RecalculateHistoryItem struct {
+	Type      string                 `json:"type"`
+	Actor     string                 `json:"actor"`
+	Note      string                 `json:"note"`
+	CreatedAt string                 `json:"created_at"`
+	Before    *RecalculateFigures    `json:"before,omitempty"`
+	After     *RecalculateFigures    `json:"after,omitempty"`
+	Diff      []RecalculateFieldDiff `json:"diff,omitempty"`
+	Limit     *RecalculateLimit      `json:"limit,omitempty"`
+}
+
+type RecalculateFieldDiff struct {
+	Field    string  `json:"field"`
+	Original float64 `json:"original"`
+	Value    float64 `json:"value"`
+	Delta    float64 `json:"delta"`
+}
+
+type RecalculateLimit struct {
+	LTV         float64 `json:"ltv"`
+	MaxLTV      float64 `json:"max_ltv"`
+	PassLTV     bool    `json:"pass_ltv"`
+	DSRFMF      float64 `json:"dsr_fmf"`
+	MaxDSRFMF   float64 `json:"max_dsr_fmf"`
+	TotalDSR    float64 `json:"total_dsr"`
+	MaxTotalDSR float64 `json:"max_total_dsr"`
+	PassDSR     bool    `json:"pass_dsr"`
+	Reason      string  `json:"reason"`
+}
+
 type ApprovalResponse struct {
 	ProspectID     string `json:"prospect_id"`
 	Decision       string `json:"decision"`
@@ -991,6 +1172,20 @@ type ApprovalResponse struct {
 	NeedEscalation bool   `json:"need_escalation"`
 }
 
+type BulkSubmitResponse struct {
+	Total   int              `json:"total"`
+	Success int              `json:"success"`
+	Failed  int              `json:"failed"`
+	Items   []BulkSubmitItem `json:"items"`
+}
+
+type BulkSubmitItem struct {
+	ProspectID string      `json:"prospect_id"`
+	Status     string      `json:"status"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+}
+
 type SubmitRecalculateResponse struct {
 	Code       int         `json:"code"`
 	Message    string      `json:"message"`
@@ -1008,6 +1203,48 @@ type UpdateQuotaDeviasiBranchResponse struct {
 	DataAfterUpdate  entity.DataQuotaDeviasiBranch `json:"data_after_update,omitempty"`
 }
 
+type UpdateLockSystemResponse struct {
+	Status          string `json:"status"`
+	Message         string `json:"message"`
+	LockType        string `json:"lock_type"`
+	ProspectID      string `json:"prospect_id"`
+	Action          string `json:"action"`
+	UnbanDateBefore string `json:"unban_date_before"`
+	UnbanDateAfter  string `json:"unban_date_after"`
+}
+
+type ReapplicationDate struct {
+	IsLocked                  bool                `json:"is_locked"`
+	EarliestReapplicationDate string              `json:"earliest_reapplication_date"`
+	Locks                     []ReapplicationLock `json:"locks"`
+}
+
+type ReapplicationLock struct {
+	LockType   string `json:"lock_type"`
+	BannedType string `json:"banned_type"`
+	ProspectID string `json:"prospect_id"`
+	Reason     string `json:"reason"`
+	UnbanDate  string `json:"unban_date"`
+}
+
+type ExportJob struct {
+	ID          string `json:"id"`
+	InquiryType string `json:"inquiry_type"`
+	FileFormat  string `json:"file_format"`
+	Status      string `json:"status"`
+	TotalRows   int    `json:"total_rows"`
+	DownloadURL string `json:"download_url"`
+}
+
+type OrderClaim struct {
+	ProspectID    string `json:"prospect_id"`
+	IsClaimed     bool   `json:"is_claimed"`
+	ClaimedBy     string `json:"claimed_by"`
+	ClaimedByName string `json:"claimed_by_name"`
+	ClaimedAt     string `json:"claimed_at"`
+	ExpiredAt     string `json:"expired_at"`
+}
+
 type UploadQuotaDeviasiBranchResponse struct {
 	Status           string                        `json:"status"`
 	Message          string                        `json:"message"`
@@ -1015,6 +1252,100 @@ type UploadQuotaDeviasiBranchResponse struct {
 	DataAfterUpdate  []entity.MappingBranchDeviasi `json:"data_after_update,omitempty"`
 }
 
+// UploadPreview is the dry run of an uploaded file, the changes can only be applied when it has no errors
+type UploadPreview struct {
+	PreviewID  string           `json:"preview_id"`
+	UploadType string           `json:"upload_type"`
+	IsValid    bool             `json:"is_valid"`
+	TotalRows  int              `json:"total_rows"`
+	ErrorCount int              `json:"error_count"`
+	Errors     []UploadRowError `json:"errors"`
+	Changes    interface{}      `json:"changes"`
+}
+
+type UploadRowError struct {
+	Row     int    `json:"row"`
+	Column  int    `json:"column"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type QuotaDeviasiPreviewChange struct {
+	BranchID string                        `json:"branch_id"`
+	Before   entity.DataQuotaDeviasiBranch `json:"before"`
+	After    entity.DataQuotaDeviasiBranch `json:"after"`
+	Conflict string                        `json:"conflict,omitempty"`
+}
+
+type MappingClusterPreviewChange struct {
+	BranchID       string `json:"branch_id"`
+	CustomerStatus string `json:"customer_status"`
+	BpkbNameType   int    `json:"bpkb_name_type"`
+	ClusterBefore  string `json:"cluster_before"`
+	ClusterAfter   string `json:"cluster_after"`
+	Action         string `json:"action"`
+}
+
+type MappingClusterVersionDiff struct {
+	FromVersion int                           `json:"from_version"`
+	ToVersion   int                           `json:"to_version"`
+	Changes     []MappingClusterPreviewChange `json:"changes"`
+}
+
+type NEDraft struct {
+	ID        string      `json:"id"`
+	Status    string      `json:"status"`
+	Data      interface{} `json:"data"`
+	UpdatedAt string      `json:"updated_at"`
+}
+
+type NEPrecheck struct {
+	Passed bool              `json:"passed"`
+	Issues []NEPrecheckIssue `json:"issues"`
+}
+
+type NEPrecheckIssue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type DashboardStats struct {
+	BranchIDs      []string                     `json:"branch_ids"`
+	StartDate      string                       `json:"start_date"`
+	EndDate        string                       `json:"end_date"`
+	Orders         []entity.DashboardOrderStage `json:"orders"`
+	ApprovalRates  []DashboardApprovalRate      `json:"approval_rates"`
+	TopRejections  []entity.DashboardRejection  `json:"top_rejections"`
+	QuotaDeviasi   DashboardQuotaDeviasi        `json:"quota_deviasi"`
+	TimeToDecision DashboardTimeToDecision      `json:"time_to_decision"`
+	GeneratedAt    string                       `json:"generated_at"`
+}
+
+type DashboardApprovalRate struct {
+	Alias         string  `json:"alias"`
+	Total         int     `json:"total"`
+	Approved      int     `json:"approved"`
+	Rejected      int     `json:"rejected"`
+	Returned      int     `json:"returned"`
+	ApprovalRate  float64 `json:"approval_rate"`
+	RejectionRate float64 `json:"rejection_rate"`
+}
+
+type DashboardQuotaDeviasi struct {
+	QuotaAmount        float64 `json:"quota_amount"`
+	QuotaAccount       int     `json:"quota_account"`
+	BookingAmount      float64 `json:"booking_amount"`
+	BookingAccount     int     `json:"booking_account"`
+	AmountUtilisation  float64 `json:"amount_utilisation"`
+	AccountUtilisation float64 `json:"account_utilisation"`
+}
+
+type DashboardTimeToDecision struct {
+	TotalDecided   int     `json:"total_decided"`
+	AverageMinutes float64 `json:"average_minutes"`
+}
+
 type EmployeeCMOResponse struct {
 	EmployeeID         string      `json:"employee_id"`
 	EmployeeName       string      `json:"employee_name"`
@@ -1618,3 +1949,23 @@ type (
 		EmergencyContactProvince        string `json:"emergency_contact_province"`
 	}
 )
+
+type MappingCheckReport struct {
+	CheckedAt  string              `json:"checked_at"`
+	TotalIssue int                 `json:"total_issue"`
+	Tables     []MappingCheckTable `json:"tables"`
+}
+
+type MappingCheckTable struct {
+	Table    string              `json:"table"`
+	TotalRow int                 `json:"total_row"`
+	Issues   []MappingCheckIssue `json:"issues"`
+}
+
+type MappingCheckIssue struct {
+	Type        string   `json:"type"`
+	Dimension   string   `json:"dimension,omitempty"`
+	Group       string   `json:"group"`
+	Rows        []string `json:"rows,omitempty"`
+	Description string   `json:"description"`
+}
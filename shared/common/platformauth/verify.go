@@ -18,6 +18,38 @@ func PlatformVerify(token string) error {
 }
 
 func defaultPlatformVerify(token string) (err error) {
+	_, err = validate(token)
+	return
+}
+
+// Session is the cms user behind a token, the role decides what the user may see and do
+type Session struct {
+	UserID string
+	Role   string
+}
+
+// This allows for mocking in tests
+var PlatformSessionFunc = defaultPlatformSession
+
+// PlatformSession validates the token like PlatformVerify and returns the user it was issued to
+func PlatformSession(token string) (Session, error) {
+	return PlatformSessionFunc(token)
+}
+
+func defaultPlatformSession(token string) (session Session, err error) {
+
+	data, err := validate(token)
+	if err != nil {
+		return
+	}
+
+	session.UserID, _ = data[constant.PLATFORM_SESSION_USER_ID].(string)
+	session.Role, _ = data[constant.PLATFORM_SESSION_ROLE].(string)
+
+	return
+}
+
+func validate(token string) (data map[string]interface{}, err error) {
 	env := os.Getenv("APP_ENV")
 
 	if strings.Contains(strings.ToLower(env), "production") {
@@ -33,12 +65,12 @@ func defaultPlatformVerify(token string) (err error) {
 	resp, authErr := auth.Validation(token, "los-kmb-api")
 
 	if authErr != nil {
-		return errors.New(constant.ERROR_UNAUTHORIZED + " - " + "invalid")
+		return nil, errors.New(constant.ERROR_UNAUTHORIZED + " - " + "invalid")
 	}
 
 	if resp.Data["status"] != "active" {
-		return errors.New(constant.ERROR_UNAUTHORIZED + " - " + "expired")
+		return nil, errors.New(constant.ERROR_UNAUTHORIZED + " - " + "expired")
 	}
 
-	return
+	return resp.Data, nil
 }
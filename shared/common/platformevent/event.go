@@ -20,6 +20,9 @@ type platformEvent struct {
 	producerSubmissionLOS    *event.Client
 	producerInsertCustomer   *event.Client
 	producerSubmission2Wilen *event.Client
+	producerUnlock           *event.Client
+	producerSlaEscalation    *event.Client
+	producerMasterData       *event.Client
 }
 
 //counterfeiter:generate . PlatformEventInterface
@@ -27,8 +30,8 @@ type PlatformEventInterface interface {
 	PublishEvent(ctx context.Context, accessToken, topicName, key, id string, value map[string]interface{}, countRetry int) error
 }
 
-func NewPlatformEvent(producerSubmission, producerSubmissionLOS, producerInsertCustomer, producerSubmission2Wilen *event.Client) PlatformEventInterface {
-	return &platformEvent{producerSubmission, producerSubmissionLOS, producerInsertCustomer, producerSubmission2Wilen}
+func NewPlatformEvent(producerSubmission, producerSubmissionLOS, producerInsertCustomer, producerSubmission2Wilen, producerUnlock, producerSlaEscalation, producerMasterData *event.Client) PlatformEventInterface {
+	return &platformEvent{producerSubmission, producerSubmissionLOS, producerInsertCustomer, producerSubmission2Wilen, producerUnlock, producerSlaEscalation, producerMasterData}
 }
 
 func (pe platformEvent) PublishEvent(ctx context.Context, accessToken, topicName, key, id string, value map[string]interface{}, countRetry int) error {
@@ -51,6 +54,12 @@ func (pe platformEvent) PublishEvent(ctx context.Context, accessToken, topicName
 		producer = pe.producerInsertCustomer
 	case constant.TOPIC_SUBMISSION_2WILEN:
 		producer = pe.producerSubmission2Wilen
+	case constant.TOPIC_UNLOCK:
+		producer = pe.producerUnlock
+	case constant.TOPIC_SLA_ESCALATION:
+		producer = pe.producerSlaEscalation
+	case constant.TOPIC_MASTER_DATA:
+		producer = pe.producerMasterData
 	default:
 		err = fmt.Errorf("producer for topic %s was not created", topicName)
 
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"context"
+	"los-kmb-api/shared/constant"
+	"os"
+	"time"
+)
+
+// SchedulerJob is one run of a scheduler, the counts it returns are the response of the run log
+type SchedulerJob func(ctx context.Context, accessToken string) (map[string]interface{}, error)
+
+type SchedulerParameter struct {
+	Action     string
+	MsgLogFile string
+	Interval   time.Duration
+	// Auth refreshes the platform token before every run and returns it, a scheduler has no request token
+	Auth func() string
+	// SkipIdle leaves out the log of a run without error whose counts are all zero
+	SkipIdle bool
+	// Rerun starts the next run right away instead of waiting for the next tick
+	Rerun func(result map[string]interface{}, err error) bool
+}
+
+// RunScheduler runs the job every interval until ctx is done and logs every run under the scheduler action
+func RunScheduler(ctx context.Context, param SchedulerParameter, job SchedulerJob) {
+
+	ticker := time.NewTicker(param.Interval)
+	defer ticker.Stop()
+
+	for {
+		accessToken := param.Auth()
+
+		result, err := job(ctx, accessToken)
+
+		if !param.SkipIdle || err != nil || !schedulerIdle(result) {
+			logParam := CentralizeLogParameter{
+				Link:       os.Getenv("DUMMY_URL_LOGS"),
+				Action:     param.Action,
+				Type:       "SCHEDULER",
+				LogFile:    constant.NEW_KMB_LOG,
+				MsgLogFile: param.MsgLogFile,
+				LevelLog:   constant.PLATFORM_LOG_LEVEL_INFO,
+				Response:   result,
+			}
+
+			if err != nil {
+				response := map[string]interface{}{"errors": err.Error()}
+				for key, value := range result {
+					response[key] = value
+				}
+
+				logParam.LevelLog = constant.PLATFORM_LOG_LEVEL_ERROR
+				logParam.Response = response
+			}
+
+			CentralizeLog(ctx, accessToken, logParam)
+		}
+
+		if param.Rerun != nil && param.Rerun(result, err) {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// schedulerIdle tells whether every count of the run is zero
+func schedulerIdle(result map[string]interface{}) bool {
+
+	for _, value := range result {
+		if count, ok := value.(int); ok && count != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -8,17 +8,25 @@ var TOPIC_SUBMISSION_LOS string
 var TOPIC_INSERT_CUSTOMER string
 var TOPIC_SUBMISSION_PRINCIPLE string
 var TOPIC_SUBMISSION_2WILEN string
+var TOPIC_UNLOCK string
+var TOPIC_SLA_ESCALATION string
+var TOPIC_MASTER_DATA string
 
 // Event Driven Key
 var KEY_PREFIX_FILTERING string
 var KEY_PREFIX_UPDATE_STATUS_FILTERING string
 var KEY_PREFIX_SUBMIT_TO_LOS string
 var KEY_PREFIX_AFTER_PRESCREENING string
+var KEY_PREFIX_RESUME_JOURNEY string
 var KEY_PREFIX_CALLBACK string
 var KEY_PREFIX_CALLBACK_GOLIVE string
 var KEY_PREFIX_UPDATE_CUSTOMER string
 var KEY_PREFIX_UPDATE_TRANSACTION_PRINCIPLE string
 var KEY_PREFIX_CANCEL_ORDER_2WILEN string
+var KEY_PREFIX_UNLOCK string
+var KEY_PREFIX_SLA_ESCALATION string
+var KEY_PREFIX_RELEASE_QUOTA_DEVIASI string
+var KEY_PREFIX_MASTER_DATA string
 
 const (
 	FLAG_LOS                         = "LOS"
@@ -316,6 +324,7 @@ const (
 	CTX_KEY_INCOMING_REQUEST_URL    = "IncomingRequestURL"
 	CTX_KEY_INCOMING_REQUEST_METHOD = "IncomingRequestMethod"
 	CTX_KEY_IS_CONSUMER             = "IsKafkaConsumer"
+	CTX_KEY_JOURNEY_RESUME          = "JourneyResume"
 	MSG_INCOMING_REQUEST            = "INCOMING_REQUEST"
 
 	//Platform Log
@@ -393,6 +402,230 @@ const (
 	BANNED_TYPE_NIK   = "NIK"
 	BANNED_TYPE_ASSET = "ASSET"
 
+	//LOCK SYSTEM - CMS OVERRIDE
+	LOCK_TYPE_SYSTEM         = "LOCK_SYSTEM"
+	LOCK_TYPE_BANNED_PMK_DSR = "BANNED_PMK_DSR"
+	LOCK_TYPE_BANNED_CHASSIS = "BANNED_CHASSIS"
+	LOCK_ACTION_LIFT         = "LIFT"
+	LOCK_ACTION_EXTEND       = "EXTEND"
+	LOCK_ACTION_SHORTEN      = "SHORTEN"
+	LOCK_BANNED_DAYS         = 30
+	LOCK_OVERRIDE_SUCCESS    = "UPDATE LOCK SYSTEM BERHASIL"
+
+	//LOCK SYSTEM - UNLOCK NOTIFICATION
+	EVENT_UNLOCK              = "unlock"
+	UNLOCK_STATUS_PENDING     = "PENDING"
+	UNLOCK_STATUS_PUBLISHED   = "PUBLISHED"
+	UNLOCK_STATUS_SKIPPED     = "SKIPPED"
+	UNLOCK_LOOKBACK_DAYS      = 7
+	UNLOCK_SCHEDULER_INTERVAL = 60
+	UNLOCK_PENDING_TIMEOUT    = 30
+
+	//CMS EXPORT
+	EXPORT_TYPE_PRESCREENING = "PRESCREENING"
+	EXPORT_TYPE_CA           = "CA"
+	EXPORT_TYPE_APPROVAL     = "APPROVAL"
+	EXPORT_TYPE_LIST_ORDER   = "LIST_ORDER"
+	EXPORT_TYPE_SEARCH       = "SEARCH"
+	EXPORT_FORMAT_XLSX       = "xlsx"
+	EXPORT_FORMAT_CSV        = "csv"
+	EXPORT_STATUS_PENDING    = "PENDING"
+	EXPORT_STATUS_PROCESSING = "PROCESSING"
+	EXPORT_STATUS_DONE       = "DONE"
+	EXPORT_STATUS_FAILED     = "FAILED"
+	EXPORT_PAGE_LIMIT        = 500
+
+	//CMS BULK SUBMIT
+	BULK_ITEM_SUCCESS = "SUCCESS"
+	BULK_ITEM_FAILED  = "FAILED"
+
+	//SLA AGING
+	EVENT_SLA_ESCALATION   = "sla_escalation"
+	SLA_FILTER_BREACHED    = "BREACHED"
+	SLA_FILTER_ON_TRACK    = "ON_TRACK"
+	SLA_SCHEDULER_INTERVAL = 15
+	SLA_CONFIG_GROUP       = "sla_aging"
+	SLA_CONFIG_KEY         = "sla_aging_kmb"
+
+	//CA ORDER CLAIM
+	ORDER_CLAIM_TIMEOUT       = 30
+	CLAIM_ACTION_CLAIM        = "CLAIM"
+	CLAIM_ACTION_RELEASE      = "RELEASE"
+	CLAIM_ACTION_FORCE        = "FORCE_RELEASE"
+	CLAIM_REASON_DECIDED      = "Order sudah diputus"
+	CLAIM_REASON_CANCELED     = "Order sudah dicancel"
+	ERROR_ORDER_CLAIMED       = "Order sedang di-claim oleh "
+	ERROR_ORDER_NOT_CLAIMED   = "Order harus di-claim terlebih dahulu"
+	ERROR_ORDER_NOT_CLAIMABLE = "Order tidak sedang dalam antrian CA"
+	ORDER_CLAIM_FORCE_ROLES   = "CBM,DRM,GMO,COM,GMC,UCC"
+	ERROR_ORDER_FORCE_ROLE    = "Role user tidak diizinkan melakukan force release"
+
+	//QUOTA DEVIASI LEDGER & PERIOD RESET
+	QUOTA_LEDGER_BOOKING           = "BOOKING"
+	QUOTA_LEDGER_RELEASE           = "RELEASE"
+	QUOTA_RESET_SCHEDULER          = "SCHEDULER"
+	QUOTA_RESET_MANUAL             = "MANUAL"
+	QUOTA_PERIOD_DAILY             = "DAILY"
+	QUOTA_PERIOD_WEEKLY            = "WEEKLY"
+	QUOTA_PERIOD_MONTHLY           = "MONTHLY"
+	QUOTA_PERIOD_QUARTERLY         = "QUARTERLY"
+	QUOTA_RESET_CONFIG_GROUP       = "quota_deviasi"
+	QUOTA_RESET_CONFIG_KEY         = "quota_deviasi_reset_kmb"
+	QUOTA_RESET_SCHEDULER_INTERVAL = 60
+	QUOTA_REASON_APPROVE           = "APPROVE"
+	QUOTA_REASON_CANCEL            = "CANCEL"
+	QUOTA_REASON_REJECT            = "REJECT"
+	QUOTA_REASON_RETURN            = "RETURN"
+
+	//UPLOAD PREVIEW
+	UPLOAD_TYPE_QUOTA_DEVIASI     = "QUOTA_DEVIASI"
+	UPLOAD_TYPE_MAPPING_CLUSTER   = "MAPPING_CLUSTER"
+	UPLOAD_PREVIEW_STATUS_PREVIEW = "PREVIEW"
+	UPLOAD_PREVIEW_STATUS_APPLIED = "APPLIED"
+	UPLOAD_PREVIEW_EXPIRY         = 60
+	UPLOAD_CHANGE_ADD             = "ADD"
+	UPLOAD_CHANGE_UPDATE          = "UPDATE"
+	UPLOAD_CHANGE_DELETE          = "DELETE"
+	MAPPING_CLUSTER_CONFIG_ID     = "kmb_mapping_cluster_branch"
+	MAPPING_CLUSTER_INITIAL       = "INITIAL"
+	MAPPING_CLUSTER_UPLOAD        = "UPLOAD"
+	MAPPING_CLUSTER_ROLLBACK      = "ROLLBACK"
+
+	//WORKER EXECUTOR
+	WORKER_SCHEDULER_INTERVAL = 30
+	WORKER_BATCH_SIZE         = 10
+	WORKER_LOCK_TIMEOUT       = 10
+	WORKER_BACKOFF_BASE       = 30
+	WORKER_BACKOFF_MAX        = 3600
+	WORKER_ACTIVITY_RUNNING   = "RUNN"
+	WORKER_ACTIVITY_DEAD      = "DEAD"
+
+	//FORM AKKK
+	FORM_AKKK_TEMPLATE_VERSION = "v1"
+	FORM_AKKK_RENDERER_REMOTE  = "REMOTE"
+
+	//NOTIFICATION
+	NOTIFICATION_APPROVAL_ROUTED = "APPROVAL_ROUTED"
+	NOTIFICATION_ORDER_RETURNED  = "ORDER_RETURNED"
+	NOTIFICATION_SLA_BREACHED    = "SLA_BREACHED"
+	NOTIFICATION_FINAL_DECISION  = "FINAL_DECISION"
+	NOTIFICATION_CHANNEL_ALL     = "ALL"
+	NOTIFICATION_CHANNEL_WEBHOOK = "WEBHOOK"
+	NOTIFICATION_CHANNEL_SMTP    = "SMTP"
+	NOTIFICATION_CHANNEL_INBOX   = "INBOX"
+	NOTIFICATION_CHANNEL_LOCAL   = "LOCAL"
+	NOTIFICATION_STATUS_SENT     = "SENT"
+	NOTIFICATION_STATUS_FAILED   = "FAILED"
+
+	//DASHBOARD
+	DASHBOARD_CACHE_TTL       = 5
+	DASHBOARD_MAX_RANGE_DAYS  = 92
+	DASHBOARD_TOP_REJECTION   = 10
+	DASHBOARD_CACHE_KEY_STATS = "dashboard_stats"
+
+	//BLIND INDEX
+	BLIND_INDEX_LENGTH              = 32
+	BLIND_INDEX_MIN_PREFIX          = 3
+	BLIND_INDEX_MIN_DIGITS          = 6
+	BLIND_INDEX_VERSION             = 2
+	BLIND_INDEX_FIELD_ID_NUMBER     = "id_number"
+	BLIND_INDEX_FIELD_LEGAL_NAME    = "legal_name"
+	BLIND_INDEX_FIELD_MOBILE_PHONE  = "mobile_phone"
+	BLIND_INDEX_FIELD_NAME_TOKEN    = "name_token"
+	BLIND_INDEX_FIELD_ID_TOKEN      = "id_number_token"
+	BLIND_INDEX_FIELD_PHONE_TOKEN   = "mobile_phone_token"
+	BLIND_INDEX_BACKFILL_BATCH_SIZE = 500
+	BLIND_INDEX_SCHEDULER_INTERVAL  = 1
+
+	//NE DRAFT
+	NE_DRAFT_STATUS_DRAFT         = "DRAFT"
+	NE_DRAFT_STATUS_SUBMITTED     = "SUBMITTED"
+	NE_DRAFT_STATUS_DISCARDED     = "DISCARDED"
+	NE_PRECHECK_SEVERITY_ERROR    = "ERROR"
+	NE_PRECHECK_SEVERITY_WARNING  = "WARNING"
+	NE_PRECHECK_RULE_MOBILE_PHONE = "MOBILE_PHONE"
+	NE_PRECHECK_RULE_INCOME_PMK   = "INCOME_PMK"
+	NE_PRECHECK_RULE_TENOR        = "TENOR"
+	NE_PRECHECK_TENOR_THRESHOLD   = 36
+
+	//RECALCULATE HISTORY
+	RECALCULATE_HISTORY_RETURN      = "RETURN"
+	RECALCULATE_HISTORY_REQUEST     = "RECALCULATE_REQUEST"
+	RECALCULATE_HISTORY_RECALCULATE = "RECALCULATE"
+
+	//MASTER DATA
+	MASTER_DATA_CANCEL_REASON              = "cancel_reason"
+	MASTER_DATA_APPROVAL_REASON            = "approval_reason"
+	MASTER_DATA_PRESCREENING_REASON        = "prescreening_reason"
+	MASTER_DATA_INDUSTRY_TYPE              = "industry_type"
+	MASTER_DATA_ACTION_CREATE              = "CREATE"
+	MASTER_DATA_ACTION_UPDATE              = "UPDATE"
+	MASTER_DATA_ACTION_DELETE              = "DELETE"
+	MASTER_DATA_ACTION_REORDER             = "REORDER"
+	MASTER_DATA_HISTORY_SYNC_LIMIT         = 100
+	MASTER_DATA_HISTORY_SCHEDULER_INTERVAL = 1
+	MASTER_DATA_CACHE_TTL                  = 5
+
+	//JOURNEY CHECKPOINT
+	JOURNEY_STAGE_DUPCHECK      = "DUPCHECK"
+	JOURNEY_STAGE_EKYC          = "EKYC"
+	JOURNEY_STAGE_SCOREPRO      = "SCOREPRO"
+	JOURNEY_STAGE_FAILED        = "FAILED"
+	JOURNEY_CHECKPOINT_VALIDITY = 24
+	ERROR_JOURNEY_ALREADY_DONE  = "Order sudah selesai diproses"
+	ERROR_JOURNEY_NOT_RESUMABLE = "Request journey tidak ditemukan"
+	ERROR_JOURNEY_NOT_FAILED    = "Journey order tidak dalam status gagal"
+
+	//PRESCREENING AUTO DECISION
+	PRESCREENING_RULE_CONFIG_GROUP       = "prescreening_rule"
+	PRESCREENING_RULE_CONFIG_KEY         = "prescreening_rule_kmb"
+	PRESCREENING_RULE_SCHEDULER_INTERVAL = 1
+	PRESCREENING_RULE_BATCH_SIZE         = 100
+	PRESCREENING_RULE_PENDING            = "PENDING"
+	PRESCREENING_RULE_PASS               = "PASS"
+	PRESCREENING_RULE_FLAG               = "FLAG"
+	PRESCREENING_RULE_MANUAL             = "MANUAL"
+	PRESCREENING_RULE_DOCUMENT_COMPLETE  = "DOCUMENT_COMPLETE"
+	PRESCREENING_RULE_SURVEYOR_PRESENT   = "SURVEYOR_PRESENT"
+	PRESCREENING_RULE_CUSTOMER_SEGMENT   = "CUSTOMER_SEGMENT"
+	PRESCREENING_RULE_AMOUNT             = "AMOUNT"
+	PRESCREENING_RULE_AMOUNT_OTR         = "OTR"
+	PRESCREENING_RULE_AMOUNT_NTF         = "NTF"
+	PRESCREENING_RULE_DECISION_BY        = "AUTO PRESCREENING"
+	PRESCREENING_RULE_REASON_PASS        = "Lolos aturan otomatis "
+
+	//STAGING JOB
+	STAGING_JOB_STATUS_RUNNING    = "RUNNING"
+	STAGING_JOB_STATUS_DONE       = "DONE"
+	STAGING_ITEM_PENDING          = "PENDING"
+	STAGING_ITEM_SUCCESS          = "SUCCESS"
+	STAGING_ITEM_SKIPPED          = "SKIPPED"
+	STAGING_ITEM_FAILED           = "FAILED"
+	STAGING_JOB_MAX_ITEMS         = 1000
+	STAGING_JOB_CONCURRENCY       = 5
+	STAGING_JOB_STALE_MINUTES     = 30
+	STAGING_RECON_MATCH           = "MATCH"
+	STAGING_RECON_MISSING_STAGING = "MISSING_STAGING"
+	STAGING_RECON_MISSING_NEW_KMB = "MISSING_NEW_KMB"
+	STAGING_RECON_PARTIAL         = "PARTIAL"
+	STAGING_RECON_MISMATCH        = "MISMATCH"
+	ERROR_STAGING_JOB_EMPTY       = "Tidak ada order yang dapat dikirim ke staging"
+	ERROR_STAGING_JOB_RUNNING     = "Job staging sedang berjalan"
+
+	//PII MASKING
+	PII_DEFAULT_FULL_ACCESS_ROLES = "CA"
+	PLATFORM_SESSION_USER_ID      = "user_id"
+	PLATFORM_SESSION_ROLE         = "role_alias"
+	PII_PURPOSE_INQUIRY_CA        = "INQUIRY_CA"
+	PII_PURPOSE_INQUIRY_APPROVAL  = "INQUIRY_APPROVAL"
+	PII_PURPOSE_SEARCH            = "SEARCH"
+	PII_PURPOSE_AKKK              = "AKKK"
+	PII_MASK_ID_NUMBER_PREFIX     = 6
+	PII_MASK_ID_NUMBER_SUFFIX     = 4
+	PII_MASK_PHONE_PREFIX         = 4
+	PII_MASK_PHONE_SUFFIX         = 3
+	PII_MASK_VALUE                = "*****"
+
 	//LOCK SYSTEM - ASSET CHECK
 	CODE_REJECT_ASSET_CHECK                = "662"
 	REASON_REJECT_ASSET_CHECK              = "Asset pernah diajukan - Bukan a.n Konsumen & Pasangan"
@@ -499,6 +732,9 @@ const (
 	REASON_MENUNGGAK                         = "- Menunggak"
 	REASON_TOTAL_DSRLTE                      = "- DSR <="
 	REASON_TOTAL_DSRGT                       = "- DSR >"
+	DSR_THRESHOLD_SOURCE_CONFIG              = "CONFIG"
+	DSR_THRESHOLD_SOURCE_CLUSTER             = "CLUSTER"
+	DSR_THRESHOLD_SOURCE_INCOME              = "INCOME"
 
 	DEALER_PSA     = "PSA"
 	DEALER_NON_PSA = "NON PSA"
@@ -829,4 +1065,19 @@ const (
 	LOS_CANCEL_MESSAGE_2WILEN           = "Pengajuan anda telah dibatalkan sebelumnya"
 
 	MESSAGE_INTERNAL_SERVER_ERROR = "Terjadi kesalahan pada sistem, silahkan coba lagi"
+
+	// MAPPING CHECKER
+	CLI_CHECK_MAPPING                 = "check-mapping"
+	MAPPING_ISSUE_GAP                 = "GAP"
+	MAPPING_ISSUE_OVERLAP             = "OVERLAP"
+	MAPPING_ISSUE_UNREACHABLE         = "UNREACHABLE"
+	MAPPING_ISSUE_MISSING_COMBINATION = "MISSING_COMBINATION"
+	MAPPING_STATUS_KONSUMEN_ALL       = "ALL"
+	AGE_VEHICLE_LTE_12                = "<=12"
+	AGE_VEHICLE_GT_12                 = ">12"
+
+	// ELABORATE LTV QUOTE, default grid axes when the request does not set them
+	ELABORATE_QUOTE_TENOR_STEP = 6
+	ELABORATE_QUOTE_TENOR_MAX  = 60
+	ELABORATE_QUOTE_YEAR_SPAN  = 15
 )
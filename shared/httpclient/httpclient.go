@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"los-kmb-api/middlewares"
+	"los-kmb-api/models/request"
 	"los-kmb-api/models/response"
 	"los-kmb-api/shared/common"
 	"los-kmb-api/shared/constant"
@@ -245,6 +246,15 @@ func (h httpClientHandler) MediaClient(ctx context.Context, logFile, link, metho
 	switch method {
 	case constant.METHOD_GET:
 		resp, err = client.R().SetHeaders(header).Get(link)
+	case constant.METHOD_POST:
+		// upload a file as multipart, only the file name is logged
+		upload, _ := param.(request.ReqMediaUpload)
+		mapRequest["file_name"] = upload.FileName
+		mapRequest["type"] = upload.Type
+		resp, err = client.R().SetHeaders(header).
+			SetMultipartField("file", upload.FileName, upload.ContentType, upload.Content).
+			SetFormData(map[string]string{"type": upload.Type}).
+			Post(link)
 	}
 
 	if err != nil {
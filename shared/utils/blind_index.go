@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"los-kmb-api/shared/constant"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/jinzhu/gorm"
+)
+
+// CustomerBlindIndex holds the searchable hashes of the encrypted customer fields of an order
+type CustomerBlindIndex struct {
+	IDNumber    string
+	LegalName   string
+	MobilePhone string
+	NameTokens  []string
+	// NumberTokens hold the prefixes and suffixes of the id number and the mobile phone
+	NumberTokens []string
+}
+
+// BlindIndexEnabled reports whether the inquiries should search through the blind indexes instead of the encrypted values,
+// the search is switched on once the backfill has populated the existing orders
+func BlindIndexEnabled() bool {
+	return os.Getenv("BLIND_INDEX_KEY") != "" && os.Getenv("BLIND_INDEX_SEARCH_ENABLED") == "true"
+}
+
+// BlindIndex returns the keyed hash of an already normalized value, the field is part of the message so equal values
+// on different fields never share a hash
+func BlindIndex(field, value string) string {
+
+	key := os.Getenv("BLIND_INDEX_KEY")
+	if key == "" || value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(field + "|" + value))
+
+	return hex.EncodeToString(mac.Sum(nil))[:constant.BLIND_INDEX_LENGTH]
+}
+
+func NormalizeIDNumber(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// NormalizePhone keeps the digits and writes the country code 62 as a leading 0
+func NormalizePhone(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+
+	phone := builder.String()
+
+	switch {
+	case strings.HasPrefix(phone, "62"):
+		phone = "0" + phone[2:]
+	case strings.HasPrefix(phone, "8"):
+		phone = "0" + phone
+	}
+
+	return phone
+}
+
+// NormalizeName uppercases the name and keeps the letters and digits of every word
+func NormalizeName(value string) []string {
+	return strings.FieldsFunc(strings.ToUpper(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func BlindIndexIDNumber(value string) string {
+	return BlindIndex(constant.BLIND_INDEX_FIELD_ID_NUMBER, NormalizeIDNumber(value))
+}
+
+func BlindIndexMobilePhone(value string) string {
+	return BlindIndex(constant.BLIND_INDEX_FIELD_MOBILE_PHONE, NormalizePhone(value))
+}
+
+func BlindIndexLegalName(value string) string {
+	return BlindIndex(constant.BLIND_INDEX_FIELD_LEGAL_NAME, strings.Join(NormalizeName(value), " "))
+}
+
+// BlindIndexNameTokens hashes every prefix of every word of the name from BLIND_INDEX_MIN_PREFIX letters,
+// a shorter word is hashed as a whole, so a search by part of a word still matches
+func BlindIndexNameTokens(value string) (tokens []string) {
+
+	seen := map[string]bool{}
+
+	for _, word := range NormalizeName(value) {
+		letters := []rune(word)
+
+		start := constant.BLIND_INDEX_MIN_PREFIX
+		if len(letters) < start {
+			start = len(letters)
+		}
+
+		for i := start; i <= len(letters); i++ {
+			token := BlindIndex(constant.BLIND_INDEX_FIELD_NAME_TOKEN, string(letters[:i]))
+			if token != "" && !seen[token] {
+				seen[token] = true
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	return
+}
+
+// BlindIndexSearchTokens hashes every word of a name search, an order matches when it has all of them
+func BlindIndexSearchTokens(value string) (tokens []string) {
+
+	seen := map[string]bool{}
+
+	for _, word := range NormalizeName(value) {
+		token := BlindIndex(constant.BLIND_INDEX_FIELD_NAME_TOKEN, word)
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	return
+}
+
+// BlindIndexNumberTokens hashes every prefix and suffix of the value from BLIND_INDEX_MIN_DIGITS characters,
+// so a search by the start or the end of an id number or a phone still matches
+func BlindIndexNumberTokens(field, value string) (tokens []string) {
+
+	seen := map[string]bool{}
+
+	for i := constant.BLIND_INDEX_MIN_DIGITS; i <= len(value); i++ {
+		for _, part := range []string{value[:i], value[len(value)-i:]} {
+			token := BlindIndex(field, part)
+			if token != "" && !seen[token] {
+				seen[token] = true
+				tokens = append(tokens, token)
+			}
+		}
+	}
+
+	return
+}
+
+// BlindIndexIDNumberSearchTokens hashes a search by part of an id number, nothing below BLIND_INDEX_MIN_DIGITS characters
+func BlindIndexIDNumberSearchTokens(value string) (tokens []string) {
+
+	value = NormalizeIDNumber(value)
+	if len(value) < constant.BLIND_INDEX_MIN_DIGITS {
+		return
+	}
+
+	if token := BlindIndex(constant.BLIND_INDEX_FIELD_ID_TOKEN, value); token != "" {
+		tokens = append(tokens, token)
+	}
+
+	return
+}
+
+// BlindIndexPhoneSearchTokens hashes a search by part of a mobile phone, the digits are hashed as typed since they may be
+// the end of the number, and once more normalized in case they are the start of it
+func BlindIndexPhoneSearchTokens(value string) (tokens []string) {
+
+	seen := map[string]bool{}
+
+	for _, part := range []string{NormalizeIDNumber(value), NormalizePhone(value)} {
+		if len(part) < constant.BLIND_INDEX_MIN_DIGITS {
+			continue
+		}
+
+		token := BlindIndex(constant.BLIND_INDEX_FIELD_PHONE_TOKEN, part)
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+
+	return
+}
+
+func NewCustomerBlindIndex(idNumber, legalName, mobilePhone string) CustomerBlindIndex {
+	return CustomerBlindIndex{
+		IDNumber:    BlindIndexIDNumber(idNumber),
+		LegalName:   BlindIndexLegalName(legalName),
+		MobilePhone: BlindIndexMobilePhone(mobilePhone),
+		NameTokens:  BlindIndexNameTokens(legalName),
+		NumberTokens: append(BlindIndexNumberTokens(constant.BLIND_INDEX_FIELD_ID_TOKEN, NormalizeIDNumber(idNumber)),
+			BlindIndexNumberTokens(constant.BLIND_INDEX_FIELD_PHONE_TOKEN, NormalizePhone(mobilePhone))...),
+	}
+}
+
+// SaveCustomerBlindIndex writes the blind indexes of an order inside the caller transaction, the tokens are replaced as a whole
+func SaveCustomerBlindIndex(tx *gorm.DB, prospectID string, index CustomerBlindIndex) (err error) {
+
+	if err = tx.Exec(`UPDATE trx_customer_personal SET id_number_bidx = NULLIF(?, ''), legal_name_bidx = NULLIF(?, ''), mobile_phone_bidx = NULLIF(?, ''),
+		bidx_at = GETDATE(), bidx_version = ? WHERE ProspectID = ?`, index.IDNumber, index.LegalName, index.MobilePhone, constant.BLIND_INDEX_VERSION, prospectID).Error; err != nil {
+		return
+	}
+
+	if err = saveBlindIndexTokens(tx, "trx_customer_name_bidx", prospectID, index.NameTokens); err != nil {
+		return
+	}
+
+	err = saveBlindIndexTokens(tx, "trx_customer_number_bidx", prospectID, index.NumberTokens)
+
+	return
+}
+
+func saveBlindIndexTokens(tx *gorm.DB, table, prospectID string, tokens []string) (err error) {
+
+	if err = tx.Exec("DELETE FROM "+table+" WHERE ProspectID = ?", prospectID).Error; err != nil {
+		return
+	}
+
+	if len(tokens) == 0 {
+		return
+	}
+
+	values := make([]string, len(tokens))
+	args := make([]interface{}, 0, len(tokens)*2)
+
+	for i, token := range tokens {
+		values[i] = "(?, ?)"
+		args = append(args, prospectID, token)
+	}
+
+	err = tx.Exec("INSERT INTO "+table+" (ProspectID, bidx) VALUES "+strings.Join(values, ", "), args...).Error
+
+	return
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"los-kmb-api/shared/constant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeBlindIndex(t *testing.T) {
+
+	testcases := []struct {
+		name      string
+		normalize func(value string) string
+		value     string
+		expected  string
+	}{
+		{name: "id number separators", normalize: NormalizeIDNumber, value: " 3273.0101-0190 0001 ", expected: "3273010101900001"},
+		{name: "id number lowercase", normalize: NormalizeIDNumber, value: "a1234567b", expected: "A1234567B"},
+		{name: "phone country code", normalize: NormalizePhone, value: "+62 812-3456-7890", expected: "081234567890"},
+		{name: "phone without leading zero", normalize: NormalizePhone, value: "81234567890", expected: "081234567890"},
+		{name: "phone local", normalize: NormalizePhone, value: "(021) 555 0101", expected: "0215550101"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.normalize(tc.value))
+		})
+	}
+
+	assert.Equal(t, []string{"BUDI", "SANTOSO", "JR"}, NormalizeName(" budi  santoso, jr. "))
+}
+
+func TestBlindIndexTokens(t *testing.T) {
+
+	t.Setenv("BLIND_INDEX_KEY", "test-key")
+
+	index := NewCustomerBlindIndex("3273.0101.0190.0001", "Budi Santoso", "+62 812-3456-7890")
+
+	testcases := []struct {
+		name     string
+		tokens   []string
+		stored   []string
+		any      bool
+		expected bool
+	}{
+		{name: "name by word prefix", tokens: BlindIndexSearchTokens("san"), stored: index.NameTokens, expected: true},
+		{name: "name by whole words", tokens: BlindIndexSearchTokens("budi santoso"), stored: index.NameTokens, expected: true},
+		{name: "name by short word", tokens: BlindIndexSearchTokens("bu"), stored: index.NameTokens, expected: false},
+		{name: "name by word middle", tokens: BlindIndexSearchTokens("ntoso"), stored: index.NameTokens, expected: false},
+		{name: "id number by prefix", tokens: BlindIndexIDNumberSearchTokens("327301"), stored: index.NumberTokens, any: true, expected: true},
+		{name: "id number by suffix", tokens: BlindIndexIDNumberSearchTokens("900001"), stored: index.NumberTokens, any: true, expected: true},
+		{name: "id number by whole value", tokens: BlindIndexIDNumberSearchTokens("3273010101900001"), stored: index.NumberTokens, any: true, expected: true},
+		{name: "id number by middle", tokens: BlindIndexIDNumberSearchTokens("01019000"), stored: index.NumberTokens, any: true, expected: false},
+		{name: "phone by prefix", tokens: BlindIndexPhoneSearchTokens("0812345"), stored: index.NumberTokens, any: true, expected: true},
+		{name: "phone by prefix with country code", tokens: BlindIndexPhoneSearchTokens("62812345"), stored: index.NumberTokens, any: true, expected: true},
+		{name: "phone by suffix", tokens: BlindIndexPhoneSearchTokens("34567890"), stored: index.NumberTokens, any: true, expected: true},
+		{name: "phone token is not an id number token", tokens: BlindIndexIDNumberSearchTokens("081234"), stored: index.NumberTokens, any: true, expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			assert.NotEmpty(t, tc.tokens)
+
+			// a name matches on all of its words, a number on any of its readings
+			matched := !tc.any
+			for _, token := range tc.tokens {
+				if Contains(tc.stored, token) == tc.any {
+					matched = tc.any
+				}
+			}
+
+			assert.Equal(t, tc.expected, matched)
+		})
+	}
+
+	t.Run("token counts", func(t *testing.T) {
+		// BUD, BUDI and SAN up to SANTOSO
+		assert.Len(t, index.NameTokens, 7)
+		// 11 prefixes and 11 suffixes of the 16 digits, the whole value once, for the id number and likewise 13 for the phone
+		assert.Len(t, index.NumberTokens, 21+13)
+		assert.Len(t, index.IDNumber, constant.BLIND_INDEX_LENGTH)
+	})
+
+	t.Run("search below the minimum digits", func(t *testing.T) {
+		assert.Empty(t, BlindIndexIDNumberSearchTokens("32730"))
+		assert.Empty(t, BlindIndexPhoneSearchTokens("7890"))
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		t.Setenv("BLIND_INDEX_KEY", "")
+
+		assert.Empty(t, BlindIndexIDNumber("3273010101900001"))
+		assert.Empty(t, BlindIndexNameTokens("Budi Santoso"))
+		assert.Empty(t, BlindIndexPhoneSearchTokens("081234567890"))
+	})
+}
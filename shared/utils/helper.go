@@ -9,6 +9,7 @@ import (
 	"los-kmb-api/models/entity"
 	"los-kmb-api/models/request"
 	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
 	"math"
 	"math/rand"
 	"reflect"
@@ -648,3 +649,87 @@ func SanitizeString(str string) string {
 	p := bluemonday.UGCPolicy()
 	return p.Sanitize(str)
 }
+
+// BusinessMinutes counts the minutes between from and to that fall inside the business hours of a work day
+func BusinessMinutes(from, to time.Time, config response.DataSlaConfig) (minutes int) {
+
+	if !to.After(from) {
+		return
+	}
+
+	start, errStart := time.Parse("15:04", config.BusinessHourStart)
+	end, errEnd := time.Parse("15:04", config.BusinessHourEnd)
+	if errStart != nil || errEnd != nil {
+		start, _ = time.Parse("15:04", "08:00")
+		end, _ = time.Parse("15:04", "17:00")
+	}
+
+	workDays := config.WorkDays
+	if len(workDays) == 0 {
+		workDays = []int{int(time.Monday), int(time.Tuesday), int(time.Wednesday), int(time.Thursday), int(time.Friday)}
+	}
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+
+	for !day.After(to) {
+		isWorkDay, _ := ItemExists(int(day.Weekday()), workDays)
+
+		if isWorkDay && !Contains(config.Holidays, day.Format("2006-01-02")) {
+			openAt := day.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+			closeAt := day.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute)
+
+			if from.After(openAt) {
+				openAt = from
+			}
+			if to.Before(closeAt) {
+				closeAt = to
+			}
+			if closeAt.After(openAt) {
+				minutes += int(closeAt.Sub(openAt).Minutes())
+			}
+		}
+
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return
+}
+
+// SlaAging measures how long an order waits in its stage against the sla of the stage, tracked is false when the stage has no sla
+func SlaAging(stage string, enteredAt, now time.Time, config response.DataSlaConfig) (aging entity.SlaAging, tracked bool) {
+
+	sla, tracked := config.Stages[stage]
+	if !tracked || enteredAt.IsZero() {
+		return aging, false
+	}
+
+	aging = entity.SlaAging{
+		Stage:          stage,
+		StageEnteredAt: enteredAt.Format("2006-01-02 15:04:05"),
+		AgingMinutes:   BusinessMinutes(enteredAt, now, config),
+		SlaMinutes:     sla,
+	}
+
+	aging.IsBreached = aging.AgingMinutes > aging.SlaMinutes
+
+	return
+}
+
+// QuotaPeriodStart returns the start of the quota deviasi period that contains now, weeks start on monday
+func QuotaPeriodStart(now time.Time, period string) (start time.Time, ok bool) {
+
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case constant.QUOTA_PERIOD_DAILY:
+		return day, true
+	case constant.QUOTA_PERIOD_WEEKLY:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), true
+	case constant.QUOTA_PERIOD_MONTHLY:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), true
+	case constant.QUOTA_PERIOD_QUARTERLY:
+		return time.Date(now.Year(), now.Month()-(now.Month()-1)%3, 1, 0, 0, 0, 0, now.Location()), true
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"los-kmb-api/models/response"
+	"los-kmb-api/shared/constant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessMinutes(t *testing.T) {
+
+	config := response.DataSlaConfig{
+		BusinessHourStart: "08:00",
+		BusinessHourEnd:   "17:00",
+		WorkDays:          []int{1, 2, 3, 4, 5},
+		Holidays:          []string{"2025-08-18"},
+	}
+
+	workDays := config
+	workDays.Holidays = nil
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 8, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	testcases := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		config   response.DataSlaConfig
+		expected int
+	}{
+		{name: "within the same day", from: at(14, 9, 0), to: at(14, 10, 30), config: config, expected: 90},
+		{name: "before opening", from: at(14, 6, 0), to: at(14, 8, 45), config: config, expected: 45},
+		{name: "after closing", from: at(14, 16, 30), to: at(14, 20, 0), config: config, expected: 30},
+		{name: "overnight", from: at(14, 16, 0), to: at(15, 9, 0), config: config, expected: 120},
+		{name: "over the weekend", from: at(15, 16, 0), to: at(18, 9, 0), config: workDays, expected: 120},
+		{name: "over the weekend and the holiday", from: at(15, 16, 0), to: at(19, 9, 0), config: config, expected: 120},
+		{name: "whole work day", from: at(14, 0, 0), to: at(15, 0, 0), config: config, expected: 540},
+		{name: "on a saturday", from: at(16, 9, 0), to: at(16, 12, 0), config: config, expected: 0},
+		{name: "to before from", from: at(14, 12, 0), to: at(14, 9, 0), config: config, expected: 0},
+		{name: "default hours and days", from: at(14, 7, 0), to: at(14, 18, 0), config: response.DataSlaConfig{}, expected: 540},
+		{name: "invalid hours fall back to default", from: at(14, 7, 0), to: at(14, 18, 0), config: response.DataSlaConfig{BusinessHourStart: "8", BusinessHourEnd: "17"}, expected: 540},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, BusinessMinutes(tc.from, tc.to, tc.config))
+		})
+	}
+}
+
+func TestQuotaPeriodStart(t *testing.T) {
+
+	// thursday 14 august 2025
+	now := time.Date(2025, 8, 14, 15, 30, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name     string
+		now      time.Time
+		period   string
+		expected time.Time
+		ok       bool
+	}{
+		{name: "daily", now: now, period: constant.QUOTA_PERIOD_DAILY, expected: time.Date(2025, 8, 14, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "weekly starts on monday", now: now, period: constant.QUOTA_PERIOD_WEEKLY, expected: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "weekly on monday", now: time.Date(2025, 8, 11, 8, 0, 0, 0, time.UTC), period: constant.QUOTA_PERIOD_WEEKLY, expected: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "weekly on sunday", now: time.Date(2025, 8, 17, 23, 59, 0, 0, time.UTC), period: constant.QUOTA_PERIOD_WEEKLY, expected: time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "weekly across the month", now: time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC), period: constant.QUOTA_PERIOD_WEEKLY, expected: time.Date(2025, 9, 29, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "monthly", now: now, period: constant.QUOTA_PERIOD_MONTHLY, expected: time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "quarterly first month", now: time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC), period: constant.QUOTA_PERIOD_QUARTERLY, expected: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "quarterly last month", now: time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), period: constant.QUOTA_PERIOD_QUARTERLY, expected: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "quarterly", now: now, period: constant.QUOTA_PERIOD_QUARTERLY, expected: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), ok: true},
+		{name: "unknown period", now: now, period: "YEARLY"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			start, ok := QuotaPeriodStart(tc.now, tc.period)
+
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, start)
+		})
+	}
+}
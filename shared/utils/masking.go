@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"los-kmb-api/shared/constant"
+	"os"
+	"strings"
+)
+
+// PIIFullAccess reports whether the role is allowed to see the personal data of the customer unmasked,
+// the roles are taken from PII_FULL_ACCESS_ROLES as a comma separated list
+func PIIFullAccess(role string) bool {
+
+	roles := os.Getenv("PII_FULL_ACCESS_ROLES")
+	if roles == "" {
+		roles = constant.PII_DEFAULT_FULL_ACCESS_ROLES
+	}
+
+	for _, r := range strings.Split(roles, ",") {
+		if strings.EqualFold(strings.TrimSpace(r), strings.TrimSpace(role)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaskIDNumber keeps the region and the last digits of the NIK, e.g. 327301******0001
+func MaskIDNumber(value string) string {
+	return maskMiddle(value, constant.PII_MASK_ID_NUMBER_PREFIX, constant.PII_MASK_ID_NUMBER_SUFFIX)
+}
+
+// MaskPhone keeps the operator prefix and the last digits of a phone number, e.g. 0812*****789
+func MaskPhone(value string) string {
+	return maskMiddle(value, constant.PII_MASK_PHONE_PREFIX, constant.PII_MASK_PHONE_SUFFIX)
+}
+
+// MaskValue hides the whole value without revealing its length
+func MaskValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return value
+	}
+	return constant.PII_MASK_VALUE
+}
+
+func maskMiddle(value string, prefix, suffix int) string {
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return value
+	}
+
+	runes := []rune(value)
+	if len(runes) <= prefix+suffix {
+		if len(runes) <= suffix {
+			return strings.Repeat("*", len(runes))
+		}
+		return strings.Repeat("*", len(runes)-suffix) + string(runes[len(runes)-suffix:])
+	}
+
+	return string(runes[:prefix]) + strings.Repeat("*", len(runes)-prefix-suffix) + string(runes[len(runes)-suffix:])
+}
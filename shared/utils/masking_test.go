@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskMiddle(t *testing.T) {
+
+	testcases := []struct {
+		name     string
+		value    string
+		prefix   int
+		suffix   int
+		expected string
+	}{
+		{name: "id number", value: "3273010101900001", prefix: 6, suffix: 4, expected: "327301******0001"},
+		{name: "phone", value: "081234567890", prefix: 4, suffix: 3, expected: "0812*****890"},
+		{name: "trimmed", value: "  081234567890 ", prefix: 4, suffix: 3, expected: "0812*****890"},
+		{name: "empty", value: "", prefix: 4, suffix: 3, expected: ""},
+		{name: "blank", value: "   ", prefix: 4, suffix: 3, expected: ""},
+		{name: "as long as prefix and suffix", value: "0812345", prefix: 4, suffix: 3, expected: "****345"},
+		{name: "shorter than prefix and suffix", value: "08123", prefix: 4, suffix: 3, expected: "**123"},
+		{name: "as long as suffix", value: "081", prefix: 4, suffix: 3, expected: "***"},
+		{name: "shorter than suffix", value: "08", prefix: 4, suffix: 3, expected: "**"},
+		{name: "multibyte", value: "ÁÉÍÓÚÀÈÌ", prefix: 2, suffix: 2, expected: "ÁÉ****ÈÌ"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, maskMiddle(tc.value, tc.prefix, tc.suffix))
+		})
+	}
+}
+
+func TestPIIFullAccess(t *testing.T) {
+
+	testcases := []struct {
+		name     string
+		roles    string
+		role     string
+		expected bool
+	}{
+		{name: "default role", roles: "", role: "CA", expected: true},
+		{name: "default other role", roles: "", role: "CMO", expected: false},
+		{name: "configured roles", roles: "CA, SPV ,ADMIN", role: "spv", expected: true},
+		{name: "not configured", roles: "CA,SPV", role: "CBM", expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("PII_FULL_ACCESS_ROLES", tc.roles)
+
+			assert.Equal(t, tc.expected, PIIFullAccess(tc.role))
+		})
+	}
+}